@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/audit"
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Manages the audit log of secret access and config changes",
+	Long: `Every decoded secret, config change, rendered file and executed command is recorded in the audit log.
+The audit log is configured in the global config using audit.sink: file, syslog or command.
+The file sink writes to audit.file (~/.git-secrets-audit.jsonl by default), the command sink passes each event as json to the stdin of audit.command.
+The values of secrets and the arguments of commands are never recorded.`,
+	Example: `
+git secrets audit verify: Verifies the hash chain of the audit log file
+git secrets audit verify /var/log/git-secrets.jsonl: Verifies the hash chain of another audit log file
+`,
+}
+
+// auditVerifyCmd represents the audit verify command
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Verifies that no event of the audit log file has been modified, removed or reordered",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var auditFile string
+		if len(args) > 0 {
+			auditFile = args[0]
+		} else if globalCfg != nil {
+			auditFile = globalCfg.GetAuditConfig().File
+		}
+
+		fileSink, errSink := audit.NewFileSink(fs, auditFile)
+		cobra.CheckErr(errSink)
+
+		file, errOpen := fs.Open(fileSink.Path())
+		if errOpen != nil {
+			cobra.CheckErr(fmt.Errorf("could not open the audit log: %s", errOpen.Error()))
+		}
+		defer file.Close()
+
+		verified, errVerify := audit.Verify(file)
+		if errVerify != nil {
+			cobra.CheckErr(fmt.Errorf("the audit log %s has been tampered with after %d valid events: %s", fileSink.Path(), verified, errVerify.Error()))
+		}
+
+		fmt.Printf("%d events of %s verified\n", verified, fileSink.Path())
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+}
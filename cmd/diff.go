@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/diff"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+const FlagContext = "context"
+const FlagRev = "rev"
+
+const DiffFormatText = "text"
+const DiffFormatJson = "json"
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Lists the added, removed and changed keys between contexts or git revisions",
+	Long: `Lists the added, removed and changed keys between contexts or git revisions.
+Secrets are compared by the fingerprints of their values, the values are never printed.
+Without --rev the working tree is used, a single revision is compared with the working tree.
+Without --context all contexts are compared by the keys they define, with --context the resolved values of the contexts are compared.`,
+	Example: `
+git secrets diff: Lists the changes of the working tree compared to HEAD
+git secrets diff --context default..prod: Lists the keys prod overrides compared to default
+git secrets diff --context prod: Lists the keys prod overrides compared to the context it extends
+git secrets diff --rev v1.4..main: Lists the changes between two revisions
+git secrets diff --rev v1.4 --context prod: Lists the changes of the resolved prod values since v1.4
+git secrets diff --rev main --format json: Prints the changes as json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		contextRange, _ := cmd.Flags().GetString(FlagContext)
+		revRange, _ := cmd.Flags().GetString(FlagRev)
+		format, _ := cmd.Flags().GetString(FlagFormat)
+
+		if format != DiffFormatText && format != DiffFormatJson {
+			cobra.CheckErr(fmt.Errorf("unsupported format %s, use %s or %s", format, DiffFormatText, DiffFormatJson))
+		}
+
+		// the working tree is compared with HEAD if nothing is passed
+		if contextRange == "" && revRange == "" {
+			revRange = "HEAD"
+		}
+
+		beforeRev, afterRev := splitRange(revRange)
+		before, errBefore := loadRepository(beforeRev)
+		cobra.CheckErr(errBefore)
+		after, errAfter := loadRepository(afterRev)
+		cobra.CheckErr(errAfter)
+
+		var changes []*diff.Change
+		if contextRange == "" {
+			changes = diff.CompareRepositories(before, after)
+		} else {
+			beforeContext, afterContext := splitRange(contextRange)
+			if afterContext == "" {
+				afterContext = beforeContext
+				// a single context without revisions is compared with the context it extends
+				if revRange == "" {
+					context := after.GetContext(afterContext)
+					if context == nil {
+						cobra.CheckErr(fmt.Errorf("the context %s does not exist", afterContext))
+					}
+					if context.Parent == nil {
+						cobra.CheckErr(fmt.Errorf("the context %s does not extend another context, use --context a..b", afterContext))
+					}
+					beforeContext = context.Parent.Name
+				}
+			}
+			contextChanges, errCompare := diff.CompareContexts(before, beforeContext, after, afterContext)
+			cobra.CheckErr(errCompare)
+			changes = contextChanges
+		}
+
+		if format == DiffFormatJson {
+			encoded, errFormat := diff.FormatJson(changes)
+			cobra.CheckErr(errFormat)
+			_, _ = os.Stdout.Write(encoded)
+			return
+		}
+
+		_, _ = os.Stdout.Write(diff.FormatText(changes))
+
+	},
+}
+
+// splitRange splits a range a..b into its parts, b is empty if no range is passed
+func splitRange(value string) (string, string) {
+	parts := strings.SplitN(value, "..", 2)
+	if len(parts) < 2 {
+		return value, ""
+	}
+	return parts[0], parts[1]
+}
+
+// loadRepository loads the config file of the git revision, the working tree is used if the revision is empty
+// the keys of a revision are resolved by the working tree config, so the revision can not run commands
+func loadRepository(revision string) (*config_generic.Repository, error) {
+	if revision == "" {
+		return projectCfg, projectCfgError
+	}
+	return diff.LoadRevision(revision, projectCfgFile, projectCfg)
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	// overrides the global context flag since diff accepts a range of contexts
+	diffCmd.Flags().StringP(FlagContext, "c", "", "The contexts to compare: --context a..b, a single context is compared with the context it extends")
+	diffCmd.Flags().String(FlagRev, "", "The git revisions to compare: --rev r1..r2, a single revision is compared with the working tree")
+	diffCmd.Flags().String(FlagFormat, DiffFormatText, "Output format: text or json")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/diff"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// DiffDriverName is the name of the diff driver in the git config and the .gitattributes file
+const DiffDriverName = "git-secrets"
+
+const FlagReveal = "reveal"
+
+// diffTextCmd represents the diff-text command
+var diffTextCmd = &cobra.Command{
+	Use:   "diff-text <file>",
+	Short: "Prints a normalized view of a config file which is used by git to diff it",
+	Long: `Prints every context, config entry, secret and render file of the config file on its own line.
+Secrets are masked and printed with a fingerprint of their value, so re-encrypting a secret does not show up as change.
+The plain values are printed if --reveal is set and the global secrets are available.
+The keys are resolved by the contexts of the working tree config, the decryptSecret of the passed file is never used.
+Run "git secrets diff-text install" to use it for git diff.`,
+	Example: `
+git secrets diff-text install: Registers the diff driver in the git config and the .gitattributes file
+git secrets diff-text .git-secrets.json: Prints the normalized view of the config file
+git secrets diff-text .git-secrets.json --reveal: Prints the plain values of the secrets
+git -c diff.git-secrets.textconv="git secrets diff-text --reveal" diff: Shows the plain values in git diff
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		reveal, _ := cmd.Flags().GetBool(FlagReveal)
+
+		contents, errRead := afero.ReadFile(fs, args[0])
+		if errRead != nil {
+			cobra.CheckErr(fmt.Errorf("could not read %s: %s", args[0], errRead.Error()))
+		}
+
+		// git passes historic versions of the config file, their keys are only resolved by the working tree config
+		// git also diffs broken versions of the config file, so they are printed as they are
+		repository, errParse := config_generic.ParseUntrustedDocument(args[0], contents, projectCfg)
+		if errParse != nil {
+			fmt.Fprintf(os.Stderr, "could not parse %s, printing it as it is: %s\n", args[0], errParse.Error())
+			_, _ = os.Stdout.Write(contents)
+			return
+		}
+
+		_, _ = os.Stdout.Write(diff.TextConv(repository, reveal))
+
+	},
+}
+
+// diffTextInstallCmd represents the diff-text install command
+var diffTextInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Registers the diff driver in the git config and the .gitattributes file",
+	Example: `
+git secrets diff-text install: Uses the diff driver for the config file and all included files
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		gitRoot, errRoot := utility.GetGitRoot("")
+		cobra.CheckErr(errRoot)
+
+		cobra.CheckErr(utility.SetGitConfig(fmt.Sprintf("diff.%s.textconv", DiffDriverName), "git secrets diff-text"))
+
+		addedLines, errAdd := utility.AddGitAttributes(gitRoot, configFilePatterns(gitRoot), fmt.Sprintf("diff=%s", DiffDriverName))
+		cobra.CheckErr(errAdd)
+
+		fmt.Printf("Registered the diff driver %s in the git config\n", DiffDriverName)
+		for _, addedLine := range addedLines {
+			fmt.Printf("Added to .gitattributes: %s\n", addedLine)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffTextCmd)
+	diffTextCmd.AddCommand(diffTextInstallCmd)
+	diffTextCmd.Flags().Bool(FlagReveal, false, "Prints the plain values of the secrets if the global secrets are available")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/doctor"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/spf13/cobra"
+	"os"
+	"time"
+)
+
+const FlagWarnDays = "warn-days"
+const FlagStrict = "strict"
+
+const DoctorFormatText = "text"
+const DoctorFormatJson = "json"
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks the config file, the encryption keys, the secrets and the render targets",
+	Long: `Checks the whole setup and reports errors and warnings:
+the schema of the config files, contexts whose encryption key can not be resolved, keys shorter than 32 bytes,
+secrets which can not be decrypted, expired entries, secrets which need to be rotated,
+render targets without templates and rendered files which are not ignored by git.
+Exits with a non-zero exit code if an error is found, with --strict also if a warning is found.`,
+	Example: `
+git secrets doctor: Checks the setup
+git secrets doctor --warn-days 14: Warns about entries expiring or secrets to rotate within the next 14 days
+git secrets doctor --format json --strict: Prints the report as json and fails on warnings, e.g. in CI
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		format, _ := cmd.Flags().GetString(FlagFormat)
+		warnDays, _ := cmd.Flags().GetInt(FlagWarnDays)
+		strict, _ := cmd.Flags().GetBool(FlagStrict)
+
+		if format != DoctorFormatText && format != DoctorFormatJson {
+			cobra.CheckErr(fmt.Errorf("unsupported format %s, use %s or %s", format, DoctorFormatText, DoctorFormatJson))
+		}
+
+		report := doctor.Check(projectCfg, projectCfgError, &doctor.Options{
+			Now:        time.Now(),
+			WarnBefore: time.Duration(warnDays) * 24 * time.Hour,
+			Fs:         fs,
+			IsIgnored:  utility.IsGitIgnored,
+		})
+
+		if format == DoctorFormatJson {
+			encoded, errFormat := report.FormatJson()
+			cobra.CheckErr(errFormat)
+			_, _ = os.Stdout.Write(encoded)
+		} else {
+			_, _ = os.Stdout.Write(report.FormatText())
+		}
+
+		if report.HasProblems(strict) {
+			cobra.CheckErr(fmt.Errorf("the doctor found %d errors and %d warnings", report.Errors, report.Warnings))
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().String(FlagFormat, DoctorFormatText, "Output format: text or json")
+	doctorCmd.Flags().Int(FlagWarnDays, 30, "Warns about entries expiring or secrets to rotate within this number of days")
+	doctorCmd.Flags().Bool(FlagStrict, false, "Exits with a non-zero exit code on warnings as well")
+}
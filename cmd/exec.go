@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/environment"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const FlagPrefix = "prefix"
+const FlagCase = "case"
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "Execute a command with the decoded secrets and configs as environment variables",
+	Example: `
+git secrets exec -- <command>: Executes the command using the default context
+git secrets exec -c prod -- ./my-binary --flag: Executes the command using the prod context
+git secrets exec --prefix APP_ -- env: Prefixes all variable names: databasePassword becomes APP_DATABASE_PASSWORD
+git secrets exec --case none -- env: Keeps the keys as they are: databasePassword stays databasePassword
+`,
+	Args: cobra.MinimumNArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		execConfig := projectCfg.GetExecConfig()
+
+		prefix := execConfig.Prefix
+		if cmd.Flags().Changed(FlagPrefix) {
+			prefix, _ = cmd.Flags().GetString(FlagPrefix)
+		}
+
+		caseTransform := execConfig.Case
+		if cmd.Flags().Changed(FlagCase) {
+			caseTransform, _ = cmd.Flags().GetString(FlagCase)
+		}
+
+		mapper, errMapper := environment.NewMapper(prefix, environment.CaseTransform(caseTransform), execConfig.Env)
+		cobra.CheckErr(errMapper)
+
+		secretsMap, errSecrets := projectCfg.GetSecretsMapDecoded()
+		cobra.CheckErr(errSecrets)
+
+		env, errMap := mapper.Map(secretsMap, projectCfg.GetConfigMap())
+		cobra.CheckErr(errMap)
+
+		if auditLogger != nil {
+			auditLogger.RecordExec(projectCfg.GetCurrent().Name, args, nil)
+		}
+
+		exitCode, errRun := environment.Run(args, environment.MergeEnv(os.Environ(), env), os.Stdin, os.Stdout, os.Stderr)
+		if errRun != nil {
+			fmt.Fprintln(os.Stderr, "Error:", errRun.Error())
+		}
+
+		os.Exit(exitCode)
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().String(FlagPrefix, "", "Prefix of every environment variable name: --prefix APP_")
+	execCmd.Flags().String(FlagCase, string(environment.CaseUpperSnake), "Case transform of the keys: upper-snake, snake, upper, lower or none")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/export"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+const FlagFormat = "format"
+const FlagOnly = "only"
+const FlagKeys = "keys"
+const FlagOutput = "output"
+const FlagName = "name"
+const FlagNamespace = "namespace"
+
+const OnlySecrets = "secrets"
+const OnlyConfigs = "configs"
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the decoded secrets and configs in dotenv, json, yaml, shell or k8s-secret format",
+	Example: `
+git secrets export: Prints all secrets and configs of the default context in dotenv format
+git secrets export --format json -c prod: Prints all secrets and configs of the prod context as json
+git secrets export --only secrets --keys databasePassword,apiKey: Only exports the given secrets
+git secrets export --format shell --output .secrets.sh: Writes the export to a file which is only readable by you
+git secrets export --format k8s-secret --name my-secret --namespace my-namespace: Prints a kubernetes secret manifest
+`,
+	Args: cobra.NoArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		format, _ := cmd.Flags().GetString(FlagFormat)
+		only, _ := cmd.Flags().GetString(FlagOnly)
+		keys, _ := cmd.Flags().GetStringSlice(FlagKeys)
+		outputFile, _ := cmd.Flags().GetString(FlagOutput)
+		name, _ := cmd.Flags().GetString(FlagName)
+		namespace, _ := cmd.Flags().GetString(FlagNamespace)
+
+		if only != "" && only != OnlySecrets && only != OnlyConfigs {
+			cobra.CheckErr(fmt.Errorf("--only must be either %s or %s", OnlySecrets, OnlyConfigs))
+		}
+
+		values := make(map[string]string)
+
+		if only != OnlySecrets {
+			for configKey, configValue := range projectCfg.GetConfigMap() {
+				values[configKey] = configValue
+			}
+		}
+
+		if only != OnlyConfigs {
+			secretsMap, errSecrets := projectCfg.GetSecretsMapDecoded()
+			cobra.CheckErr(errSecrets)
+			for secretKey, secretValue := range secretsMap {
+				if _, exists := values[secretKey]; exists {
+					cobra.CheckErr(fmt.Errorf("the key %s is defined as secret and config, use --only %s or --only %s", secretKey, OnlySecrets, OnlyConfigs))
+				}
+				values[secretKey] = secretValue
+			}
+		}
+
+		if len(keys) > 0 {
+			filteredValues := make(map[string]string)
+			for _, key := range keys {
+				key = strings.TrimSpace(key)
+				value, exists := values[key]
+				if !exists {
+					cobra.CheckErr(fmt.Errorf("the key %s does not exist on context %s", key, projectCfg.GetCurrent().Name))
+				}
+				filteredValues[key] = value
+			}
+			values = filteredValues
+		}
+
+		exported, errExport := export.Export(export.Format(format), values, &export.Options{
+			Name:      name,
+			Namespace: namespace,
+		})
+		cobra.CheckErr(errExport)
+
+		if outputFile == "" {
+			fmt.Print(string(exported))
+			return
+		}
+
+		cobra.CheckErr(export.WriteFile(fs, outputFile, exported))
+		fmt.Println(outputFile, "written")
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	var formats []string
+	for _, format := range export.Formats() {
+		formats = append(formats, string(format))
+	}
+
+	exportCmd.Flags().String(FlagFormat, string(export.FormatDotenv), fmt.Sprintf("Export format: %s", strings.Join(formats, ", ")))
+	exportCmd.Flags().String(FlagOnly, "", "Only export secrets or configs: --only secrets or --only configs")
+	exportCmd.Flags().StringSlice(FlagKeys, []string{}, "Only export the given keys: --keys databasePassword,databaseHost")
+	exportCmd.Flags().StringP(FlagOutput, "o", "", "Write the export to a file with restrictive permissions instead of stdout")
+	exportCmd.Flags().String(FlagName, "", "Name of the kubernetes secret when using --format k8s-secret")
+	exportCmd.Flags().String(FlagNamespace, "", "Namespace of the kubernetes secret when using --format k8s-secret")
+}
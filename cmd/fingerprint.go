@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const FlagGlobal = "global"
+
+// fingerprintCmd represents the fingerprint command
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint",
+	Short: "Print the key fingerprints of the contexts or global secrets without revealing the secrets",
+	Example: `
+git secrets fingerprint: prints the key fingerprint of every context and the pinned fingerprint
+git secrets fingerprint -c prod --secret gitSecretsProd=$PROD_SECRET: prints the key fingerprint of the prod context only
+git secrets fingerprint --global mySecret: prints the key fingerprint of the global secret to compare it with a teammate
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		globalSecretKey, _ := cmd.Flags().GetString(FlagGlobal)
+
+		if globalSecretKey != "" {
+			secretValue := globalCfg.GetSecret(globalSecretKey)
+			if secretValue == "" {
+				cobra.CheckErr(fmt.Errorf("the secret %s does not exist", globalSecretKey))
+			}
+			fmt.Println(encryption.KeyFingerprint([]byte(secretValue)))
+			return
+		}
+
+		cobra.CheckErr(projectCfgError)
+
+		if cmd.Flags().Changed(FlagContext) {
+			keyFingerprint, errFingerprint := projectCfg.GetCurrent().KeyFingerprint()
+			cobra.CheckErr(errFingerprint)
+			fmt.Println(keyFingerprint)
+			return
+		}
+
+		var tableData [][]string
+		for _, context := range projectCfg.GetContexts() {
+			tableData = append(tableData, []string{context.Name, keyFingerprintStatus(context)})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Context", "Key Fingerprint"})
+		table.SetBorder(false)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(tableData)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.Render()
+
+	},
+}
+
+// keyFingerprintStatus returns the key fingerprint of the context and whether it matches the pinned fingerprint
+func keyFingerprintStatus(context *config_generic.Context) string {
+	keyFingerprint, errFingerprint := context.KeyFingerprint()
+	if errFingerprint != nil {
+		return fmt.Sprintf("unavailable: %s", errFingerprint.Error())
+	}
+	pinnedFingerprint := context.PinnedKeyFingerprint()
+	switch {
+	case pinnedFingerprint == "":
+		return keyFingerprint
+	case pinnedFingerprint == keyFingerprint:
+		return fmt.Sprintf("%s (pinned)", keyFingerprint)
+	default:
+		return fmt.Sprintf("%s (key mismatch, %s is pinned)", keyFingerprint, pinnedFingerprint)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(fingerprintCmd)
+	fingerprintCmd.Flags().String(FlagGlobal, "", "Print the key fingerprint of the global secret instead")
+}
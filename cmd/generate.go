@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate resources like global-secret",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// generateGlobalSecretCmd represents the generateGlobalSecret command
+var generateGlobalSecretCmd = &cobra.Command{
+	Use:   "global-secret",
+	Short: "Generate a random 32 byte global secret and write it to the global configuration",
+	Example: `
+git secrets generate global-secret <secretKey>: generates the secret without showing it
+git secrets generate global-secret <secretKey> --force: replaces an existing secret, the values encoded using the old secret can not be decoded anymore
+`,
+	Aliases: []string{"gs"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		isForce, _ := cmd.Flags().GetBool(FlagForce)
+		secretKey := args[0]
+
+		errGenerate := globalCfg.GenerateSecret(secretKey, isForce)
+		if errGenerate != nil {
+			cobra.CheckErr(fmt.Errorf("could not write config: %s", errGenerate.Error()))
+		}
+
+		fmt.Printf("%s generated\n", secretKey)
+		fmt.Printf("Share it with your team: git secrets export global-secret %s --to-passphrase\n", secretKey)
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.AddCommand(generateGlobalSecretCmd)
+	generateGlobalSecretCmd.Flags().Bool(FlagForce, false, "Force overwrite existing secret: You may loose your master password!")
+}
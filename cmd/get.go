@@ -30,7 +30,7 @@ git secrets get config <configKey> -c prod
 		configKey := args[0]
 		configEntry := projectCfg.GetCurrentConfig(configKey)
 		if configEntry == nil {
-			cobra.CheckErr(fmt.Errorf("the config entry %s does not exist on context %s", configKey, selectedContext.Name))
+			cobra.CheckErr(fmt.Errorf("the config entry %s does not exist on context %s", configKey, projectCfg.GetCurrent().Name))
 		}
 		fmt.Println(configEntry.Value)
 	},
@@ -52,7 +52,7 @@ git secrets get secret <secretName> -c prod
 		secretKey := args[0]
 		secretEntry := projectCfg.GetCurrentSecret(secretKey)
 		if secretEntry == nil {
-			cobra.CheckErr(fmt.Errorf("the secret %s does not exist on context %s", secretKey, selectedContext.Name))
+			cobra.CheckErr(fmt.Errorf("the secret %s does not exist on context %s", secretKey, projectCfg.GetCurrent().Name))
 		}
 		decodedValue, errDecode := secretEntry.Decode()
 		if errDecode != nil {
@@ -1,17 +1,29 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/benammann/git-secrets/pkg/armor"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/benammann/git-secrets/pkg/passphrase"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
 )
 
+const FlagToPassphrase = "to-passphrase"
+const FlagPassphrase = "passphrase"
+
 // getGlobalSecretsCmd represents the globalSecrets command
 var getGlobalSecretsCmd = &cobra.Command{
 	Use:   "global-secret",
 	Short: "Get or list a secret from the global configuration",
 	Example: `
-git secrets get global-secrets: get all global secret keys
+git secrets get global-secrets: get all global secret keys and their fingerprints
 git secrets get global-secret <secretKey>: prints the global secret value
 `,
 	Aliases: []string{"global-secrets", "gs"},
@@ -26,10 +38,16 @@ git secrets get global-secret <secretKey>: prints the global secret value
 				cobra.CheckErr(fmt.Errorf("the secret %s does not exist", secretName))
 			}
 		} else {
-			secretKeys := globalCfg.GetSecretKeys()
-			for _, secretKey := range secretKeys {
-				fmt.Println(secretKey)
+			var tableData [][]string
+			for _, secretKey := range globalCfg.GetSecretKeys() {
+				tableData = append(tableData, []string{secretKey, encryption.KeyFingerprint([]byte(globalCfg.GetSecret(secretKey)))})
 			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Secret Name", "Fingerprint"})
+			table.SetBorder(false)
+			table.AppendBulk(tableData)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.Render()
 		}
 	},
 }
@@ -67,9 +85,144 @@ git secrets set global-secret <secretKey> --value $MY_SECRET_VALUE_STORED_IN_ENV
 	},
 }
 
+// exportGlobalSecretCmd represents the exportGlobalSecret command
+var exportGlobalSecretCmd = &cobra.Command{
+	Use:   "global-secret",
+	Short: "Export a global secret as passphrase protected token to share it with a teammate",
+	Example: `
+git secrets export global-secret <secretKey> --to-passphrase: asks for a passphrase and prints the token
+git secrets export global-secret <secretKey> --to-passphrase > token.txt: writes the token to a file
+`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		toPassphrase, _ := cmd.Flags().GetBool(FlagToPassphrase)
+		secretPassphrase, _ := cmd.Flags().GetString(FlagPassphrase)
+		secretKey := args[0]
+
+		if !toPassphrase {
+			cobra.CheckErr(fmt.Errorf("global secrets can only be exported using --%s, use git secrets get global-secret %s to print the plain value", FlagToPassphrase, secretKey))
+		}
+
+		secretValue := globalCfg.GetSecret(secretKey)
+		if secretValue == "" {
+			cobra.CheckErr(fmt.Errorf("the secret %s does not exist", secretKey))
+		}
+
+		// the prompts are written to stderr, so the token can be redirected to a file
+		if secretPassphrase == "" {
+			stdio := survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)
+			errAsk := survey.AskOne(&survey.Password{
+				Message: fmt.Sprintf("Passphrase (at least %d characters)", passphrase.MinLength),
+			}, &secretPassphrase, stdio)
+			cobra.CheckErr(errAsk)
+			repeatedPassphrase := ""
+			errRepeat := survey.AskOne(&survey.Password{
+				Message: "Repeat the passphrase",
+			}, &repeatedPassphrase, stdio)
+			cobra.CheckErr(errRepeat)
+			if repeatedPassphrase != secretPassphrase {
+				cobra.CheckErr(fmt.Errorf("the passphrases do not match"))
+			}
+		}
+
+		token, errWrap := passphrase.Wrap(secretKey, secretValue, secretPassphrase, passphrase.DefaultParams)
+		cobra.CheckErr(errWrap)
+
+		fmt.Print(token)
+		fmt.Fprintf(os.Stderr, "Share the token and the passphrase using different channels, import it using: git secrets import global-secret\n")
+
+	},
+}
+
+// importGlobalSecretCmd represents the importGlobalSecret command
+var importGlobalSecretCmd = &cobra.Command{
+	Use:   "global-secret",
+	Short: "Import a global secret from a passphrase protected token",
+	Example: `
+git secrets import global-secret: paste the token and enter the passphrase
+git secrets import global-secret token.txt: reads the token from a file
+git secrets import global-secret token.txt --name otherName --force: imports the secret using another name and overwrites an existing secret
+`,
+	Args: cobra.RangeArgs(0, 1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		isForce, _ := cmd.Flags().GetBool(FlagForce)
+		secretPassphrase, _ := cmd.Flags().GetString(FlagPassphrase)
+		secretKey, _ := cmd.Flags().GetString(FlagName)
+
+		var token string
+		if len(args) == 1 {
+			fileContents, errRead := afero.ReadFile(fs, args[0])
+			if errRead != nil {
+				cobra.CheckErr(fmt.Errorf("could not read %s: %s", args[0], errRead.Error()))
+			}
+			token = string(fileContents)
+		} else {
+			fmt.Fprintln(os.Stderr, "Paste the token:")
+			readToken, errRead := readPassphraseToken(os.Stdin)
+			cobra.CheckErr(errRead)
+			token = readToken
+		}
+
+		if secretPassphrase == "" {
+			errAsk := survey.AskOne(&survey.Password{
+				Message: "Passphrase",
+			}, &secretPassphrase)
+			cobra.CheckErr(errAsk)
+		}
+
+		tokenName, secretValue, errUnwrap := passphrase.Unwrap(token, secretPassphrase)
+		cobra.CheckErr(errUnwrap)
+
+		if secretKey == "" {
+			secretKey = tokenName
+		}
+
+		errWrite := globalCfg.SetSecret(secretKey, secretValue, isForce)
+		if errWrite != nil {
+			cobra.CheckErr(fmt.Errorf("could not write config: %s", errWrite.Error()))
+		}
+
+		fmt.Printf("%s imported. Use git secrets get global-secret %s to get it's value\n", secretKey, secretKey)
+
+	},
+}
+
+// readPassphraseToken reads until the end of the token, so a pasted token does not need to be terminated using ctrl+d
+func readPassphraseToken(reader io.Reader) (string, error) {
+	var token strings.Builder
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		token.WriteString(scanner.Text() + "\n")
+		if strings.TrimSpace(scanner.Text()) == armor.End(passphrase.BlockType) {
+			break
+		}
+	}
+	if errScan := scanner.Err(); errScan != nil {
+		return "", fmt.Errorf("could not read the token: %s", errScan.Error())
+	}
+	return token.String(), nil
+}
+
 func init() {
 	getCmd.AddCommand(getGlobalSecretsCmd)
 	setCmd.AddCommand(setGlobalSecretsCmd)
+	exportCmd.AddCommand(exportGlobalSecretCmd)
+	importCmd.AddCommand(importGlobalSecretCmd)
+
+	exportGlobalSecretCmd.Flags().Bool(FlagToPassphrase, false, "Encrypt the secret using a passphrase, the passphrase is asked for interactively")
+	importGlobalSecretCmd.Flags().Bool(FlagForce, false, "Force overwrite existing secret: You may loose your master password!")
+	importGlobalSecretCmd.Flags().String(FlagName, "", "Import the secret using another name than the one stored in the token")
+	for _, cmd := range []*cobra.Command{exportGlobalSecretCmd, importGlobalSecretCmd} {
+		cmd.Flags().String(FlagPassphrase, "", "Pass the passphrase as parameter instead of password input (insecure)")
+	}
 
 	setGlobalSecretsCmd.Flags().Bool(FlagForce, false, "Force overwrite existing secret: You may loose your master password!")
 	setGlobalSecretsCmd.Flags().String(FlagValue, "", "Pass the secret's value as parameter instead of password input")
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/AlecAivazis/survey/v2"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	"github.com/benammann/git-secrets/pkg/config/writer"
+	"github.com/benammann/git-secrets/pkg/importer"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+const FlagAs = "as"
+const FlagYes = "yes"
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import secrets or configs from dotenv, json or yaml files",
+	Example: `
+git secrets import .env: Encodes all values of the .env file and adds them as secrets to the default context
+git secrets import .env -c prod --force: Imports the values to the prod context and overwrites existing secrets
+git secrets import config.yaml --as configs: Imports the values as config entries
+git secrets import .env --prefix APP_: Only imports keys starting with APP_ and removes the prefix
+git secrets import secrets.txt --format dotenv --dry-run: Only prints the summary without writing
+`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		fileName := args[0]
+		format, _ := cmd.Flags().GetString(FlagFormat)
+		importAs, _ := cmd.Flags().GetString(FlagAs)
+		prefix, _ := cmd.Flags().GetString(FlagPrefix)
+		force, _ := cmd.Flags().GetBool(FlagForce)
+		skipConfirm, _ := cmd.Flags().GetBool(FlagYes)
+		isDryRun, _ := cmd.Flags().GetBool(FlagDryRun)
+
+		if importAs != OnlySecrets && importAs != OnlyConfigs {
+			cobra.CheckErr(fmt.Errorf("--as must be either %s or %s", OnlySecrets, OnlyConfigs))
+		}
+
+		if format == "" {
+			detectedFormat, errDetect := importer.DetectFormat(fileName)
+			cobra.CheckErr(errDetect)
+			format = string(detectedFormat)
+		}
+
+		fileContents, errRead := afero.ReadFile(fs, fileName)
+		if errRead != nil {
+			cobra.CheckErr(fmt.Errorf("could not read %s: %s", fileName, errRead.Error()))
+		}
+
+		values, errParse := importer.Parse(importer.Format(format), fileContents)
+		cobra.CheckErr(errParse)
+
+		values = importer.StripPrefix(values, prefix)
+		if len(values) == 0 {
+			cobra.CheckErr(fmt.Errorf("%s does not contain any values to import", fileName))
+		}
+
+		contextName := projectCfg.GetCurrent().Name
+		isDefault := contextName == config_const.DefaultContextName
+
+		var existing importer.ExistingValue
+		var allowed importer.Allowed
+
+		if importAs == OnlySecrets {
+			existing = func(key string) (string, bool, error) {
+				for _, secret := range projectCfg.GetSecretsByContext(contextName) {
+					if secret.Name == key {
+						decodedValue, errDecode := secret.Decode()
+						return decodedValue, true, errDecode
+					}
+				}
+				return "", false, nil
+			}
+			allowed = func(key string, value string) error {
+				if existingSecret := projectCfg.GetCurrentSecret(key); existingSecret != nil {
+					return existingSecret.Metadata.ValidateValue(value)
+				}
+				if isDefault {
+					return nil
+				}
+				return fmt.Errorf("not defined in the default context")
+			}
+		} else {
+			existing = func(key string) (string, bool, error) {
+				for _, config := range projectCfg.GetConfigsByContext(contextName) {
+					if config.Name == key {
+						return config.Value, true, nil
+					}
+				}
+				return "", false, nil
+			}
+			allowed = func(key string, value string) error {
+				if existingConfig := projectCfg.GetCurrentConfig(key); existingConfig != nil {
+					return existingConfig.Metadata.ValidateValue(value)
+				}
+				if isDefault {
+					return nil
+				}
+				return fmt.Errorf("not defined in the default context")
+			}
+		}
+
+		plan, errPlan := importer.NewPlan(values, existing, allowed, force)
+		cobra.CheckErr(errPlan)
+
+		var tableData [][]string
+		for _, change := range plan.Changes {
+			tableData = append(tableData, []string{change.Key, string(change.Action), change.Reason})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Key", "Action", "Reason"})
+		table.SetBorder(false)
+		table.AppendBulk(tableData)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.Render()
+		fmt.Println()
+
+		fmt.Printf("Importing %s as %s to context %s: %d added, %d changed, %d skipped\n", fileName, importAs, contextName, plan.Count(importer.ActionAdd), plan.Count(importer.ActionChange), plan.Count(importer.ActionSkip))
+
+		if !plan.HasWrites() {
+			fmt.Println("Nothing to import")
+			return
+		}
+
+		if isDryRun {
+			fmt.Println("Dry run, nothing has been written")
+			return
+		}
+
+		if !skipConfirm {
+			confirmed := false
+			errAsk := survey.AskOne(&survey.Confirm{
+				Message: "Write the changes to the config file?",
+			}, &confirmed)
+			cobra.CheckErr(errAsk)
+			if !confirmed {
+				fmt.Println("Import aborted")
+				return
+			}
+		}
+
+		cobra.CheckErr(writeTransaction(func(configWriter writer.ConfigWriter) error {
+			if importAs != OnlySecrets {
+				return configWriter.SetConfigs(contextName, plan.Values(), force)
+			}
+			encodedValues := make(map[string]string)
+			for key, value := range plan.Values() {
+				encodedValue, errEncode := projectCfg.GetCurrent().EncodeValue(value)
+				if errEncode != nil {
+					return fmt.Errorf("could not encode %s: %s", key, errEncode.Error())
+				}
+				encodedValues[key] = encodedValue
+			}
+			return configWriter.SetSecrets(contextName, encodedValues, force)
+		}))
+
+		fmt.Printf("%d %s have been imported\n", len(plan.Values()), importAs)
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	var formats []string
+	for _, format := range importer.Formats() {
+		formats = append(formats, string(format))
+	}
+
+	importCmd.Flags().String(FlagFormat, "", fmt.Sprintf("Import format: %s, detected by the file name if not set", strings.Join(formats, ", ")))
+	importCmd.Flags().String(FlagAs, OnlySecrets, "Import the values as secrets or configs: --as secrets or --as configs")
+	importCmd.Flags().String(FlagPrefix, "", "Only import keys starting with the prefix and remove it: --prefix APP_")
+	importCmd.Flags().Bool(FlagForce, false, "Overwrite existing values")
+	importCmd.Flags().BoolP(FlagYes, "y", false, "Do not ask for confirmation before writing")
+	importCmd.Flags().Bool(FlagDryRun, false, "Only print the summary without writing")
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
 	"github.com/olekukonko/tablewriter"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -32,17 +34,56 @@ git secrets info -d -c prod: Decodes all secrets from the prod context
 		}
 
 		fmt.Printf("Config File: %s (Version: %d)\n", projectCfgFile, projectCfg.GetConfigVersion())
+		includedFiles := projectCfg.GetIncludedFiles()
+		if len(includedFiles) > 0 {
+			var relativeFiles []string
+			for _, includedFile := range includedFiles {
+				relativeFiles = append(relativeFiles, sourceFileName(includedFile))
+			}
+			fmt.Printf("Included Files: %s\n", strings.Join(relativeFiles, ", "))
+		}
 		fmt.Printf("Available Contexts: %s\n", strings.Join(allContextNames, ", "))
+		if !projectCfg.IsDefault() {
+			fmt.Printf("Context Chain: %s\n", strings.Join(projectCfg.GetCurrent().ChainNames(), " -> "))
+		}
+		fmt.Printf("Key Fingerprint: %s\n", keyFingerprintStatus(projectCfg.GetCurrent()))
 		fmt.Printf("Available Render Targets: %s\n", strings.Join(projectCfg.RenderTargetNames(), ", "))
 		fmt.Printf("\n")
 
+		currentConfigs := projectCfg.GetCurrentConfigs()
+		currentSecrets := projectCfg.GetCurrentSecrets()
+
+		// the metadata columns are only shown if at least one entry is annotated
+		showMetadata := false
+		for _, config := range currentConfigs {
+			showMetadata = showMetadata || !config.Metadata.IsEmpty()
+		}
+		for _, secret := range currentSecrets {
+			showMetadata = showMetadata || !secret.Metadata.IsEmpty()
+		}
+
+		// the source file column is only shown if the config includes other files
+		showSourceFile := len(includedFiles) > 0
+
 		configHeader := []string{"Config Key", "Config Value", "Origin Context"}
+		if showSourceFile {
+			configHeader = append(configHeader, "Source File")
+		}
+		if showMetadata {
+			configHeader = append(configHeader, metadataHeader...)
+		}
 
 		var configData [][]string
 
-		for _, config := range projectCfg.GetCurrentConfigs() {
+		for _, config := range currentConfigs {
 
 			tableRow := []string{config.Name, config.Value, config.OriginContext.Name}
+			if showSourceFile {
+				tableRow = append(tableRow, sourceFileName(config.SourceFile))
+			}
+			if showMetadata {
+				tableRow = append(tableRow, metadataColumns(config.Metadata)...)
+			}
 
 			configData = append(configData, tableRow)
 
@@ -62,15 +103,27 @@ git secrets info -d -c prod: Decodes all secrets from the prod context
 		shouldDecode, _ := cmd.Flags().GetBool(InfoCmdFlagDecode)
 
 		tableHeader := []string{"Secret Name", "Origin Context"}
+		if showSourceFile {
+			tableHeader = append(tableHeader, "Source File")
+		}
+		if showMetadata {
+			tableHeader = append(tableHeader, metadataHeader...)
+		}
 		if shouldDecode {
 			tableHeader = append(tableHeader, "Decoded Value")
 		}
 
 		var tableData [][]string
 
-		for _, secret := range projectCfg.GetCurrentSecrets() {
+		for _, secret := range currentSecrets {
 
 			tableRow := []string{secret.Name, secret.OriginContext.Name}
+			if showSourceFile {
+				tableRow = append(tableRow, sourceFileName(secret.SourceFile))
+			}
+			if showMetadata {
+				tableRow = append(tableRow, metadataColumns(secret.Metadata)...)
+			}
 			if shouldDecode {
 				decodedValue, errDecode := secret.Decode()
 				if errDecode != nil {
@@ -101,6 +154,22 @@ git secrets info -d -c prod: Decodes all secrets from the prod context
 	},
 }
 
+var metadataHeader = []string{"Type", "Description", "Owner", "Expires", "Rotate After", "Rotated"}
+
+// metadataColumns returns the table columns matching metadataHeader
+func metadataColumns(metadata config_generic.Metadata) []string {
+	return []string{string(metadata.Type), metadata.Description, metadata.Owner, metadata.ExpiresAt, metadata.RotateAfter, metadata.RotatedAt}
+}
+
+// sourceFileName returns the path of the config file relative to the used config file
+func sourceFileName(sourceFile string) string {
+	relativeFile, errRel := filepath.Rel(filepath.Dir(projectCfg.GetConfigFileUsed()), sourceFile)
+	if errRel != nil {
+		return sourceFile
+	}
+	return relativeFile
+}
+
 func init() {
 	rootCmd.AddCommand(infoCmd)
 	infoCmd.Flags().BoolP(InfoCmdFlagDecode, "d", false, "Adds the decoded secrets to the info table")
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/AlecAivazis/survey/v2"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
 	config_init "github.com/benammann/git-secrets/pkg/config/init"
 	"github.com/spf13/cobra"
 	"os"
-	"strings"
 )
 
 // initCmd represents the init command
@@ -16,13 +16,22 @@ var initCmd = &cobra.Command{
 	Short: "Initializes a new .git-secrets.json project",
 	Example: `
 git secrets init
-git secrets init -f custom-file-name.json
+git secrets init --format yaml: Initializes a .git-secrets.yaml project
+git secrets init --format toml: Initializes a .git-secrets.toml project
 `,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		if projectCfg != nil {
-			cobra.CheckErr(fmt.Errorf("can not initialize while having config file %s loaded. Please switch directories", projectCfgFile))
+		// config files of parent directories are loaded as well, a sub project of a monorepo gets its own config file
+		if workingDir, errWd := os.Getwd(); errWd == nil {
+			if existingConfigFile, found := findProjectConfigFile(workingDir, false); found {
+				cobra.CheckErr(fmt.Errorf("can not initialize since the config file %s already exists. Please switch directories", existingConfigFile))
+			}
+		}
+
+		format, _ := cmd.Flags().GetString(FlagFormat)
+		if !config_generic.IsFileFormat(format) {
+			cobra.CheckErr(fmt.Errorf("unsupported format %s, available: json, yaml, toml", format))
 		}
 
 		secretKeys := globalCfg.GetSecretKeys()
@@ -36,7 +45,7 @@ git secrets init -f custom-file-name.json
 				Name: "outputFile",
 				Prompt: &survey.Input{
 					Message: "Output file",
-					Default: ".git-secrets.json",
+					Default: config_generic.DefaultConfigFileName(config_generic.FileFormat(format)),
 				},
 				Validate: func(ans interface{}) error {
 
@@ -72,8 +81,8 @@ git secrets init -f custom-file-name.json
 			cobra.CheckErr(fmt.Errorf("could not ask survey: %s", errAsk.Error()))
 		}
 
-		if !strings.HasSuffix(questionResponse.OutputFile, ".json") {
-			cobra.CheckErr(fmt.Errorf("output file %s must have .json file ending", questionResponse.OutputFile))
+		if config_generic.DetectFileFormat(questionResponse.OutputFile) != config_generic.FileFormat(format) {
+			cobra.CheckErr(fmt.Errorf("output file %s must have a .%s file ending", questionResponse.OutputFile, format))
 		}
 
 		errWrite := config_init.WriteInitialConfig(fs, questionResponse.OutputFile, questionResponse.SecretName)
@@ -92,6 +101,7 @@ git secrets init -f custom-file-name.json
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().String(FlagFormat, string(config_generic.FileFormatJson), "format of the config file: json, yaml or toml")
 
 	// Here you will define your flags and configuration settings.
 
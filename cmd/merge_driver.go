@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/fatih/color"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"path/filepath"
+)
+
+// MergeDriverName is the name of the merge driver in the git config and the .gitattributes file
+const MergeDriverName = "git-secrets"
+
+// mergeDriverCmd represents the merge-driver command
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <current> <other> [path]",
+	Short: "Git merge driver which merges the config file key by key",
+	Long: `Merges the config file at the level of contexts, secrets, configs and render targets.
+The files of render targets and the includes are merged as sets.
+Only keys changed on both sides are reported as conflict, their lines are written between git conflict markers.
+Secrets encrypting the same value on both sides are no conflict if the keys of the working tree config are available.
+The merge driver is called by git, run "git secrets merge-driver install" to register it.`,
+	Example: `
+git secrets merge-driver install: Registers the merge driver in the git config and the .gitattributes file
+git secrets merge-driver %O %A %B %P: Merges the config file, this is called by git
+`,
+	Args: cobra.RangeArgs(3, 4),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var contents [3][]byte
+		for i, fileName := range args[:3] {
+			fileContents, errRead := afero.ReadFile(fs, fileName)
+			if errRead != nil {
+				cobra.CheckErr(fmt.Errorf("could not read %s: %s", fileName, errRead.Error()))
+			}
+			contents[i] = fileContents
+		}
+
+		// git passes temporary files, the path of the merged file is used to detect the format
+		configPath := args[1]
+		if len(args) > 3 {
+			configPath = args[3]
+		}
+		configPath, _ = filepath.Abs(configPath)
+
+		merged, conflicts, errMerge := config_generic.MergeConfigFiles(config_generic.DetectFileFormat(configPath), configPath, contents[0], contents[1], contents[2], projectCfg)
+		if errMerge != nil {
+			cobra.CheckErr(fmt.Errorf("could not merge %s: %s", configPath, errMerge.Error()))
+		}
+
+		if errWrite := afero.WriteFile(fs, args[1], merged, 0664); errWrite != nil {
+			cobra.CheckErr(fmt.Errorf("could not write %s: %s", args[1], errWrite.Error()))
+		}
+
+		unresolved := 0
+		for _, conflict := range conflicts {
+			if conflict.Resolved {
+				color.Green("resolved %s", conflict.String())
+				continue
+			}
+			unresolved++
+			color.Red("conflict %s", conflict.String())
+		}
+
+		if unresolved > 0 {
+			cobra.CheckErr(fmt.Errorf("%d conflicting keys in %s are written between conflict markers, resolve them and mark the file as resolved using git add", unresolved, configPath))
+		}
+
+	},
+}
+
+// mergeDriverInstallCmd represents the merge-driver install command
+var mergeDriverInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Registers the merge driver in the git config and the .gitattributes file",
+	Example: `
+git secrets merge-driver install: Uses the merge driver for the config file and all included files
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		gitRoot, errRoot := utility.GetGitRoot("")
+		cobra.CheckErr(errRoot)
+
+		cobra.CheckErr(utility.SetGitConfig(fmt.Sprintf("merge.%s.name", MergeDriverName), "git-secrets config file merge driver"))
+		cobra.CheckErr(utility.SetGitConfig(fmt.Sprintf("merge.%s.driver", MergeDriverName), "git secrets merge-driver %O %A %B %P"))
+
+		addedLines, errAdd := utility.AddGitAttributes(gitRoot, configFilePatterns(gitRoot), fmt.Sprintf("merge=%s", MergeDriverName))
+		cobra.CheckErr(errAdd)
+
+		fmt.Printf("Registered the merge driver %s in the git config\n", MergeDriverName)
+		for _, addedLine := range addedLines {
+			fmt.Printf("Added to .gitattributes: %s\n", addedLine)
+		}
+
+	},
+}
+
+// configFilePatterns returns the .gitattributes patterns matching the config file and all included files
+// the default config file names are used if the project config can not be parsed
+func configFilePatterns(gitRoot string) (patterns []string) {
+
+	if projectCfgError != nil {
+		return config_generic.DefaultConfigFileNames()
+	}
+
+	for _, configFile := range append([]string{projectCfg.GetConfigFileUsed()}, projectCfg.GetIncludedFiles()...) {
+		absFile, _ := filepath.Abs(configFile)
+		relativeFile, errRel := filepath.Rel(gitRoot, absFile)
+		if errRel != nil {
+			continue
+		}
+		patterns = append(patterns, "/"+filepath.ToSlash(relativeFile))
+	}
+
+	return patterns
+
+}
+
+func init() {
+	rootCmd.AddCommand(mergeDriverCmd)
+	mergeDriverCmd.AddCommand(mergeDriverInstallCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+const FlagTo = "to"
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate the config file and its included files to another schema version",
+	Example: `
+git secrets migrate: Migrates the config file to the latest schema version
+git secrets migrate --to 2 --dry-run: Prints the migrated config file without writing it
+git secrets migrate --to 1: Migrates back to version 1 if no entry holds metadata
+`,
+	Args: cobra.NoArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		targetVersion, _ := cmd.Flags().GetInt(FlagTo)
+		isDryRun, _ := cmd.Flags().GetBool(FlagDryRun)
+
+		currentVersion := projectCfg.GetConfigVersion()
+		if currentVersion == targetVersion {
+			fmt.Printf("The config file is already at version %d\n", targetVersion)
+			return
+		}
+
+		// the included files use the same version, so they are migrated together with the config file
+		configFiles := append([]string{projectCfg.GetConfigFileUsed()}, projectCfg.GetIncludedFiles()...)
+
+		if isDryRun {
+			for _, configFile := range configFiles {
+				fileContents, errRead := afero.ReadFile(fs, configFile)
+				if errRead != nil {
+					cobra.CheckErr(fmt.Errorf("could not read %s: %s", configFile, errRead.Error()))
+				}
+				migrated, errMigrate := config_generic.Migrate(config_generic.DetectFileFormat(configFile), fileContents, targetVersion)
+				if errMigrate != nil {
+					cobra.CheckErr(fmt.Errorf("could not migrate %s: %s", configFile, errMigrate.Error()))
+				}
+				if len(configFiles) > 1 {
+					fmt.Printf("==> %s <==\n", configFile)
+				}
+				fmt.Println(string(migrated))
+			}
+			return
+		}
+
+		backupFiles, errMigrate := config_generic.MigrateFiles(fs, configFiles, targetVersion)
+		cobra.CheckErr(errMigrate)
+
+		for _, configFile := range configFiles {
+			fmt.Printf("Migrated %s from version %d to %d\n", configFile, currentVersion, targetVersion)
+		}
+		for _, backupFile := range backupFiles {
+			fmt.Printf("Backup of the original file: %s\n", backupFile)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().Int(FlagTo, config_generic.LatestVersion, "the schema version to migrate to")
+	migrateCmd.Flags().Bool(FlagDryRun, false, "print the migrated config file without writing it")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/recovery"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const FlagShares = "shares"
+const FlagThreshold = "threshold"
+const FlagOutputDir = "output-dir"
+
+// recoveryCmd represents the recovery command
+var recoveryCmd = &cobra.Command{
+	Use:   "recovery",
+	Short: "Split a global secret into recovery shares and combine them again",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// recoverySplitCmd represents the recoverySplit command
+var recoverySplitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split a global secret into shares using shamir secret sharing, any threshold of them can rebuild the secret",
+	Example: `
+git secrets recovery split <secretKey>: prints 5 shares, any 3 of them rebuild the secret
+git secrets recovery split <secretKey> --shares 3 --threshold 2: prints 3 shares, any 2 of them rebuild the secret
+git secrets recovery split <secretKey> --output-dir shares: writes each share to its own file which is only readable by you
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		shares, _ := cmd.Flags().GetInt(FlagShares)
+		threshold, _ := cmd.Flags().GetInt(FlagThreshold)
+		outputDir, _ := cmd.Flags().GetString(FlagOutputDir)
+		secretKey := args[0]
+
+		secretValue := globalCfg.GetSecret(secretKey)
+		if secretValue == "" {
+			cobra.CheckErr(fmt.Errorf("the secret %s does not exist", secretKey))
+		}
+
+		armored, errSplit := recovery.Split(secretKey, secretValue, shares, threshold)
+		cobra.CheckErr(errSplit)
+
+		if outputDir == "" {
+			for _, share := range armored {
+				fmt.Println(share)
+			}
+		} else {
+			cobra.CheckErr(fs.MkdirAll(outputDir, 0700))
+			for i, share := range armored {
+				fileName := filepath.Join(outputDir, fmt.Sprintf("%s-share-%d.txt", secretKey, i+1))
+				if errWrite := afero.WriteFile(fs, fileName, []byte(share), 0600); errWrite != nil {
+					cobra.CheckErr(fmt.Errorf("could not write %s: %s", fileName, errWrite.Error()))
+				}
+				fmt.Fprintf(os.Stderr, "Share %d written to %s\n", i+1, fileName)
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Hand each share to a different person, any %d of the %d shares rebuild %s: git secrets recovery combine\n", threshold, shares, secretKey)
+
+	},
+}
+
+// recoveryCombineCmd represents the recoveryCombine command
+var recoveryCombineCmd = &cobra.Command{
+	Use:   "combine",
+	Short: "Rebuild a global secret from its recovery shares and write it to the global configuration",
+	Example: `
+git secrets recovery combine share-1.txt share-3.txt share-4.txt: rebuilds the secret from the share files
+git secrets recovery combine < shares.txt: reads the shares from stdin, finish the input using ctrl+d
+git secrets recovery combine share-*.txt --name otherName --force: writes the secret using another name and overwrites an existing secret
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		isForce, _ := cmd.Flags().GetBool(FlagForce)
+		secretKey, _ := cmd.Flags().GetString(FlagName)
+
+		var text string
+		if len(args) > 0 {
+			for _, fileName := range args {
+				fileContents, errRead := afero.ReadFile(fs, fileName)
+				if errRead != nil {
+					cobra.CheckErr(fmt.Errorf("could not read %s: %s", fileName, errRead.Error()))
+				}
+				text += string(fileContents) + "\n"
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, "Paste the shares and finish the input using ctrl+d:")
+			stdinContents, errRead := io.ReadAll(os.Stdin)
+			if errRead != nil {
+				cobra.CheckErr(fmt.Errorf("could not read the shares: %s", errRead.Error()))
+			}
+			text = string(stdinContents)
+		}
+
+		shares, errParse := recovery.Parse(text)
+		cobra.CheckErr(errParse)
+
+		shareName, secretValue, errCombine := recovery.Combine(shares)
+		cobra.CheckErr(errCombine)
+
+		if secretKey == "" {
+			secretKey = shareName
+		}
+
+		errWrite := globalCfg.SetSecret(secretKey, secretValue, isForce)
+		if errWrite != nil {
+			cobra.CheckErr(fmt.Errorf("could not write config: %s", errWrite.Error()))
+		}
+
+		fmt.Printf("%s has been rebuilt from %d shares (fingerprint %s)\n", secretKey, len(shares), shares[0].Fingerprint)
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recoveryCmd)
+	recoveryCmd.AddCommand(recoverySplitCmd)
+	recoveryCmd.AddCommand(recoveryCombineCmd)
+
+	recoverySplitCmd.Flags().Int(FlagShares, 5, "Number of shares to create")
+	recoverySplitCmd.Flags().Int(FlagThreshold, 3, "Number of shares needed to rebuild the secret")
+	recoverySplitCmd.Flags().String(FlagOutputDir, "", "Write each share to its own file in the directory instead of printing them")
+
+	recoveryCombineCmd.Flags().Bool(FlagForce, false, "Force overwrite existing secret: You may loose your master password!")
+	recoveryCombineCmd.Flags().String(FlagName, "", "Write the secret using another name than the one stored in the shares")
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// removeCmd represents the remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove resources like secret, config, context or file",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// removeSecretCmd represents the removeSecret command
+var removeSecretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Remove a secret from the config file",
+	Example: `
+git secrets remove secret <secretKey>: Removes the secret from all contexts
+git secrets remove secret <secretKey> -c prod: Removes the overwrite of the prod context
+git secrets remove secret <secretKey> --force: Removes the secret without confirmation
+`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		removeEntry(cmd, args[0], true)
+	},
+}
+
+// removeConfigCmd represents the removeConfig command
+var removeConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Remove a config entry from the config file",
+	Example: `
+git secrets remove config <configKey>: Removes the config entry from all contexts
+git secrets remove config <configKey> -c prod: Removes the overwrite of the prod context
+git secrets remove config <configKey> --force: Removes the config entry without confirmation
+`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		removeEntry(cmd, args[0], false)
+	},
+}
+
+// removeContextCmd represents the removeContext command
+var removeContextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Remove a context and all contexts extending it",
+	Example: `
+git secrets remove context <contextName>
+git secrets remove context <contextName> --force: Removes the context without confirmation
+`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		contextToRemove := args[0]
+		if projectCfg.GetContext(contextToRemove) == nil {
+			cobra.CheckErr(fmt.Errorf("the context %s does not exist", contextToRemove))
+		}
+
+		removedContexts := append([]string{contextToRemove}, projectCfg.GetDescendantContexts(contextToRemove)...)
+		if !confirmAction(cmd, fmt.Sprintf("Remove the contexts %s including their secrets and configs?", strings.Join(removedContexts, ", "))) {
+			fmt.Println("Nothing has been removed")
+			return
+		}
+
+		selectedContextName := projectCfg.GetCurrent().Name
+		cobra.CheckErr(projectCfg.GetConfigWriter().RemoveContext(contextToRemove))
+		fmt.Printf("The contexts %s have been removed\n", strings.Join(removedContexts, ", "))
+		if projectCfg.GetCurrent().Name != selectedContextName {
+			fmt.Printf("The selected context %s has been removed, the context %s is used now\n", selectedContextName, projectCfg.GetCurrent().Name)
+		}
+
+	},
+}
+
+// removeFileCmd represents the removeFile command
+var removeFileCmd = &cobra.Command{
+	Use:   "file",
+	Short: "Remove a file from the rendering engine",
+	Example: `
+git secrets remove file <fileOut> -t <targetName>
+git secrets remove file <fileOut> -t <targetName> --force: Removes the file without confirmation
+`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		targetName, _ := cmd.Flags().GetString(FlagTarget)
+		if targetName == "" {
+			cobra.CheckErr(fmt.Errorf("you must specify a target name: -t or --target <targetName>"))
+		}
+
+		fileOut := args[0]
+		if !confirmAction(cmd, fmt.Sprintf("Remove the file %s from target %s?", fileOut, targetName)) {
+			fmt.Println("Nothing has been removed")
+			return
+		}
+
+		cobra.CheckErr(projectCfg.GetConfigWriter().RemoveFileToRender(targetName, fileOut))
+		fmt.Printf("The file %s has been removed from target %s\n", fileOut, targetName)
+
+	},
+}
+
+// removeEntry removes the secret or config entry from the selected context after the confirmation
+func removeEntry(cmd *cobra.Command, entryName string, isSecret bool) {
+
+	kind := "config entry"
+	definingContexts := make(map[string]bool)
+	if isSecret {
+		kind = "secret"
+		for _, secret := range projectCfg.GetSecretsByContext(projectCfg.GetCurrent().Name) {
+			definingContexts[secret.Name] = true
+		}
+	} else {
+		for _, config := range projectCfg.GetConfigsByContext(projectCfg.GetCurrent().Name) {
+			definingContexts[config.Name] = true
+		}
+	}
+
+	if !definingContexts[entryName] {
+		cobra.CheckErr(fmt.Errorf("the %s %s is not defined in context %s", kind, entryName, projectCfg.GetCurrent().Name))
+	}
+
+	removedFrom := projectCfg.GetRemovalContexts(projectCfg.GetCurrent().Name, entryName, isSecret)
+	if !confirmAction(cmd, fmt.Sprintf("Remove the %s %s from the contexts %s?", kind, entryName, strings.Join(removedFrom, ", "))) {
+		fmt.Println("Nothing has been removed")
+		return
+	}
+
+	configWriter := projectCfg.GetConfigWriter()
+	if isSecret {
+		cobra.CheckErr(configWriter.RemoveSecret(projectCfg.GetCurrent().Name, entryName))
+	} else {
+		cobra.CheckErr(configWriter.RemoveConfig(projectCfg.GetCurrent().Name, entryName))
+	}
+
+	fmt.Printf("The %s %s has been removed from the contexts %s\n", kind, entryName, strings.Join(removedFrom, ", "))
+
+}
+
+// confirmAction asks the user to confirm the action, --force skips the confirmation
+func confirmAction(cmd *cobra.Command, message string) bool {
+	if force, _ := cmd.Flags().GetBool(FlagForce); force {
+		return true
+	}
+	confirmed := false
+	cobra.CheckErr(survey.AskOne(&survey.Confirm{
+		Message: message,
+	}, &confirmed))
+	return confirmed
+}
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+	removeCmd.AddCommand(removeSecretCmd)
+	removeCmd.AddCommand(removeConfigCmd)
+	removeCmd.AddCommand(removeContextCmd)
+	removeCmd.AddCommand(removeFileCmd)
+	removeCmd.PersistentFlags().Bool(FlagForce, false, "Removes without confirmation")
+	removeFileCmd.Flags().StringP(FlagTarget, "t", "", "Specifies the render target name: -t <targetName>, example -t k8s")
+}
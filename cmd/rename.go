@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// renameCmd represents the rename command
+var renameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Rename resources like secret, config or context",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// renameSecretCmd represents the renameSecret command
+var renameSecretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Rename a secret in all contexts",
+	Example: `
+git secrets rename secret <oldSecretKey> <newSecretKey>
+git secrets rename secret <oldSecretKey> <newSecretKey> --force: Renames the secret without confirmation
+`,
+	Args: cobra.ExactArgs(2),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		oldName, newName := args[0], args[1]
+		if !confirmAction(cmd, fmt.Sprintf("Rename the secret %s to %s in all contexts?", oldName, newName)) {
+			fmt.Println("Nothing has been renamed")
+			return
+		}
+		cobra.CheckErr(projectCfg.GetConfigWriter().RenameSecret(oldName, newName))
+		fmt.Printf("The secret %s has been renamed to %s\n", oldName, newName)
+		fmt.Printf("Update your templates: {{.Secrets.%s}} is now {{.Secrets.%s}}\n", oldName, newName)
+	},
+}
+
+// renameConfigCmd represents the renameConfig command
+var renameConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Rename a config entry in all contexts",
+	Example: `
+git secrets rename config <oldConfigKey> <newConfigKey>
+git secrets rename config <oldConfigKey> <newConfigKey> --force: Renames the config entry without confirmation
+`,
+	Args: cobra.ExactArgs(2),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		oldName, newName := args[0], args[1]
+		if !confirmAction(cmd, fmt.Sprintf("Rename the config entry %s to %s in all contexts?", oldName, newName)) {
+			fmt.Println("Nothing has been renamed")
+			return
+		}
+		cobra.CheckErr(projectCfg.GetConfigWriter().RenameConfig(oldName, newName))
+		fmt.Printf("The config entry %s has been renamed to %s\n", oldName, newName)
+		fmt.Printf("Update your templates: {{.Configs.%s}} is now {{.Configs.%s}}\n", oldName, newName)
+	},
+}
+
+// renameContextCmd represents the renameContext command
+var renameContextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Rename a context, contexts extending it are updated",
+	Example: `
+git secrets rename context <oldContextName> <newContextName>
+git secrets rename context <oldContextName> <newContextName> --force: Renames the context without confirmation
+`,
+	Args: cobra.ExactArgs(2),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		oldName, newName := args[0], args[1]
+		if !confirmAction(cmd, fmt.Sprintf("Rename the context %s to %s?", oldName, newName)) {
+			fmt.Println("Nothing has been renamed")
+			return
+		}
+		cobra.CheckErr(projectCfg.GetConfigWriter().RenameContext(oldName, newName))
+		fmt.Printf("The context %s has been renamed to %s\n", oldName, newName)
+		fmt.Printf("Use it using the --context %s or -c %s flag\n", newName, newName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+	renameCmd.AddCommand(renameSecretCmd)
+	renameCmd.AddCommand(renameConfigCmd)
+	renameCmd.AddCommand(renameContextCmd)
+	renameCmd.PersistentFlags().Bool(FlagForce, false, "Renames without confirmation")
+}
@@ -23,6 +23,7 @@ var renderCmd = &cobra.Command{
 git secrets render <targetName>: Render from configuration
 git secrets render <targetName1>,<targetName2>,...: Renders multiple targets at once
 git secrets render <fileIn> <fileOut> --debug: Render a specific file instead of the configured ones
+git secrets render <fileIn> <fileOut> --partial "partials/*.tpl": Render a specific file using partial templates
 git secrets render <targetName> -c prod: Render files for the prod context
 git secrets render <targetName> --dry-run: Render files and print them to the console
 git secrets render <targetName> --dry-run --debug: Dry run render and shows the rendering context
@@ -72,37 +73,46 @@ git secrets render <targetName> --debug: Render and write the rendering target
 			}
 
 		} else {
+			partials, _ := cmd.Flags().GetStringArray(FlagPartial)
 			filesToRender = append(filesToRender, &config_generic.FileToRender{
-				FileIn:  args[0],
-				FileOut: args[1],
+				FileIn:   args[0],
+				FileOut:  args[1],
+				Partials: partials,
 			})
 		}
 
 		for _, fileToRender := range filesToRender {
 
+			sourceName := fileToRender.FileIn
+			if fileToRender.Kubernetes != nil {
+				sourceName = fmt.Sprintf("kubernetes manifest %s", fileToRender.Kubernetes.Name)
+			}
+
 			if isDryRun {
 				usedContext, fileContents, errRender := renderingEngine.RenderFile(fileToRender)
+				recordRender(fileToRender, errRender)
 				if isDebug {
-					fmt.Println(fileToRender.FileIn)
+					fmt.Println(sourceName)
 					if usedContext != nil {
 						renderContextJson, _ := json.MarshalIndent(usedContext, "", "  ")
 						fmt.Println(string(renderContextJson))
 					}
 				}
 				if errRender != nil {
-					cobra.CheckErr(fmt.Errorf("could not render file %s: %s", fileToRender.FileIn, errRender.Error()))
+					cobra.CheckErr(fmt.Errorf("could not render file %s: %s", sourceName, errRender.Error()))
 					continue
 				}
 				fmt.Println(fileContents)
 			} else {
 				usedContext, errWrite := renderingEngine.WriteFile(fileToRender)
+				recordRender(fileToRender, errWrite)
 				if isDebug && usedContext != nil {
-					fmt.Println(fileToRender.FileIn)
+					fmt.Println(sourceName)
 					renderContextJson, _ := json.MarshalIndent(usedContext, "", "  ")
 					fmt.Println(string(renderContextJson))
 				}
 				if errWrite != nil {
-					cobra.CheckErr(fmt.Errorf("could not write file %s: %s", fileToRender.FileIn, errWrite.Error()))
+					cobra.CheckErr(fmt.Errorf("could not write file %s: %s", sourceName, errWrite.Error()))
 					continue
 				}
 				fmt.Println(fileToRender.FileOut, "written")
@@ -113,10 +123,18 @@ git secrets render <targetName> --debug: Render and write the rendering target
 	},
 }
 
+// recordRender records the rendered file in the audit log if it is enabled
+func recordRender(fileToRender *config_generic.FileToRender, errRender error) {
+	if auditLogger != nil {
+		auditLogger.RecordRender(projectCfg.GetCurrent().Name, fileToRender.FileOut, errRender)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(renderCmd)
 
 	renderCmd.Flags().Bool(FlagDryRun, false, "Render files to os.stdout: --dry-run instead of writing")
 	renderCmd.Flags().Bool(FlagDebug, false, "Also prints the rendering context to the console")
+	renderCmd.Flags().StringArray(FlagPartial, []string{}, "Glob pattern of partial templates when rendering a specific file: --partial \"partials/*.tpl\"")
 
 }
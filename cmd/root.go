@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/audit"
 	config_const "github.com/benammann/git-secrets/pkg/config/const"
 	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
 	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/benammann/git-secrets/pkg/config/writer"
 	"github.com/benammann/git-secrets/pkg/render"
+	"github.com/benammann/git-secrets/pkg/utility"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -25,11 +30,12 @@ var projectCfgFile string
 var projectCfg *config_generic.Repository
 var projectCfgError error
 var renderingEngine *render.RenderingEngine
+var auditLogger *audit.Logger
 
-var selectedContext *config_generic.Context
 var contextName string
 
 var overwrittenSecrets []string
+var overwrittenSecretsMap map[string]string
 
 const FlagValue = "value"
 const FlagForce = "force"
@@ -39,6 +45,7 @@ const FlagTarget = "target"
 const FlagAll = "all"
 const FlagVerbose = "verbose"
 const FlagShort = "short"
+const FlagPartial = "partial"
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -68,14 +75,14 @@ func Execute(buildVersion string, buildCommit string, buildDate string) {
 }
 
 func init() {
-	cobra.OnInitialize(initGlobalConfig, initProjectConfig, resolveContext, createRenderingEngine)
+	cobra.OnInitialize(initGlobalConfig, initProjectConfig, resolveContext, initAuditLogger, createRenderingEngine)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&globalCfgFile, "global-config", "", "Path to the global config file: ~/.git-secrets.yaml")
-	rootCmd.PersistentFlags().StringVarP(&projectCfgFile, "config", "f", ".git-secrets.json", "Path to the projects config file: .git-secrets.json")
+	rootCmd.PersistentFlags().StringVarP(&projectCfgFile, "config", "f", ".git-secrets.json", "Path to the projects config file: .git-secrets.json, .git-secrets.yaml or .git-secrets.toml")
 	rootCmd.PersistentFlags().StringVarP(&contextName, "context", "c", "", "Which context to use: default")
 	rootCmd.PersistentFlags().StringArrayVar(&overwrittenSecrets, "secret", []string{}, "Pass global secrets directly: --secret secretKey=secretValue")
 	// Cobra also supports local flags, which will only run
@@ -126,7 +133,7 @@ func initGlobalConfig() {
 
 func initProjectConfig() {
 
-	overwrittenSecretsMap := make(map[string]string)
+	overwrittenSecretsMap = make(map[string]string)
 	for _, secretKeyValue := range overwrittenSecrets {
 		splitSecret := strings.SplitN(secretKeyValue, "=", 2)
 		if len(splitSecret) < 2 {
@@ -136,10 +143,52 @@ func initProjectConfig() {
 		overwrittenSecretsMap[secretKey] = strings.Join(secretValues, "")
 	}
 
+	// use the nearest config file of the working directory or its parents up to the git root if no config file is passed
+	if !rootCmd.PersistentFlags().Changed("config") {
+		if workingDir, errWd := os.Getwd(); errWd == nil {
+			if foundConfigFile, found := findProjectConfigFile(workingDir, true); found {
+				projectCfgFile = foundConfigFile
+			}
+		}
+	}
+
 	projectCfg, projectCfgError = config_generic.ParseRepository(fs, projectCfgFile, globalCfg, overwrittenSecretsMap)
 
 }
 
+// findProjectConfigFile returns the nearest config file of the directory, the parents are searched up to the git root
+// the global config file is never used as project config file although ~/.git-secrets.yaml has a default config file name
+func findProjectConfigFile(dir string, searchParents bool) (string, bool) {
+
+	// the git root is returned with resolved symlinks, so the directory is resolved as well
+	if resolvedDir, errResolve := filepath.EvalSymlinks(dir); errResolve == nil {
+		dir = resolvedDir
+	}
+
+	rootDir := dir
+	if searchParents {
+		if gitRoot, errRoot := utility.GetGitRoot(dir); errRoot == nil {
+			rootDir = gitRoot
+		}
+	}
+
+	var ignoredFiles []string
+	if home, errHome := os.UserHomeDir(); errHome == nil {
+		if resolvedHome, errResolve := filepath.EvalSymlinks(home); errResolve == nil {
+			home = resolvedHome
+		}
+		ignoredFiles = append(ignoredFiles, filepath.Join(home, ".git-secrets.yaml"), filepath.Join(home, ".git-secrets.yml"))
+	}
+	if globalCfgFile != "" {
+		if absGlobalCfgFile, errAbs := filepath.Abs(globalCfgFile); errAbs == nil {
+			ignoredFiles = append(ignoredFiles, absGlobalCfgFile)
+		}
+	}
+
+	return config_generic.FindConfigFile(fs, dir, rootDir, ignoredFiles...)
+
+}
+
 func resolveContext() {
 	if projectCfgError != nil {
 		return
@@ -148,9 +197,49 @@ func resolveContext() {
 	if contextName != "" {
 		desiredContextName = contextName
 	}
-	desiredContext, errGetContext := projectCfg.SetSelectedContext(desiredContextName)
+	// the repository keeps the selection by name when it reloads after a write, so commands use projectCfg.GetCurrent() instead of holding the context
+	_, errGetContext := projectCfg.SetSelectedContext(desiredContextName)
 	cobra.CheckErr(errGetContext)
-	selectedContext = desiredContext
+}
+
+// writeTransaction passes the changes to the config writer in a transaction, so they are validated and written at once or not at all
+func writeTransaction(changes func(configWriter writer.ConfigWriter) error) error {
+	configWriter := projectCfg.GetConfigWriter()
+	if errBegin := configWriter.Begin(); errBegin != nil {
+		return errBegin
+	}
+	if errChanges := changes(configWriter); errChanges != nil {
+		_ = configWriter.Rollback()
+		return errChanges
+	}
+	return configWriter.Commit()
+}
+
+// initAuditLogger records the access to the secrets if an audit sink is configured in the global config
+func initAuditLogger() {
+	if globalCfg == nil {
+		return
+	}
+
+	sink, errSink := audit.NewSink(fs, globalCfg.GetAuditConfig())
+	cobra.CheckErr(errSink)
+	if sink == nil {
+		return
+	}
+
+	// only the command path is recorded since the arguments may contain secret values
+	executedCmd, _, errFind := rootCmd.Find(os.Args[1:])
+	if errFind != nil {
+		executedCmd = rootCmd
+	}
+
+	auditLogger = audit.NewLogger(sink, executedCmd.CommandPath(), func(err error) {
+		fmt.Fprintln(os.Stderr, "Warning:", err.Error())
+	})
+
+	if projectCfg != nil {
+		projectCfg.SetHook(auditLogger)
+	}
 }
 
 func createRenderingEngine() {
@@ -3,9 +3,17 @@ package cmd
 import (
 	"fmt"
 	"github.com/AlecAivazis/survey/v2"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/generator"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
+const FlagGenerate = "generate"
+const FlagLength = "length"
+const FlagCharset = "charset"
+const FlagPrint = "print"
+
 // setCmd represents the set command
 var setCmd = &cobra.Command{
 	Use:   "set",
@@ -30,6 +38,9 @@ git secrets set config <configKey> <configValue> -c prod
 	Run: func(cmd *cobra.Command, args []string) {
 		force, _ := cmd.Flags().GetBool(FlagForce)
 		configKey, configValue := args[0], args[1]
+		if existingConfig := projectCfg.GetCurrentConfig(configKey); existingConfig != nil {
+			cobra.CheckErr(existingConfig.Metadata.ValidateValue(configValue))
+		}
 		configWrite := projectCfg.GetConfigWriter()
 		cobra.CheckErr(configWrite.SetConfig(projectCfg.GetCurrent().Name, configKey, configValue, force))
 		fmt.Printf("The config entry %s has been written\n", configKey)
@@ -45,6 +56,8 @@ var setSecretCmd = &cobra.Command{
 	Example: `
 git secrets set secret <secretKey>: Encodes the secret using interactive ui and adds it to the git secrets file
 git secrets set secret <secretKey> --value <plainValue>: INSECURE: Uses the value directly from the --value parameter
+git secrets set secret <secretKey> --generate: Generates a random value and encodes it without showing it
+git secrets set secret <secretKey> --generate --length 64 --charset hex --print: Generates 64 hex characters and prints the value
 `,
 	Args: cobra.ExactArgs(1),
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
@@ -55,6 +68,22 @@ git secrets set secret <secretKey> --value <plainValue>: INSECURE: Uses the valu
 		secretKey := args[0]
 		value, _ := cmd.Flags().GetString(FlagValue)
 		force, _ := cmd.Flags().GetBool(FlagForce)
+		generate, _ := cmd.Flags().GetBool(FlagGenerate)
+		printValue, _ := cmd.Flags().GetBool(FlagPrint)
+
+		var metadata config_generic.Metadata
+		if existingSecret := projectCfg.GetCurrentSecret(secretKey); existingSecret != nil {
+			metadata = existingSecret.Metadata
+		}
+
+		if generate {
+			if value != "" {
+				cobra.CheckErr(fmt.Errorf("--%s can not be combined with --%s", FlagValue, FlagGenerate))
+			}
+			generatedValue, errGenerate := generateSecretValue(cmd, metadata)
+			cobra.CheckErr(errGenerate)
+			value = generatedValue
+		}
 
 		if value == "" {
 			errAsk := survey.AskOne(&survey.Password{
@@ -63,7 +92,9 @@ git secrets set secret <secretKey> --value <plainValue>: INSECURE: Uses the valu
 			cobra.CheckErr(errAsk)
 		}
 
-		encodedValue, errEncode := selectedContext.EncodeValue(value)
+		cobra.CheckErr(metadata.ValidateValue(value))
+
+		encodedValue, errEncode := projectCfg.GetCurrent().EncodeValue(value)
 		cobra.CheckErr(errEncode)
 
 		writer := projectCfg.GetConfigWriter()
@@ -71,17 +102,55 @@ git secrets set secret <secretKey> --value <plainValue>: INSECURE: Uses the valu
 		cobra.CheckErr(errWrite)
 
 		fmt.Printf("The secret %s has been written\n", secretKey)
+		if generate && printValue {
+			fmt.Printf("Generated value: %s\n", value)
+		}
 		fmt.Printf("Resolve the decoded value: git secrets get secret %s\n", secretKey)
 		fmt.Printf("Use it in a template: MY_CONFIG_KEY={{.Secrets.%s}}\n", secretKey)
 
 	},
 }
 
+// generateSecretValue generates a random value, the charset and the length default to the rules of the secret
+func generateSecretValue(cmd *cobra.Command, metadata config_generic.Metadata) (string, error) {
+
+	charset, _ := cmd.Flags().GetString(FlagCharset)
+	length, _ := cmd.Flags().GetInt(FlagLength)
+
+	if charset == "" {
+		charset = metadata.Charset
+	}
+	if charset == "" {
+		charset = string(generator.CharsetAlnum)
+	}
+	if !generator.IsCharset(charset) {
+		return "", fmt.Errorf("unknown charset %s", charset)
+	}
+
+	if length == 0 {
+		length = generator.DefaultLengthOf(generator.Charset(charset))
+		if charset != string(generator.CharsetWords) && metadata.MinLength > length {
+			length = metadata.MinLength
+		}
+	}
+
+	return generator.Generate(generator.Charset(charset), length)
+}
+
 func init() {
 	for _, cmd := range []*cobra.Command{setConfigCmd, setSecretCmd} {
 		cmd.Flags().Bool(FlagForce, false, "use --force to overwrite an existing value")
 	}
 	setSecretCmd.Flags().String(FlagValue, "", "--value <secretValue>: This is insecure, use --value $ENV_VALUE to not write the secret value to the history file.")
+
+	var charsets []string
+	for _, charset := range generator.Charsets() {
+		charsets = append(charsets, string(charset))
+	}
+	setSecretCmd.Flags().Bool(FlagGenerate, false, "Generate a random value using crypto/rand instead of asking for it")
+	setSecretCmd.Flags().Int(FlagLength, 0, fmt.Sprintf("Length of the generated value, the number of words for the words charset (default %d, %d words)", generator.DefaultLength, generator.DefaultWordCount))
+	setSecretCmd.Flags().String(FlagCharset, "", fmt.Sprintf("Charset of the generated value: %s (default: the charset of the secret or %s)", strings.Join(charsets, ", "), generator.CharsetAlnum))
+	setSecretCmd.Flags().Bool(FlagPrint, false, "Print the generated value")
 	rootCmd.AddCommand(setCmd)
 	setCmd.AddCommand(setConfigCmd)
 	setCmd.AddCommand(setSecretCmd)
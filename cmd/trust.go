@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// trustCmd represents the trust command
+var trustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Allow the config files to run the commands of their fromCommand decryptSecrets",
+	Long: `Config files are committed to the repository, so everyone with write access could add a command which runs on your machine.
+The commands of fromCommand are only run once you trusted them for the config file defining them.
+The trusted commands are stored in the global config, a changed command has to be trusted again.`,
+	Example: `
+git secrets trust: Lists the commands which are not trusted yet and asks to trust them
+git secrets trust --force: Trusts the commands without confirmation, e.g. in a ci pipeline
+`,
+	Args: cobra.NoArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var untrusted []*encryption.FromCommandSecretResolver
+		var tableData [][]string
+		for _, context := range projectCfg.GetContexts() {
+			commandResolver := contextCommandResolver(context.SecretResolver)
+			if commandResolver == nil || commandResolver.IsTrusted() || isListedCommand(untrusted, commandResolver) {
+				continue
+			}
+			untrusted = append(untrusted, commandResolver)
+			tableData = append(tableData, []string{context.Name, commandResolver.File(), commandResolver.Command()})
+		}
+
+		if len(untrusted) == 0 {
+			fmt.Println("All commands are trusted")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Context", "Config File", "Command"})
+		table.SetBorder(false)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(tableData)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.Render()
+
+		if !confirmAction(cmd, fmt.Sprintf("Do you trust the %d commands to run on this machine?", len(untrusted))) {
+			return
+		}
+
+		for _, commandResolver := range untrusted {
+			cobra.CheckErr(globalCfg.TrustCommand(commandResolver.File(), commandResolver.Command()))
+		}
+
+		fmt.Printf("Trusted %d commands\n", len(untrusted))
+
+	},
+}
+
+// contextCommandResolver returns the command resolver of the context or nil if the key is not resolved by a command
+func contextCommandResolver(secretResolver encryption.SecretResolver) *encryption.FromCommandSecretResolver {
+	if pinnedResolver, isPinned := secretResolver.(*encryption.PinnedSecretResolver); isPinned {
+		secretResolver = pinnedResolver.Unpinned()
+	}
+	commandResolver, _ := secretResolver.(*encryption.FromCommandSecretResolver)
+	return commandResolver
+}
+
+// isListedCommand returns true if the command of the config file is already listed, contexts inherit the resolver of their parent
+func isListedCommand(listed []*encryption.FromCommandSecretResolver, commandResolver *encryption.FromCommandSecretResolver) bool {
+	for _, listedResolver := range listed {
+		if listedResolver.File() == commandResolver.File() && listedResolver.Command() == commandResolver.Command() {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(trustCmd)
+	trustCmd.Flags().Bool(FlagForce, false, "Trusts the commands without confirmation")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/verify"
+	"github.com/spf13/cobra"
+	"os"
+	"runtime"
+)
+
+const FlagParallel = "parallel"
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Checks that every secret can be decrypted using the secret of its context",
+	Long: `Decrypts every secret of every context using the secret of the context defining it.
+The failures are grouped by their reason: unresolved secret, invalid key, wrong key, bad base64 or truncated payload.
+Exits with a non-zero exit code if a secret can not be decrypted, so it can be used to gate CI.`,
+	Example: `
+git secrets verify: Decrypts the secrets of all contexts
+git secrets verify --context prod: Decrypts the secrets used by the prod context including the inherited ones
+git secrets verify --secret gitSecretsTest=$GIT_SECRETS_TEST: Uses the secret passed from CI
+`,
+	Args: cobra.NoArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(projectCfgError)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		contextNames, _ := cmd.Flags().GetStringSlice(FlagContext)
+		parallel, _ := cmd.Flags().GetInt(FlagParallel)
+
+		secrets, errSecrets := verify.Secrets(projectCfg, contextNames)
+		cobra.CheckErr(errSecrets)
+
+		result := verify.Verify(secrets, parallel)
+		_, _ = os.Stdout.Write(result.FormatText())
+
+		if len(result.Failures) > 0 {
+			cobra.CheckErr(fmt.Errorf("%d secrets can not be decrypted", len(result.Failures)))
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	// overrides the global context flag since verify accepts multiple contexts
+	verifyCmd.Flags().StringSliceP(FlagContext, "c", nil, "The contexts to verify including their ancestors: --context prod,staging, all contexts if not set")
+	verifyCmd.Flags().Int(FlagParallel, runtime.NumCPU(), "Number of secrets decrypted in parallel")
+}
@@ -10,8 +10,11 @@ import (
 )
 
 func main() {
-	if errParse := ParseEnv(afero.NewOsFs(), ".env"); errParse != nil {
-		log.Fatal(errParse)
+	// the .env file is not needed when the secrets are injected using git secrets exec
+	if _, errStat := os.Stat(".env"); errStat == nil {
+		if errParse := ParseEnv(afero.NewOsFs(), ".env"); errParse != nil {
+			log.Fatal(errParse)
+		}
 	}
 	DebugEnv("Database Host", "DATABASE_HOST")
 	DebugEnv("Database Port", "DATABASE_PORT")
@@ -0,0 +1,152 @@
+package armor
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HeaderChecksum is added to every encoded block, it is verified when decoding
+const HeaderChecksum = "Checksum"
+
+// lineLength is the number of base64 characters per line
+const lineLength = 64
+
+// ErrDamaged is returned if a block has been changed while it was copied, e.g. a line is missing
+var ErrDamaged = errors.New("the token is damaged")
+
+// Header is a key value pair printed above the payload
+type Header struct {
+	Key   string
+	Value string
+}
+
+// Block is a copy-pasteable text block holding readable headers and a base64 payload
+type Block struct {
+	Type    string
+	Headers []Header
+	Payload []byte
+}
+
+// Begin returns the first line of a block of the given type
+func Begin(blockType string) string {
+	return fmt.Sprintf("-----BEGIN %s-----", blockType)
+}
+
+// End returns the last line of a block of the given type
+func End(blockType string) string {
+	return fmt.Sprintf("-----END %s-----", blockType)
+}
+
+// Get returns the value of the header or an empty string if the header does not exist
+func (b *Block) Get(key string) string {
+	for _, header := range b.Headers {
+		if header.Key == key {
+			return header.Value
+		}
+	}
+	return ""
+}
+
+// Encode returns the armored block, the checksum header is added after the other headers
+func Encode(block *Block) string {
+
+	encoded := base64.StdEncoding.EncodeToString(block.Payload)
+
+	var armored strings.Builder
+	armored.WriteString(Begin(block.Type) + "\n")
+	for _, header := range block.Headers {
+		armored.WriteString(fmt.Sprintf("%s: %s\n", header.Key, header.Value))
+	}
+	armored.WriteString(fmt.Sprintf("%s: %s\n", HeaderChecksum, checksum(block)))
+	armored.WriteString("\n")
+	for len(encoded) > lineLength {
+		armored.WriteString(encoded[:lineLength] + "\n")
+		encoded = encoded[lineLength:]
+	}
+	armored.WriteString(encoded + "\n")
+	armored.WriteString(End(block.Type) + "\n")
+
+	return armored.String()
+}
+
+// Decode returns all blocks of the given type and verifies their checksums
+// indentation and text around the blocks are ignored, so the blocks can be pasted from a chat or an email
+func Decode(text string, blockType string) ([]*Block, error) {
+
+	var blocks []*Block
+	var current *Block
+	var expectedChecksum string
+	var body strings.Builder
+	inBody := false
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == Begin(blockType):
+			if current != nil {
+				return nil, fmt.Errorf("%w: the line %s is missing", ErrDamaged, End(blockType))
+			}
+			current = &Block{Type: blockType}
+			expectedChecksum, inBody = "", false
+			body.Reset()
+		case current == nil:
+			continue
+		case line == End(blockType):
+			payload, errDecode := base64.StdEncoding.DecodeString(body.String())
+			if errDecode != nil {
+				return nil, fmt.Errorf("%w: %s", ErrDamaged, errDecode.Error())
+			}
+			current.Payload = payload
+			if checksum(current) != expectedChecksum {
+				return nil, fmt.Errorf("%w: the checksum does not match", ErrDamaged)
+			}
+			blocks = append(blocks, current)
+			current = nil
+		case !inBody && line == "":
+			inBody = true
+		case !inBody:
+			key, value, found := strings.Cut(line, ":")
+			if !found {
+				return nil, fmt.Errorf("%w: invalid header %s", ErrDamaged, line)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if key == HeaderChecksum {
+				expectedChecksum = value
+			} else {
+				current.Headers = append(current.Headers, Header{Key: key, Value: value})
+			}
+		default:
+			body.WriteString(line)
+		}
+	}
+
+	if errScan := scanner.Err(); errScan != nil {
+		return nil, fmt.Errorf("could not read the text: %s", errScan.Error())
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%w: the line %s is missing", ErrDamaged, End(blockType))
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no token found, it starts with %s", Begin(blockType))
+	}
+
+	return blocks, nil
+}
+
+// checksum detects copy and paste errors, it covers the type, the headers and the payload
+func checksum(block *Block) string {
+	hash := sha256.New()
+	hash.Write([]byte(block.Type + "\n"))
+	for _, header := range block.Headers {
+		hash.Write([]byte(fmt.Sprintf("%s: %s\n", header.Key, header.Value)))
+	}
+	hash.Write([]byte("\n"))
+	hash.Write(block.Payload)
+	return hex.EncodeToString(hash.Sum(nil)[:4])
+}
@@ -0,0 +1,58 @@
+package armor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testType = "GIT SECRETS TEST"
+
+func TestEncode(t *testing.T) {
+	encoded := Encode(&Block{
+		Type:    testType,
+		Headers: []Header{{Key: "Name", Value: "test"}},
+		Payload: []byte(strings.Repeat("a", 60)),
+	})
+	assert.Equal(t, `-----BEGIN GIT SECRETS TEST-----
+Name: test
+Checksum: c4899336
+
+YWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFh
+YWFhYWFhYWFhYWFh
+-----END GIT SECRETS TEST-----
+`, encoded)
+}
+
+func TestDecode(t *testing.T) {
+
+	first := Encode(&Block{Type: testType, Headers: []Header{{Key: "Share", Value: "1"}}, Payload: []byte("first")})
+	second := Encode(&Block{Type: testType, Headers: []Header{{Key: "Share", Value: "2"}}, Payload: []byte("second")})
+
+	t.Run("it should decode all blocks and ignore the text around them", func(t *testing.T) {
+		blocks, errDecode := Decode("share one:\n"+first+"\nshare two:\n  "+strings.ReplaceAll(second, "\n", "\r\n  "), testType)
+		assert.NoError(t, errDecode)
+		assert.Len(t, blocks, 2)
+		assert.Equal(t, "1", blocks[0].Get("Share"))
+		assert.Equal(t, []byte("first"), blocks[0].Payload)
+		assert.Equal(t, "2", blocks[1].Get("Share"))
+		assert.Equal(t, []byte("second"), blocks[1].Payload)
+		assert.Equal(t, "", blocks[1].Get(HeaderChecksum))
+	})
+
+	t.Run("it should detect changed headers", func(t *testing.T) {
+		_, errDecode := Decode(strings.Replace(first, "Share: 1", "Share: 3", 1), testType)
+		assert.EqualError(t, errDecode, "the token is damaged: the checksum does not match")
+	})
+
+	t.Run("it should detect a missing end", func(t *testing.T) {
+		_, errDecode := Decode(strings.Replace(first, End(testType), "", 1)+second, testType)
+		assert.ErrorIs(t, errDecode, ErrDamaged)
+	})
+
+	t.Run("it should ignore blocks of other types", func(t *testing.T) {
+		_, errDecode := Decode(first, "GIT SECRETS OTHER")
+		assert.EqualError(t, errDecode, "no token found, it starts with -----BEGIN GIT SECRETS OTHER-----")
+	})
+
+}
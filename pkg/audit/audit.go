@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ActionDecode = "decode"
+	ActionWrite  = "write"
+	ActionRender = "render"
+	ActionExec   = "exec"
+)
+
+// Event describes an access to the secrets or a change of the config, the values are never recorded
+// each event includes the hash of the previous event so removed or modified events can be detected
+type Event struct {
+	Time time.Time `json:"time"`
+
+	// User is the name of the operating system user
+	User string `json:"user"`
+
+	// GitUser is the configured git identity: name <email>
+	GitUser string `json:"gitUser,omitempty"`
+
+	// Command is the called git secrets command without its arguments, e.g. git-secrets set secret
+	Command string `json:"command"`
+
+	// Action is the kind of the event: decode, write, render or exec
+	Action string `json:"action"`
+
+	// Operation details the action: the called config writer method, the render target or the executed binary
+	Operation string `json:"operation,omitempty"`
+
+	Context string `json:"context,omitempty"`
+
+	// Names holds the names of the decoded secret or the changed entries
+	Names []string `json:"names,omitempty"`
+
+	// Error is set if the action failed
+	Error string `json:"error,omitempty"`
+
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// ComputeHash returns the sha256 of the event including the hash of the previous event
+func (e *Event) ComputeHash() string {
+	unhashed := *e
+	unhashed.Hash = ""
+	encoded, _ := json.Marshal(unhashed)
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:])
+}
+
+// chain links the event to the previous event and sets its hash
+func (e *Event) chain(prevHash string) {
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+// Sink stores the events, the sink links each event to the previous one before storing it
+type Sink interface {
+	Write(event *Event) error
+}
+
+// Logger records the events of a git secrets command to the sink
+// it implements config_generic.Hook to record decoded secrets and config changes
+type Logger struct {
+	sink    Sink
+	user    string
+	gitUser string
+	command string
+
+	// onError is called if an event could not be written
+	onError func(err error)
+}
+
+// NewLogger creates a logger for the command, onError is called if an event could not be written
+func NewLogger(sink Sink, command string, onError func(err error)) *Logger {
+	return &Logger{
+		sink:    sink,
+		user:    currentUser(),
+		gitUser: utility.GetGitIdentity(),
+		command: command,
+		onError: onError,
+	}
+}
+
+// Record writes an event to the sink
+func (l *Logger) Record(action string, operation string, contextName string, names []string, errAction error) {
+
+	event := &Event{
+		Time:      time.Now().UTC(),
+		User:      l.user,
+		GitUser:   l.gitUser,
+		Command:   l.command,
+		Action:    action,
+		Operation: operation,
+		Context:   contextName,
+		Names:     names,
+	}
+	if errAction != nil {
+		event.Error = errAction.Error()
+	}
+
+	if errWrite := l.sink.Write(event); errWrite != nil && l.onError != nil {
+		l.onError(fmt.Errorf("could not write audit event: %s", errWrite.Error()))
+	}
+
+}
+
+// RecordRender records the rendering of a file
+func (l *Logger) RecordRender(contextName string, fileOut string, errRender error) {
+	l.Record(ActionRender, "", contextName, []string{fileOut}, errRender)
+}
+
+// RecordExec records the execution of a binary with the secrets as environment variables, the arguments are not recorded
+func (l *Logger) RecordExec(contextName string, args []string, errExec error) {
+	var binary string
+	if len(args) > 0 {
+		binary = filepath.Base(args[0])
+	}
+	l.Record(ActionExec, binary, contextName, nil, errExec)
+}
+
+// SecretDecoded records the decoded secret
+func (l *Logger) SecretDecoded(secret *config_generic.Secret, errDecode error) {
+	var contextName string
+	if secret.OriginContext != nil {
+		contextName = secret.OriginContext.Name
+	}
+	l.Record(ActionDecode, "", contextName, []string{secret.Name}, errDecode)
+}
+
+// ConfigChanged records the change of the config
+func (l *Logger) ConfigChanged(change *config_generic.ConfigChange, errChange error) {
+	l.Record(ActionWrite, change.Action, change.Context, change.Names, errChange)
+}
+
+// currentUser returns the name of the operating system user
+func currentUser() string {
+	if current, errUser := user.Current(); errUser == nil {
+		return current.Username
+	}
+	for _, envName := range []string{"USER", "USERNAME"} {
+		if name := strings.TrimSpace(os.Getenv(envName)); name != "" {
+			return name
+		}
+	}
+	return ""
+}
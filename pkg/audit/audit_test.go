@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memorySink keeps the events in memory
+type memorySink struct {
+	chain  memoryChain
+	events []*Event
+}
+
+func (m *memorySink) Write(event *Event) error {
+	return m.chain.write(event, func(event *Event) error {
+		m.events = append(m.events, event)
+		return nil
+	})
+}
+
+func TestLogger(t *testing.T) {
+
+	sink := &memorySink{}
+	logger := NewLogger(sink, "git-secrets get secret", nil)
+
+	secret := &config_generic.Secret{Name: "databasePassword", EncodedValue: "encoded", OriginContext: &config_generic.Context{Name: "prod"}}
+	logger.SecretDecoded(secret, nil)
+	logger.ConfigChanged(&config_generic.ConfigChange{Action: "SetSecret", Context: "prod", Names: []string{"apiKey"}}, fmt.Errorf("failed"))
+	logger.RecordRender("prod", "templates/.env", nil)
+	logger.RecordExec("prod", []string{"/usr/bin/env", "--secret-arg"}, nil)
+
+	assert.Len(t, sink.events, 4)
+
+	t.Run("it should record the actions without values and arguments", func(t *testing.T) {
+		decode, write, render, exec := sink.events[0], sink.events[1], sink.events[2], sink.events[3]
+		assert.Equal(t, ActionDecode, decode.Action)
+		assert.Equal(t, "prod", decode.Context)
+		assert.Equal(t, []string{"databasePassword"}, decode.Names)
+		assert.Equal(t, "git-secrets get secret", decode.Command)
+		assert.False(t, decode.Time.IsZero())
+		assert.Equal(t, ActionWrite, write.Action)
+		assert.Equal(t, "SetSecret", write.Operation)
+		assert.Equal(t, "failed", write.Error)
+		assert.Equal(t, []string{"templates/.env"}, render.Names)
+		assert.Equal(t, "env", exec.Operation)
+		for _, event := range sink.events {
+			assert.NotContains(t, fmt.Sprintf("%+v", *event), "encoded")
+			assert.NotContains(t, fmt.Sprintf("%+v", *event), "--secret-arg")
+		}
+	})
+
+	t.Run("it should chain the events", func(t *testing.T) {
+		assert.Equal(t, "", sink.events[0].PrevHash)
+		for i := 1; i < len(sink.events); i++ {
+			assert.Equal(t, sink.events[i-1].Hash, sink.events[i].PrevHash)
+			assert.Equal(t, sink.events[i].ComputeHash(), sink.events[i].Hash)
+		}
+	})
+
+	t.Run("it should pass write errors to the error handler", func(t *testing.T) {
+		var errHandled error
+		failingLogger := NewLogger(&CommandSink{args: []string{"git-secrets-missing-audit-command"}}, "git-secrets", func(err error) {
+			errHandled = err
+		})
+		failingLogger.RecordRender("default", "out", nil)
+		assert.Error(t, errHandled)
+		assert.Contains(t, errHandled.Error(), "could not write audit event")
+	})
+
+}
+
+func TestFileSink(t *testing.T) {
+
+	fs := afero.NewMemMapFs()
+	sink, errSink := NewFileSink(fs, "audit.jsonl")
+	assert.NoError(t, errSink)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			NewLogger(sink, "git-secrets", nil).Record(ActionDecode, "", "default", []string{fmt.Sprintf("secret%d", i)}, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	contents, errRead := afero.ReadFile(fs, "audit.jsonl")
+	assert.NoError(t, errRead)
+
+	t.Run("it should write a single chain of concurrent events", func(t *testing.T) {
+		verified, errVerify := Verify(bytes.NewReader(contents))
+		assert.NoError(t, errVerify)
+		assert.Equal(t, 10, verified)
+	})
+
+	t.Run("it should continue the chain of an existing file", func(t *testing.T) {
+		reopened, _ := NewFileSink(fs, "audit.jsonl")
+		assert.NoError(t, reopened.Write(&Event{Action: ActionExec}))
+		contents, _ := afero.ReadFile(fs, "audit.jsonl")
+		verified, errVerify := Verify(bytes.NewReader(contents))
+		assert.NoError(t, errVerify)
+		assert.Equal(t, 11, verified)
+	})
+
+	t.Run("it should detect modified events", func(t *testing.T) {
+		modified := strings.Replace(string(contents), "secret", "other", 1)
+		verified, errVerify := Verify(strings.NewReader(modified))
+		assert.EqualError(t, errVerify, "event 1 has been modified")
+		assert.Equal(t, 0, verified)
+	})
+
+	t.Run("it should detect removed events", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		removed := strings.Join(append(lines[:3:3], lines[4:]...), "\n")
+		verified, errVerify := Verify(strings.NewReader(removed))
+		assert.EqualError(t, errVerify, "event 4 does not follow the previous event, events have been removed or reordered")
+		assert.Equal(t, 3, verified)
+	})
+
+}
+
+func TestNewSink(t *testing.T) {
+
+	fs := afero.NewMemMapFs()
+
+	t.Run("it should disable the audit log without sink", func(t *testing.T) {
+		sink, errSink := NewSink(fs, &global_config.AuditConfig{})
+		assert.NoError(t, errSink)
+		assert.Nil(t, sink)
+	})
+
+	t.Run("it should create the configured sink", func(t *testing.T) {
+		sink, errSink := NewSink(fs, &global_config.AuditConfig{Sink: SinkFile, File: "audit.jsonl"})
+		assert.NoError(t, errSink)
+		assert.Equal(t, "audit.jsonl", sink.(*FileSink).Path())
+		sink, errSink = NewSink(fs, &global_config.AuditConfig{Sink: SinkCommand, Command: "logger -t git-secrets"})
+		assert.NoError(t, errSink)
+		assert.Equal(t, []string{"logger", "-t", "git-secrets"}, sink.(*CommandSink).args)
+		sink, errSink = NewSink(fs, &global_config.AuditConfig{Sink: SinkCommand, Command: `"/opt/audit tools/forward" --tag 'git secrets'`})
+		assert.NoError(t, errSink)
+		assert.Equal(t, []string{"/opt/audit tools/forward", "--tag", "git secrets"}, sink.(*CommandSink).args)
+	})
+
+	t.Run("it should use the file in the home directory by default", func(t *testing.T) {
+		home, _ := os.UserHomeDir()
+		sink, errSink := NewSink(fs, &global_config.AuditConfig{Sink: SinkFile})
+		assert.NoError(t, errSink)
+		assert.Equal(t, filepath.Join(home, DefaultFileName), sink.(*FileSink).Path())
+	})
+
+	t.Run("it should fail on invalid configs", func(t *testing.T) {
+		_, errSink := NewSink(fs, &global_config.AuditConfig{Sink: SinkCommand})
+		assert.Error(t, errSink)
+		_, errSink = NewSink(fs, &global_config.AuditConfig{Sink: SinkCommand, Command: `logger -t "git-secrets`})
+		assert.Error(t, errSink)
+		_, errSink = NewSink(fs, &global_config.AuditConfig{Sink: "database"})
+		assert.EqualError(t, errSink, "unsupported audit sink database, use file, syslog or command")
+	})
+
+}
+
+func TestCommandSink(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the command sink test uses a shell")
+	}
+
+	outFile := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, errSink := NewCommandSink(fmt.Sprintf("sh -c 'cat >> %s'", outFile))
+	assert.NoError(t, errSink)
+
+	logger := NewLogger(sink, "git-secrets", nil)
+	logger.RecordRender("default", "a", nil)
+	logger.RecordRender("default", "b", nil)
+
+	contents, errRead := os.ReadFile(outFile)
+	assert.NoError(t, errRead)
+	verified, errVerify := Verify(bytes.NewReader(contents))
+	assert.NoError(t, errVerify)
+	assert.Equal(t, 2, verified)
+
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"fmt"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	SinkFile    = "file"
+	SinkSyslog  = "syslog"
+	SinkCommand = "command"
+)
+
+// DefaultFileName is the name of the audit log file in the home directory if audit.file is not set
+const DefaultFileName = ".git-secrets-audit.jsonl"
+
+// NewSink creates the sink configured in the global config, nil is returned if the audit log is disabled
+func NewSink(fs afero.Fs, auditConfig *global_config.AuditConfig) (Sink, error) {
+	switch auditConfig.Sink {
+	case "":
+		return nil, nil
+	case SinkFile:
+		return NewFileSink(fs, auditConfig.File)
+	case SinkSyslog:
+		return NewSyslogSink()
+	case SinkCommand:
+		return NewCommandSink(auditConfig.Command)
+	default:
+		return nil, fmt.Errorf("unsupported audit sink %s, use %s, %s or %s", auditConfig.Sink, SinkFile, SinkSyslog, SinkCommand)
+	}
+}
+
+// DefaultFile returns the path of the audit log file in the home directory
+func DefaultFile() (string, error) {
+	home, errHome := os.UserHomeDir()
+	if errHome != nil {
+		return "", fmt.Errorf("could not resolve the audit log file: %s", errHome.Error())
+	}
+	return filepath.Join(home, DefaultFileName), nil
+}
+
+// memoryChain links the events written by this process, it is used by the sinks which can not read the previous events
+type memoryChain struct {
+	mutex    sync.Mutex
+	prevHash string
+}
+
+// write links the event to the previous one and passes it to write, the chain is only advanced if write succeeds
+func (m *memoryChain) write(event *Event, write func(event *Event) error) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	event.chain(m.prevHash)
+	if errWrite := write(event); errWrite != nil {
+		return errWrite
+	}
+	m.prevHash = event.Hash
+	return nil
+}
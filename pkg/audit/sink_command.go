@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/kballard/go-shellquote"
+	"os/exec"
+	"strings"
+)
+
+// CommandSink passes each event as json to the stdin of a command, e.g. to forward it to a log collector
+// the events are only linked to the events of the same process since the command can not be queried for the previous event
+type CommandSink struct {
+	args  []string
+	chain memoryChain
+}
+
+// NewCommandSink creates a sink running the command for every event, the command is split into words like a shell does
+func NewCommandSink(command string) (*CommandSink, error) {
+	args, errSplit := shellquote.Split(command)
+	if errSplit != nil {
+		return nil, fmt.Errorf("could not parse audit.command: %s", errSplit.Error())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("the audit sink %s requires audit.command to be set", SinkCommand)
+	}
+	return &CommandSink{args: args}, nil
+}
+
+func (c *CommandSink) Write(event *Event) error {
+	return c.chain.write(event, func(event *Event) error {
+		encoded, errMarshal := json.Marshal(event)
+		if errMarshal != nil {
+			return errMarshal
+		}
+		command := exec.Command(c.args[0], c.args[1:]...)
+		command.Stdin = bytes.NewReader(append(encoded, '\n'))
+		if output, errRun := command.CombinedOutput(); errRun != nil {
+			return fmt.Errorf("%s failed: %s / %s", c.args[0], errRun.Error(), strings.TrimSpace(string(output)))
+		}
+		return nil
+	})
+}
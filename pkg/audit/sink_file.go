@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/spf13/afero"
+	"io"
+	"os"
+)
+
+// tailSize is the number of bytes read from the end of the audit log to find the last event
+const tailSize = 64 * 1024
+
+// FileSink appends the events as json lines to a file
+// the file is locked while an event is written so the events of concurrent processes form a single chain
+type FileSink struct {
+	fs   afero.Fs
+	path string
+}
+
+// NewFileSink creates a sink appending to the file, the file in the home directory is used if the path is empty
+func NewFileSink(fs afero.Fs, path string) (*FileSink, error) {
+	if path == "" {
+		defaultFile, errDefault := DefaultFile()
+		if errDefault != nil {
+			return nil, errDefault
+		}
+		path = defaultFile
+	}
+	return &FileSink{fs: fs, path: path}, nil
+}
+
+// Path returns the path of the audit log file
+func (f *FileSink) Path() string {
+	return f.path
+}
+
+func (f *FileSink) Write(event *Event) error {
+
+	unlock, errLock := utility.LockFile(f.fs, f.path)
+	if errLock != nil {
+		return errLock
+	}
+	defer unlock()
+
+	prevHash, errLast := f.lastHash()
+	if errLast != nil {
+		return errLast
+	}
+	event.chain(prevHash)
+
+	encoded, errMarshal := json.Marshal(event)
+	if errMarshal != nil {
+		return errMarshal
+	}
+
+	file, errOpen := f.fs.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if errOpen != nil {
+		return fmt.Errorf("could not open %s: %s", f.path, errOpen.Error())
+	}
+	defer file.Close()
+
+	if _, errWrite := file.Write(append(encoded, '\n')); errWrite != nil {
+		return fmt.Errorf("could not write %s: %s", f.path, errWrite.Error())
+	}
+
+	return nil
+
+}
+
+// lastHash returns the hash of the last event in the file, empty if there is no event yet
+func (f *FileSink) lastHash() (string, error) {
+
+	file, errOpen := f.fs.Open(f.path)
+	if os.IsNotExist(errOpen) {
+		return "", nil
+	} else if errOpen != nil {
+		return "", fmt.Errorf("could not open %s: %s", f.path, errOpen.Error())
+	}
+	defer file.Close()
+
+	info, errStat := file.Stat()
+	if errStat != nil {
+		return "", errStat
+	}
+
+	offset := info.Size() - tailSize
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, errRead := file.ReadAt(tail, offset); errRead != nil && errRead != io.EOF {
+		return "", fmt.Errorf("could not read %s: %s", f.path, errRead.Error())
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(tail), []byte("\n"))
+	lastLine := lines[len(lines)-1]
+	if len(lastLine) == 0 {
+		return "", nil
+	}
+
+	var lastEvent Event
+	if errUnmarshal := json.Unmarshal(lastLine, &lastEvent); errUnmarshal != nil {
+		return "", fmt.Errorf("the last event of %s is corrupted: %s", f.path, errUnmarshal.Error())
+	}
+	return lastEvent.Hash, nil
+
+}
+
+// Verify checks the hash chain of the events read from the audit log and returns the number of valid events
+// an error is returned at the first event which has been modified or does not follow its predecessor
+func Verify(reader io.Reader) (int, error) {
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, tailSize), tailSize)
+
+	var prevHash string
+	verified := 0
+
+	for scanner.Scan() {
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var event Event
+		if errUnmarshal := json.Unmarshal(line, &event); errUnmarshal != nil {
+			return verified, fmt.Errorf("event %d is corrupted: %s", verified+1, errUnmarshal.Error())
+		}
+		if event.ComputeHash() != event.Hash {
+			return verified, fmt.Errorf("event %d has been modified", verified+1)
+		}
+		if event.PrevHash != prevHash {
+			return verified, fmt.Errorf("event %d does not follow the previous event, events have been removed or reordered", verified+1)
+		}
+
+		prevHash = event.Hash
+		verified++
+
+	}
+
+	if errScan := scanner.Err(); errScan != nil {
+		return verified, fmt.Errorf("could not read the audit log: %s", errScan.Error())
+	}
+
+	return verified, nil
+
+}
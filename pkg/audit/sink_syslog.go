@@ -0,0 +1,38 @@
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogTag is the tag of the events in the syslog
+const SyslogTag = "git-secrets"
+
+// SyslogSink writes the events as json to the local syslog
+// the events are only linked to the events of the same process since the syslog can not be queried for the previous event
+type SyslogSink struct {
+	writer *syslog.Writer
+	chain  memoryChain
+}
+
+// NewSyslogSink connects to the local syslog
+func NewSyslogSink() (*SyslogSink, error) {
+	writer, errDial := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, SyslogTag)
+	if errDial != nil {
+		return nil, fmt.Errorf("could not connect to the syslog: %s", errDial.Error())
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+func (s *SyslogSink) Write(event *Event) error {
+	return s.chain.write(event, func(event *Event) error {
+		encoded, errMarshal := json.Marshal(event)
+		if errMarshal != nil {
+			return errMarshal
+		}
+		return s.writer.Info(string(encoded))
+	})
+}
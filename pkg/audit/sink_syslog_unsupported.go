@@ -0,0 +1,17 @@
+//go:build windows || plan9
+
+package audit
+
+import "fmt"
+
+// SyslogSink is not available on this platform
+type SyslogSink struct{}
+
+// NewSyslogSink returns an error since there is no syslog on this platform
+func NewSyslogSink() (*SyslogSink, error) {
+	return nil, fmt.Errorf("the audit sink %s is not supported on this platform, use %s or %s", SinkSyslog, SinkFile, SinkCommand)
+}
+
+func (s *SyslogSink) Write(event *Event) error {
+	return fmt.Errorf("the audit sink %s is not supported on this platform", SinkSyslog)
+}
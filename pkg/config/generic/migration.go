@@ -0,0 +1,358 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/xeipuuv/gojsonschema"
+	"sort"
+	"strings"
+)
+
+// LatestVersion is the newest config schema version
+const LatestVersion = 2
+
+// MigrateFunc converts a config document to the next schema version
+// it only converts the keys known to the schema, Migrate applies the result to the original document
+type MigrateFunc func(input []byte) ([]byte, error)
+
+// Migration describes a single step between two neighbouring schema versions
+type Migration struct {
+	From    int
+	To      int
+	Migrate MigrateFunc
+}
+
+// migrations holds all registered migration steps
+var migrations = []*Migration{
+	{From: 1, To: 2, Migrate: migrateV1ToV2},
+	{From: 2, To: 1, Migrate: migrateV2ToV1},
+}
+
+// GetMigration returns the registered step from one version to another or nil if there is none
+func GetMigration(from int, to int) *Migration {
+	for _, migration := range migrations {
+		if migration.From == from && migration.To == to {
+			return migration
+		}
+	}
+	return nil
+}
+
+// MigrationPath returns all steps needed to migrate from one version to another
+func MigrationPath(from int, to int) ([]*Migration, error) {
+	var path []*Migration
+	for current := from; current != to; {
+		next := current + 1
+		if to < from {
+			next = current - 1
+		}
+		migration := GetMigration(current, next)
+		if migration == nil {
+			return nil, fmt.Errorf("no migration from version %d to %d available", current, next)
+		}
+		path = append(path, migration)
+		current = next
+	}
+	return path, nil
+}
+
+// Migrate converts the config document of the given format to the given version
+// the changes are applied to the document in place, so comments, unknown keys and the order of the keys are kept
+// toml documents are encoded as a whole and lose their comments
+// the result is validated against the json schema of the target version
+func Migrate(format FileFormat, contents []byte, to int) ([]byte, error) {
+
+	normalized, errNormalize := NormalizeDocument(format, contents)
+	if errNormalize != nil {
+		return nil, errNormalize
+	}
+
+	var versionBase VersionFixType
+	if errParse := json.Unmarshal(normalized, &versionBase); errParse != nil {
+		return nil, fmt.Errorf("could not parse json: %s", errParse.Error())
+	}
+
+	path, errPath := MigrationPath(versionBase.Version, to)
+	if errPath != nil {
+		return nil, errPath
+	}
+
+	output := contents
+	for _, migration := range path {
+		converted, errMigrate := migration.Migrate(normalized)
+		if errMigrate != nil {
+			return nil, fmt.Errorf("could not migrate from version %d to %d: %s", migration.From, migration.To, errMigrate.Error())
+		}
+		migrated, errEdit := migrateDocument(format, output, normalized, schemaModel(migration.From), converted)
+		if errEdit != nil {
+			return nil, fmt.Errorf("could not migrate from version %d to %d: %s", migration.From, migration.To, errEdit.Error())
+		}
+		migratedNormalized, errNormalize := NormalizeDocument(format, migrated)
+		if errNormalize != nil {
+			return nil, fmt.Errorf("could not migrate from version %d to %d: %s", migration.From, migration.To, errNormalize.Error())
+		}
+		if errValidate := ValidateDocument(migratedNormalized); errValidate != nil {
+			return nil, fmt.Errorf("migration from version %d to %d produced an invalid config: %s", migration.From, migration.To, errValidate.Error())
+		}
+		output, normalized = migrated, migratedNormalized
+	}
+
+	return output, nil
+
+}
+
+// MigrateFiles migrates the config file and its included files to the given version and keeps the original files as backup
+// all files have to use the same version, so they are migrated together: nothing is written if one of them can not be migrated
+// the files are locked while they are migrated, like every other write of a config file
+func MigrateFiles(fs afero.Fs, configPaths []string, to int) (backupPaths []string, err error) {
+
+	// the files are locked in a fixed order, so two migrations never wait for each other
+	lockOrder := append([]string{}, configPaths...)
+	sort.Strings(lockOrder)
+	for i, configPath := range lockOrder {
+		if i > 0 && lockOrder[i-1] == configPath {
+			continue
+		}
+		unlock, errLock := lockConfig(fs, configPath)
+		if errLock != nil {
+			return nil, errLock
+		}
+		defer unlock()
+	}
+
+	originals := make(map[string][]byte)
+	migrated := make(map[string][]byte)
+	versions := make(map[string]int)
+	for _, configPath := range configPaths {
+		contents, errRead := afero.ReadFile(fs, configPath)
+		if errRead != nil {
+			return nil, fmt.Errorf("could not read %s: %s", configPath, errRead.Error())
+		}
+		normalized, errNormalize := NormalizeDocument(DetectFileFormat(configPath), contents)
+		if errNormalize != nil {
+			return nil, fmt.Errorf("could not migrate %s: %s", configPath, errNormalize.Error())
+		}
+		var versionBase VersionFixType
+		_ = json.Unmarshal(normalized, &versionBase)
+		if versionBase.Version == to {
+			continue
+		}
+		migratedContents, errMigrate := Migrate(DetectFileFormat(configPath), contents, to)
+		if errMigrate != nil {
+			return nil, fmt.Errorf("could not migrate %s: %s", configPath, errMigrate.Error())
+		}
+		originals[configPath], migrated[configPath], versions[configPath] = contents, migratedContents, versionBase.Version
+	}
+
+	for _, configPath := range configPaths {
+		if migrated[configPath] == nil {
+			continue
+		}
+		backupPath := fmt.Sprintf("%s.v%d.bak", configPath, versions[configPath])
+		if errBackup := afero.WriteFile(fs, backupPath, originals[configPath], 0664); errBackup != nil {
+			return nil, fmt.Errorf("could not write backup %s: %s", backupPath, errBackup.Error())
+		}
+		backupPaths = append(backupPaths, backupPath)
+	}
+
+	// the files written before a failed write are restored, so the files never use different versions
+	var written []string
+	for _, configPath := range configPaths {
+		if migrated[configPath] == nil {
+			continue
+		}
+		if errWrite := writeConfigFile(fs, configPath, migrated[configPath]); errWrite != nil {
+			for _, writtenPath := range written {
+				_ = writeConfigFile(fs, writtenPath, originals[writtenPath])
+			}
+			return nil, fmt.Errorf("could not migrate %s: %s", configPath, errWrite.Error())
+		}
+		written = append(written, configPath)
+	}
+
+	return backupPaths, nil
+
+}
+
+// migrateDocument applies the converted config to the document, toml documents can not be edited in place
+func migrateDocument(format FileFormat, contents []byte, normalized []byte, model interface{}, converted []byte) ([]byte, error) {
+	if format != FileFormatToml {
+		return editDocument(format, contents, model, converted)
+	}
+	edited, errEdit := editDocument(FileFormatJson, normalized, model, converted)
+	if errEdit != nil {
+		return nil, errEdit
+	}
+	return EncodeDocument(format, edited)
+}
+
+// schemaModel returns an empty schema of the version which is used to read the keys known to it
+func schemaModel(version int) interface{} {
+	if IsSchemaV1(version) {
+		return &V1Schema{}
+	}
+	return &V2Schema{}
+}
+
+// ValidateDocument validates the config document against the json schema and the rules of its version
+func ValidateDocument(input []byte) error {
+
+	var versionBase VersionFixType
+	if errParse := json.Unmarshal(input, &versionBase); errParse != nil {
+		return fmt.Errorf("could not parse json: %s", errParse.Error())
+	}
+
+	var schemaLoader gojsonschema.JSONLoader
+	var validate func() error
+
+	switch {
+	case IsSchemaV1(versionBase.Version):
+		var parsed V1Schema
+		schemaLoader = jsonLoaderV1
+		validate = func() error {
+			if errParse := json.Unmarshal(input, &parsed); errParse != nil {
+				return errParse
+			}
+			return parsed.validateSchemaV1()
+		}
+	case IsSchemaV2(versionBase.Version):
+		var parsed V2Schema
+		schemaLoader = jsonLoaderV2
+		validate = func() error {
+			if errParse := json.Unmarshal(input, &parsed); errParse != nil {
+				return errParse
+			}
+			return parsed.validateSchemaV2()
+		}
+	default:
+		return fmt.Errorf("unsupported version: %d", versionBase.Version)
+	}
+
+	res, errValidate := gojsonschema.Validate(schemaLoader, gojsonschema.NewStringLoader(string(input)))
+	if errValidate != nil {
+		return fmt.Errorf("could not validate json schema: %s", errValidate.Error())
+	}
+
+	if res.Valid() == false {
+		var schemaErrors []string
+		for _, schemaErr := range res.Errors() {
+			schemaErrors = append(schemaErrors, schemaErr.String())
+		}
+		return fmt.Errorf("invalid json passed: %s", strings.Join(schemaErrors, ", "))
+	}
+
+	return validate()
+
+}
+
+// migrateV1ToV2 wraps every secret and config value into an entry without metadata
+func migrateV1ToV2(input []byte) ([]byte, error) {
+
+	var v1 V1Schema
+	if errParse := json.Unmarshal(input, &v1); errParse != nil {
+		return nil, fmt.Errorf("could not parse v1: %s", errParse.Error())
+	}
+
+	v2 := V2Schema{
+		Schema:      migrateSchemaUrl(v1.Schema, 1, 2),
+		Version:     2,
+		Include:     v1.Include,
+		Context:     make(V2Context),
+		RenderFiles: v1.RenderFiles,
+		Exec:        v1.Exec,
+	}
+
+	for contextName, context := range v1.Context {
+		v2.Context[contextName] = &V2ContextAwareSecrets{
+			Extends:       context.Extends,
+			DecryptSecret: context.DecryptSecret,
+			Secrets:       wrapEntries(context.Secrets),
+			Configs:       wrapEntries(context.Configs),
+		}
+	}
+
+	return json.MarshalIndent(v2, "", "  ")
+
+}
+
+// migrateV2ToV1 unwraps every entry, fails if an entry holds metadata since v1 can not store it
+func migrateV2ToV1(input []byte) ([]byte, error) {
+
+	var v2 V2Schema
+	if errParse := json.Unmarshal(input, &v2); errParse != nil {
+		return nil, fmt.Errorf("could not parse v2: %s", errParse.Error())
+	}
+
+	v1 := V1Schema{
+		Schema:      migrateSchemaUrl(v2.Schema, 2, 1),
+		Version:     1,
+		Include:     v2.Include,
+		Context:     make(V1Context),
+		RenderFiles: v2.RenderFiles,
+		Exec:        v2.Exec,
+	}
+
+	for _, contextName := range sortedContextNames(v2.Context) {
+		context := v2.Context[contextName]
+		secrets, errSecrets := unwrapEntries(context.Secrets)
+		if errSecrets != nil {
+			return nil, fmt.Errorf("secret %s in context %s", errSecrets.Error(), contextName)
+		}
+		configs, errConfigs := unwrapEntries(context.Configs)
+		if errConfigs != nil {
+			return nil, fmt.Errorf("config entry %s in context %s", errConfigs.Error(), contextName)
+		}
+		v1.Context[contextName] = &V1ContextAwareSecrets{
+			Extends:       context.Extends,
+			DecryptSecret: context.DecryptSecret,
+			Secrets:       secrets,
+			Configs:       configs,
+		}
+	}
+
+	return json.MarshalIndent(v1, "", "  ")
+
+}
+
+// wrapEntries converts plain values to entries without metadata
+func wrapEntries(values map[string]string) map[string]*V2Entry {
+	if values == nil {
+		return nil
+	}
+	entries := make(map[string]*V2Entry)
+	for key, value := range values {
+		entries[key] = &V2Entry{Value: value}
+	}
+	return entries
+}
+
+// unwrapEntries converts entries to plain values, fails if an entry holds metadata
+func unwrapEntries(entries map[string]*V2Entry) (map[string]string, error) {
+	if entries == nil {
+		return nil, nil
+	}
+	values := make(map[string]string)
+	for _, key := range sortedV2Keys(entries) {
+		if !entries[key].metadata().IsEmpty() {
+			return nil, fmt.Errorf("%s holds metadata which can not be stored in version 1", key)
+		}
+		values[key] = entries[key].Value
+	}
+	return values, nil
+}
+
+// migrateSchemaUrl points a $schema url of the official definitions to the new version
+func migrateSchemaUrl(schemaUrl string, from int, to int) string {
+	return strings.Replace(schemaUrl, fmt.Sprintf("schema/def/v%d.json", from), fmt.Sprintf("schema/def/v%d.json", to), 1)
+}
+
+// sortedContextNames returns the context names sorted alphabetically
+func sortedContextNames(contexts V2Context) []string {
+	var names []string
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,225 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func readTestFile(t *testing.T, fileName string) []byte {
+	fileBytes, errRead := testFiles.ReadFile(fmt.Sprintf("test_fs/%s", fileName))
+	assert.NoError(t, errRead)
+	return fileBytes
+}
+
+func TestMigrationPath(t *testing.T) {
+	t.Run("return the steps in order", func(t *testing.T) {
+		path, errPath := MigrationPath(1, 2)
+		assert.NoError(t, errPath)
+		assert.Len(t, path, 1)
+		assert.Equal(t, 1, path[0].From)
+		assert.Equal(t, 2, path[0].To)
+	})
+	t.Run("return no steps for the same version", func(t *testing.T) {
+		path, errPath := MigrationPath(2, 2)
+		assert.NoError(t, errPath)
+		assert.Len(t, path, 0)
+	})
+	t.Run("fail if a step is missing", func(t *testing.T) {
+		_, errPath := MigrationPath(1, LatestVersion+1)
+		assert.Error(t, errPath)
+	})
+}
+
+func TestMigrate(t *testing.T) {
+
+	t.Run("every registered step round-trips", func(t *testing.T) {
+		inputs := map[int]string{1: TestFileRealWorld, 2: TestFileV2}
+		for _, migration := range migrations {
+			back := GetMigration(migration.To, migration.From)
+			if !assert.NotNil(t, back, "missing reverse migration of %d -> %d", migration.From, migration.To) {
+				continue
+			}
+			input := readTestFile(t, inputs[migration.From])
+			if migration.From == 2 {
+				// metadata can not be stored in v1, start with a migrated v1 file
+				migrated, errMigrate := Migrate(FileFormatJson, readTestFile(t, TestFileRealWorld), 2)
+				assert.NoError(t, errMigrate)
+				input = migrated
+			}
+			migrated, errMigrate := migration.Migrate(input)
+			assert.NoError(t, errMigrate)
+			assert.NoError(t, ValidateDocument(migrated))
+			restored, errRestore := back.Migrate(migrated)
+			assert.NoError(t, errRestore)
+			assert.JSONEq(t, normalizeJson(t, input), normalizeJson(t, restored))
+		}
+	})
+
+	t.Run("migrate v1 to v2", func(t *testing.T) {
+		migrated, errMigrate := Migrate(FileFormatJson, readTestFile(t, TestFileRealWorld), 2)
+		assert.NoError(t, errMigrate)
+
+		var parsed V2Schema
+		assert.NoError(t, json.Unmarshal(migrated, &parsed))
+		assert.Equal(t, 2, parsed.Version)
+		assert.Equal(t, "https://raw.githubusercontent.com/benammann/git-secrets/dev-beta/schema/def/v2.json", parsed.Schema)
+		assert.Equal(t, "database-prod.svc.cluster", parsed.Context["prod"].Configs["databaseHost"].Value)
+		assert.Equal(t, "gitSecretsTest", parsed.Context["default"].DecryptSecret.FromName)
+		assert.Len(t, parsed.RenderFiles["env"].Files, 1)
+	})
+
+	t.Run("keep the includes, unknown keys and the order of the keys", func(t *testing.T) {
+		input := []byte(`{
+  "version": 1,
+  "x-team": "backend",
+  "include": ["services/*.json"],
+  "context": {
+    "default": {
+      "decryptSecret": {"fromName": "gitSecretsTest"},
+      "x-comment": "shared by all contexts",
+      "configs": {"databaseHost": "database.svc.local"}
+    }
+  }
+}`)
+		migrated, errMigrate := Migrate(FileFormatJson, input, 2)
+		assert.NoError(t, errMigrate)
+		assert.Equal(t, `{
+  "version": 2,
+  "x-team": "backend",
+  "include": ["services/*.json"],
+  "context": {
+    "default": {
+      "decryptSecret": {"fromName": "gitSecretsTest"},
+      "x-comment": "shared by all contexts",
+      "configs": {"databaseHost": {
+        "value": "database.svc.local"
+      }}
+    }
+  }
+}`, string(migrated))
+
+		restored, errRestore := Migrate(FileFormatJson, migrated, 1)
+		assert.NoError(t, errRestore)
+		assert.JSONEq(t, string(input), string(restored))
+	})
+
+	t.Run("keep the comments and the order of the keys of yaml files", func(t *testing.T) {
+		input := []byte(`# shared by the backend team
+version: 1
+context:
+  default:
+    decryptSecret:
+      fromName: gitSecretsTest
+    configs:
+      databaseHost: database.svc.local # the primary
+`)
+		migrated, errMigrate := Migrate(FileFormatYaml, input, 2)
+		assert.NoError(t, errMigrate)
+		assert.Equal(t, `# shared by the backend team
+version: 2
+context:
+  default:
+    decryptSecret:
+      fromName: gitSecretsTest
+    configs:
+      databaseHost: # the primary
+        value: database.svc.local
+`, string(migrated))
+	})
+
+	t.Run("fail to migrate metadata to v1", func(t *testing.T) {
+		_, errMigrate := Migrate(FileFormatJson, readTestFile(t, TestFileV2), 1)
+		assert.Error(t, errMigrate)
+	})
+
+	t.Run("keep the document if already at the version", func(t *testing.T) {
+		input := readTestFile(t, TestFileV2)
+		migrated, errMigrate := Migrate(FileFormatJson, input, 2)
+		assert.NoError(t, errMigrate)
+		assert.Equal(t, input, migrated)
+	})
+
+}
+
+func TestMigrateFiles(t *testing.T) {
+
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	assert.NoError(t, globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, false))
+
+	// copies the include fixtures into a writable file system
+	newFs := func(t *testing.T) afero.Fs {
+		fs := afero.NewMemMapFs()
+		for _, fileName := range []string{TestFileInclude, "include/services/api.json", "include/services/web.json"} {
+			assert.NoError(t, afero.WriteFile(fs, "/repo/"+fileName, readTestFile(t, fileName), 0664))
+		}
+		return fs
+	}
+
+	t.Run("migrate the config file and its included files", func(t *testing.T) {
+		fs := newFs(t)
+		repo, errParse := ParseRepository(fs, "/repo/"+TestFileInclude, globalConfig, map[string]string{})
+		assert.NoError(t, errParse)
+		configPaths := append([]string{repo.GetConfigFileUsed()}, repo.GetIncludedFiles()...)
+		assert.Len(t, configPaths, 3)
+
+		backupPaths, errMigrate := MigrateFiles(fs, configPaths, 2)
+		assert.NoError(t, errMigrate)
+		assert.Len(t, backupPaths, 3)
+		for _, configPath := range configPaths {
+			assert.Contains(t, backupPaths, configPath+".v1.bak")
+		}
+
+		migrated, errParse := ParseRepository(fs, "/repo/"+TestFileInclude, globalConfig, map[string]string{})
+		assert.NoError(t, errParse)
+		_, errContext := migrated.SetSelectedContext("default")
+		assert.NoError(t, errContext)
+		assert.Equal(t, 2, migrated.GetConfigVersion())
+		assert.Equal(t, "8080", migrated.GetConfigMap()["apiPort"])
+
+		backup, errBackup := afero.ReadFile(fs, "/repo/include/services/api.json.v1.bak")
+		assert.NoError(t, errBackup)
+		assert.Equal(t, readTestFile(t, "include/services/api.json"), backup)
+
+		files, errDir := afero.ReadDir(fs, "/repo/include/services")
+		assert.NoError(t, errDir)
+		assert.Len(t, files, 4, "the locks and the temporary files are removed")
+	})
+
+	t.Run("write none of the files if one of them can not be migrated", func(t *testing.T) {
+		fs := newFs(t)
+		assert.NoError(t, afero.WriteFile(fs, "/repo/include/services/web.json", []byte(`{"version": 1, "context": {"default": {"configs": {"webPort": 8080}}}}`), 0664))
+		configPaths := []string{"/repo/" + TestFileInclude, "/repo/include/services/api.json", "/repo/include/services/web.json"}
+		_, errMigrate := MigrateFiles(fs, configPaths, 2)
+		assert.Error(t, errMigrate)
+		assert.Contains(t, errMigrate.Error(), "could not migrate /repo/include/services/web.json")
+
+		for _, fileName := range []string{TestFileInclude, "include/services/api.json"} {
+			contents, errRead := afero.ReadFile(fs, "/repo/"+fileName)
+			assert.NoError(t, errRead)
+			assert.Equal(t, readTestFile(t, fileName), contents)
+			exists, _ := afero.Exists(fs, "/repo/"+fileName+".v1.bak")
+			assert.False(t, exists)
+		}
+	})
+
+}
+
+func TestValidateDocument(t *testing.T) {
+	assert.NoError(t, ValidateDocument(readTestFile(t, TestFileRealWorld)))
+	assert.NoError(t, ValidateDocument(readTestFile(t, TestFileV2)))
+	assert.Error(t, ValidateDocument(readTestFile(t, TestFileInvalidVersion)))
+	assert.Error(t, ValidateDocument([]byte(`{"version": 2, "context": {"default": {"decryptSecret": {"fromName": "test"}, "configs": {"key": "plain"}}}}`)))
+}
+
+// normalizeJson re-marshals the document to compare it independent of formatting
+func normalizeJson(t *testing.T, input []byte) string {
+	var parsed interface{}
+	assert.NoError(t, json.Unmarshal(input, &parsed))
+	out, errMarshal := json.Marshal(parsed)
+	assert.NoError(t, errMarshal)
+	return string(out)
+}
@@ -1,7 +1,9 @@
 package config_generic
 
 import (
+	"fmt"
 	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
 	"github.com/benammann/git-secrets/pkg/config/writer"
 )
 
@@ -25,6 +27,9 @@ type Repository struct {
 	// configFileUsed holds the abs path of the used config file
 	configFileUsed string
 
+	// includedFiles holds the abs paths of all config files included by the used config file
+	includedFiles []string
+
 	// context holds the current resolved context
 	context *Context
 
@@ -42,6 +47,23 @@ type Repository struct {
 
 	// configWriter allows to manipulate the current config
 	configWriter writer.ConfigWriter
+
+	// execConfig configures the environment variables of git secrets exec
+	execConfig *ExecConfig
+
+	// globalConfig and overwrittenSecrets are used to rebuild the repository after a write
+	globalConfig       *global_config.GlobalConfigProvider
+	overwrittenSecrets map[string]string
+
+	// hook is notified about decoded secrets and config changes, it is kept when the repository is rebuilt
+	hook Hook
+}
+
+// definitionSource is implemented by the config writers which describe the written config
+// the repository is rebuilt from this definition after each write to stay in sync with the config file
+type definitionSource interface {
+	definition() (*repositoryDefinition, error)
+	setOnWrite(onWrite func() error)
 }
 
 // GetConfigVersion returns the config version this repository is built from
@@ -49,12 +71,66 @@ func (c *Repository) GetConfigVersion() int {
 	return c.configVersion
 }
 
+// GetConfigFileUsed returns the abs path of the config file this repository is built from
+func (c *Repository) GetConfigFileUsed() string {
+	return c.configFileUsed
+}
+
+// GetIncludedFiles returns the abs paths of all config files included by the used config file
+func (c *Repository) GetIncludedFiles() []string {
+	return c.includedFiles
+}
+
 // IsDefault returns if the default context is used
 func (c *Repository) IsDefault() bool {
 	return c.context.Name == config_const.DefaultContextName
 }
 
-// GetConfigWriter returns the current config writer
+// reload rebuilds the repository from the config writer, the selected context is kept if it still exists
+func (c *Repository) reload() error {
+
+	source, isSource := c.configWriter.(definitionSource)
+	if !isSource {
+		return nil
+	}
+
+	definition, errDefinition := source.definition()
+	if errDefinition != nil {
+		return fmt.Errorf("could not reload the config: %s", errDefinition.Error())
+	}
+
+	rebuilt, errBuild := buildRepository(definition, c.globalConfig, c.overwrittenSecrets)
+	if errBuild != nil {
+		return fmt.Errorf("could not reload the config: %s", errBuild.Error())
+	}
+
+	selectedContext := c.context
+	hook := c.hook
+
+	*c = *rebuilt
+	c.hook = hook
+	source.setOnWrite(c.reload)
+
+	// the secrets reference the rebuilt repository which is replaced by this one
+	for _, secret := range c.secrets {
+		secret.repository = c
+	}
+
+	if selectedContext != nil {
+		c.context = c.GetContext(selectedContext.Name)
+		if c.context == nil {
+			c.context = c.GetDefault()
+		}
+	}
+
+	return nil
+
+}
+
+// GetConfigWriter returns the current config writer, the calls are passed to the hook if there is one
 func (c *Repository) GetConfigWriter() writer.ConfigWriter {
+	if c.hook != nil {
+		return &hookedWriter{ConfigWriter: c.configWriter, repository: c}
+	}
 	return c.configWriter
 }
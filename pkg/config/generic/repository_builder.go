@@ -0,0 +1,320 @@
+package config_generic
+
+import (
+	"fmt"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/benammann/git-secrets/pkg/config/writer"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"path/filepath"
+	"sort"
+)
+
+// repositoryDefinition is the schema independent description of a repository
+// each schema version converts its parsed file into a definition which is then built by buildRepository
+type repositoryDefinition struct {
+	version        int
+	configFileUsed string
+	configWriter   writer.ConfigWriter
+	include        []string
+	includedFiles  []string
+	files          []*fileDefinition
+	contexts       map[string]*contextDefinition
+	renderFiles    map[string]*renderTargetDefinition
+	exec           *V1Exec
+
+	// untrusted is set for config files which are not part of the working tree, e.g. historic versions
+	// their decryptSecret is never used, the keys are resolved by the contexts of the same name of keySource
+	untrusted bool
+	keySource *Repository
+}
+
+type contextDefinition struct {
+	source        string
+	extends       string
+	decryptSecret *V1DecryptSecret
+	// decryptSecretSource is the config file defining the decryptSecret, relative paths of fromFile are resolved from its directory
+	decryptSecretSource string
+	secrets             map[string]*entryDefinition
+	configs             map[string]*entryDefinition
+}
+
+type entryDefinition struct {
+	value    string
+	metadata Metadata
+	source   string
+}
+
+// fileDefinition describes a single config file the repository is built from
+type fileDefinition struct {
+	configFileUsed string
+	configWriter   writer.ConfigWriter
+	contexts       []string
+}
+
+type renderTargetDefinition struct {
+	target *V1RenderTarget
+	source string
+}
+
+// buildRepository creates the repository from the definition
+func buildRepository(definition *repositoryDefinition, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string) (*Repository, error) {
+
+	// all resulting contexts
+	var contexts []*Context
+
+	// all render targets to add
+	var renderTargets []*RenderTarget
+
+	// first, initialize all contexts
+	for contextKey, contextValue := range definition.contexts {
+		localContext := &Context{
+			Name:             contextKey,
+			EncryptedSecrets: entryValues(contextValue.secrets),
+			Configs:          entryValues(contextValue.configs),
+		}
+		contexts = append(contexts, localContext)
+	}
+
+	// link every context to the context it extends, the default context is the parent of all other contexts
+	for _, context := range contexts {
+		if context.Name == config_const.DefaultContextName {
+			continue
+		}
+		parentName := definition.contexts[context.Name].extends
+		if parentName == "" {
+			parentName = config_const.DefaultContextName
+		}
+		for _, parent := range contexts {
+			if parent.Name == parentName {
+				context.Parent = parent
+			}
+		}
+		if context.Parent == nil {
+			return nil, fmt.Errorf("context %s extends the context %s which does not exist", context.Name, parentName)
+		}
+	}
+
+	// important, always parents first since some logics depend on fully defined parent contexts
+	sort.SliceStable(contexts, func(i, j int) bool {
+		if len(contexts[i].Ancestors()) != len(contexts[j].Ancestors()) {
+			return len(contexts[i].Ancestors()) < len(contexts[j].Ancestors())
+		}
+		return contexts[i].Name < contexts[j].Name
+	})
+
+	for _, context := range contexts {
+		definedContext := definition.contexts[context.Name]
+		if definition.untrusted {
+			context.SecretResolver = keySourceResolver(definition.keySource, context)
+		} else {
+			context.SecretResolver = getSecretResolverV1(definedContext.decryptSecret, context.Parent, globalConfig, overwrittenSecrets, definedContext.decryptSecretSource)
+		}
+		context.Encryption = encryption.NewAesEngine(context.SecretResolver)
+	}
+
+	for targetName, targetDefinition := range definition.renderFiles {
+		renderTarget := targetDefinition.target
+		if renderTarget.Files == nil && renderTarget.Kubernetes == nil {
+			continue
+		}
+		finalRenderTarget := NewRenderTarget(targetName)
+		configDir := filepath.Dir(targetDefinition.source)
+		for _, partial := range renderTarget.Partials {
+			if errAddPartial := finalRenderTarget.AddPartial(filepath.Join(configDir, partial)); errAddPartial != nil {
+				return nil, fmt.Errorf("could not add partial %s to target %s: %s", partial, finalRenderTarget.Name, errAddPartial.Error())
+			}
+		}
+		for _, fileToRender := range renderTarget.Files {
+			fileIn := filepath.Join(configDir, fileToRender.FileIn)
+			fileOut := filepath.Join(configDir, fileToRender.FileOut)
+			errAddFile := finalRenderTarget.AddFileToRender(fileIn, fileOut)
+			if errAddFile != nil {
+				return nil, fmt.Errorf("could not add file (%s -> %s) to target %s: %s", fileToRender.FileIn, fileToRender.FileOut, finalRenderTarget.Name, errAddFile.Error())
+			}
+		}
+		if k8s := renderTarget.Kubernetes; k8s != nil {
+			errAddManifest := finalRenderTarget.AddKubernetesManifest(filepath.Join(configDir, k8s.FileOut), &KubernetesManifest{
+				Name:        k8s.Name,
+				Namespace:   k8s.Namespace,
+				Labels:      k8s.Labels,
+				Annotations: k8s.Annotations,
+				Kinds:       k8s.Kinds,
+				StringData:  k8s.StringData,
+				SecretKeys:  k8s.SecretKeys,
+				ConfigKeys:  k8s.ConfigKeys,
+			})
+			if errAddManifest != nil {
+				return nil, fmt.Errorf("could not add kubernetes manifest %s to target %s: %s", k8s.FileOut, finalRenderTarget.Name, errAddManifest.Error())
+			}
+		}
+		renderTargets = append(renderTargets, finalRenderTarget)
+	}
+
+	var secrets []*Secret
+
+	for _, context := range contexts {
+		for secretKey, secretEntry := range definition.contexts[context.Name].secrets {
+			secrets = append(secrets, &Secret{
+				Name:          secretKey,
+				OriginContext: context,
+				EncodedValue:  secretEntry.value,
+				SourceFile:    secretEntry.source,
+				Metadata:      inheritMetadata(secretEntry, ancestorEntries(definition, context, secretKey, true)),
+			})
+		}
+	}
+
+	var configs []*Config
+	for _, context := range contexts {
+		for configKey, configEntry := range definition.contexts[context.Name].configs {
+			configs = append(configs, &Config{
+				Name:          configKey,
+				Value:         configEntry.value,
+				SourceFile:    configEntry.source,
+				OriginContext: context,
+				Metadata:      inheritMetadata(configEntry, ancestorEntries(definition, context, configKey, false)),
+			})
+		}
+	}
+
+	repository := NewRepository(definition.version, definition.configFileUsed, definition.configWriter)
+	repository.includedFiles = definition.includedFiles
+	repository.globalConfig = globalConfig
+	repository.overwrittenSecrets = overwrittenSecrets
+
+	if source, isSource := definition.configWriter.(definitionSource); isSource {
+		source.setOnWrite(repository.reload)
+	}
+
+	for _, resultingContext := range contexts {
+		errAddContext := repository.AddContext(resultingContext)
+		if errAddContext != nil {
+			return nil, fmt.Errorf("could not add context to repository: %s", errAddContext.Error())
+		}
+	}
+
+	for _, secretOut := range secrets {
+		errAddSecret := repository.AddSecret(secretOut)
+		if errAddSecret != nil {
+			return nil, fmt.Errorf("could not add secret to repository: %s", errAddSecret.Error())
+		}
+	}
+
+	for _, configOut := range configs {
+		errAddConfig := repository.AddConfig(configOut)
+		if errAddConfig != nil {
+			return nil, fmt.Errorf("could not add config to repository: %s", errAddConfig.Error())
+		}
+	}
+
+	for _, renderTargetOut := range renderTargets {
+		if errAddTarget := repository.AddRenderTarget(renderTargetOut); errAddTarget != nil {
+			return nil, fmt.Errorf("could not add render target: %s", errAddTarget.Error())
+		}
+	}
+
+	if definition.exec != nil {
+		repository.SetExecConfig(&ExecConfig{
+			Prefix: definition.exec.Prefix,
+			Case:   definition.exec.Case,
+			Env:    definition.exec.Env,
+		})
+	}
+
+	return repository, nil
+
+}
+
+// unresolvedSecretResolver is used by untrusted config files if the key source does not define the default context
+type unresolvedSecretResolver struct {
+	contextName string
+}
+
+func (u *unresolvedSecretResolver) GetPlainSecret() (secret []byte, errResolve error) {
+	return nil, fmt.Errorf("the context %s does not exist in the working tree config, keys of config files outside of the working tree are only resolved by it", u.contextName)
+}
+
+// keySourceResolver returns the secret resolver of the context of the same name of the key source
+// a context which does not exist in the key source uses the resolver of its parent like a context without decryptSecret
+func keySourceResolver(keySource *Repository, context *Context) encryption.SecretResolver {
+	if keySource != nil {
+		if keySourceContext := keySource.GetContext(context.Name); keySourceContext != nil {
+			return keySourceContext.SecretResolver
+		}
+	}
+	if context.Parent != nil {
+		return context.Parent.SecretResolver
+	}
+	return &unresolvedSecretResolver{contextName: context.Name}
+}
+
+// renderTargetDefinitions references the config file of each render target, paths are relative to it
+func renderTargetDefinitions(renderFiles map[string]*V1RenderTarget, configFileUsed string) map[string]*renderTargetDefinition {
+	definitions := make(map[string]*renderTargetDefinition)
+	for targetName, renderTarget := range renderFiles {
+		definitions[targetName] = &renderTargetDefinition{target: renderTarget, source: configFileUsed}
+	}
+	return definitions
+}
+
+// entryValues returns the plain values of the entries
+func entryValues(entries map[string]*entryDefinition) map[string]string {
+	if entries == nil {
+		return nil
+	}
+	values := make(map[string]string)
+	for key, entry := range entries {
+		values[key] = entry.value
+	}
+	return values
+}
+
+// ancestorEntries returns the entries of the ancestors of the context, the nearest first
+func ancestorEntries(definition *repositoryDefinition, context *Context, key string, isSecret bool) (res []*entryDefinition) {
+	for _, ancestor := range context.Ancestors() {
+		entries := definition.contexts[ancestor.Name].configs
+		if isSecret {
+			entries = definition.contexts[ancestor.Name].secrets
+		}
+		if entries[key] != nil {
+			res = append(res, entries[key])
+		}
+	}
+	return res
+}
+
+// inheritMetadata fills the description, owner, type, rotation interval and value rules of an overwriting entry from the nearest ancestor entry defining it
+// the expiry and rotation dates are never inherited since every context has its own value
+func inheritMetadata(entry *entryDefinition, ancestorEntries []*entryDefinition) Metadata {
+	metadata := entry.metadata
+	for _, ancestorEntry := range ancestorEntries {
+		if metadata.Description == "" {
+			metadata.Description = ancestorEntry.metadata.Description
+		}
+		if metadata.Owner == "" {
+			metadata.Owner = ancestorEntry.metadata.Owner
+		}
+		if metadata.RotateAfter == "" {
+			metadata.RotateAfter = ancestorEntry.metadata.RotateAfter
+		}
+		inheritRules(&metadata, ancestorEntry.metadata)
+	}
+	return metadata
+}
+
+// inheritRules fills the value rules which are not set from the metadata of an ancestor entry
+func inheritRules(metadata *Metadata, ancestorMetadata Metadata) {
+	if metadata.Type == "" {
+		metadata.Type = ancestorMetadata.Type
+	}
+	if metadata.Pattern == "" {
+		metadata.Pattern = ancestorMetadata.Pattern
+	}
+	if metadata.MinLength == 0 {
+		metadata.MinLength = ancestorMetadata.MinLength
+	}
+	if metadata.Charset == "" {
+		metadata.Charset = ancestorMetadata.Charset
+	}
+}
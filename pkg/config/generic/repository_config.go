@@ -4,6 +4,7 @@ import (
 	"fmt"
 	config_const "github.com/benammann/git-secrets/pkg/config/const"
 	"sort"
+	"strings"
 )
 
 type Config struct {
@@ -16,31 +17,37 @@ type Config struct {
 
 	// OriginContext references the configured context to decode the secret
 	OriginContext *Context
+
+	// Metadata annotates the entry, only available since schema v2
+	Metadata Metadata
+
+	// SourceFile holds the abs path of the config file defining the entry
+	SourceFile string
 }
 
 // AddConfig adds a Config to the repository
 // also does some validations
 func (c *Repository) AddConfig(Config *Config) error {
 
-	// if not default Config we need to check if the given Config is also configured in the default context
+	// if not default Config we need to check if the given Config is also configured in an ancestor context
 	// because we are not allowed to define variables only in a child context
 	if Config.OriginContext.Name != config_const.DefaultContextName {
 
-		// get all the default Configs
-		defaultConfigs := c.GetConfigsByContext(config_const.DefaultContextName)
-		defaultConfigFound := false
+		ancestorConfigFound := false
 
-		// check if it defined
-		for _, defaultConfig := range defaultConfigs {
-			if defaultConfig.Name == Config.Name {
-				defaultConfigFound = true
-				break
+		// check if it is defined in one of the ancestors
+		for _, ancestor := range Config.OriginContext.Ancestors() {
+			for _, ancestorConfig := range c.GetConfigsByContext(ancestor.Name) {
+				if ancestorConfig.Name == Config.Name {
+					ancestorConfigFound = true
+					break
+				}
 			}
 		}
 
 		// return error if not defined
-		if defaultConfigFound == false {
-			return fmt.Errorf("config %s defined in context %s is not defined in the default context", Config.Name, Config.OriginContext.Name)
+		if ancestorConfigFound == false {
+			return fmt.Errorf("config %s defined in context %s is not defined in the parent contexts %s", Config.Name, Config.OriginContext.Name, strings.Join(Config.OriginContext.ChainNames()[1:], ", "))
 		}
 
 	}
@@ -66,41 +73,20 @@ func (c *Repository) GetConfigsByContext(contextName string) (res []*Config) {
 	return res
 }
 
-// GetCurrentConfigs merges the default Configs with the context Configs
-// the default Configs are overwritten by the context Configs
+// GetCurrentConfigs merges the Configs of the current context with the Configs of its ancestors
+// the Configs of a context overwrite the Configs of its ancestors
 func (c *Repository) GetCurrentConfigs() (res []*Config) {
 
-	// get all default Configs
-	defaultConfigs := c.GetConfigsByContext(config_const.DefaultContextName)
+	found := make(map[string]bool)
 
-	// if not default, merge the Configs with the default Configs
-	if !c.IsDefault() {
-
-		// result is context Configs
-		contextConfigs := c.GetConfigsByContext(c.context.Name)
-
-		// add the default Config if it is missing in the context Configs
-		for _, defaultConfig := range defaultConfigs {
-
-			found := false
-			for _, contextConfig := range contextConfigs {
-				if defaultConfig.Name == contextConfig.Name {
-					found = true
-					break
-				}
-			}
-
-			if found == false {
-				contextConfigs = append(contextConfigs, defaultConfig)
+	for _, context := range c.context.Chain() {
+		for _, config := range c.GetConfigsByContext(context.Name) {
+			if found[config.Name] {
+				continue
 			}
-
+			found[config.Name] = true
+			res = append(res, config)
 		}
-
-		res = contextConfigs
-
-	} else {
-		// result is just the default Configs
-		res = defaultConfigs
 	}
 
 	return res
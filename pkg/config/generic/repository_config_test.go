@@ -156,4 +156,16 @@ func TestRepository_GetCurrentConfigs(t *testing.T) {
 	assert.Equal(t, "3306", prodConfigMap["databasePort"].ConfigValue)
 	assert.Equal(t, "default", prodConfigMap["databasePort"].OriginContextName)
 
+	t.Run("resolve configs along the extends chain", func(t *testing.T) {
+		extendsRepo := initRepository(t, TestFileExtends, "prod-eu")
+		configMap := configsToMap(extendsRepo.GetCurrentConfigs())
+
+		assert.Equal(t, "database-prod.eu.svc.cluster", configMap["databaseHost"].ConfigValue)
+		assert.Equal(t, "prod-eu", configMap["databaseHost"].OriginContextName)
+		assert.Equal(t, "3307", configMap["databasePort"].ConfigValue)
+		assert.Equal(t, "prod", configMap["databasePort"].OriginContextName)
+		assert.Equal(t, "app", configMap["databaseName"].ConfigValue)
+		assert.Equal(t, "default", configMap["databaseName"].OriginContextName)
+	})
+
 }
@@ -13,6 +13,7 @@ type Context struct {
 	Encryption       encryption.Engine
 	EncryptedSecrets map[string]string
 	Configs          map[string]string
+	Parent           *Context
 }
 
 // AddContext adds a context and does some validations
@@ -28,6 +29,27 @@ func (c *Repository) AddContext(context *Context) error {
 	return nil
 }
 
+// Ancestors returns the parents of the context, the nearest first and the default context last
+func (c *Context) Ancestors() (res []*Context) {
+	for parent := c.Parent; parent != nil; parent = parent.Parent {
+		res = append(res, parent)
+	}
+	return res
+}
+
+// Chain returns the context followed by its ancestors, values are resolved in this order
+func (c *Context) Chain() []*Context {
+	return append([]*Context{c}, c.Ancestors()...)
+}
+
+// ChainNames returns the names of the contexts of Chain
+func (c *Context) ChainNames() (res []string) {
+	for _, context := range c.Chain() {
+		res = append(res, context.Name)
+	}
+	return res
+}
+
 // GetContext returns the context by name
 func (c *Repository) GetContext(contextName string) *Context {
 	for _, context := range c.contexts {
@@ -42,6 +64,44 @@ func (c *Repository) GetContexts() []*Context {
 	return c.contexts
 }
 
+// GetDescendantContexts returns the names of all contexts inheriting from the context
+func (c *Repository) GetDescendantContexts(contextName string) []string {
+	return descendantContexts(c.contextExtends(), contextName)
+}
+
+// GetRemovalContexts returns the names of the contexts a secret or config entry is removed from when removing it from the context
+// the overwrites of the child contexts are removed as well if no parent context defines the entry
+func (c *Repository) GetRemovalContexts(contextName string, entryName string, isSecret bool) []string {
+	return removalContexts(c.contextExtends(), contextName, func(contextName string) bool {
+		if isSecret {
+			for _, secret := range c.GetSecretsByContext(contextName) {
+				if secret.Name == entryName {
+					return true
+				}
+			}
+			return false
+		}
+		for _, config := range c.GetConfigsByContext(contextName) {
+			if config.Name == entryName {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// contextExtends returns the name of the parent of each context, empty for the default context
+func (c *Repository) contextExtends() map[string]string {
+	extends := make(map[string]string)
+	for _, context := range c.contexts {
+		extends[context.Name] = ""
+		if context.Parent != nil {
+			extends[context.Name] = context.Parent.Name
+		}
+	}
+	return extends
+}
+
 // GetDefault returns the default context
 func (c *Repository) GetDefault() *Context {
 	return c.GetContext(config_const.DefaultContextName)
@@ -62,6 +122,28 @@ func (c *Repository) GetCurrent() *Context {
 	return c.context
 }
 
+// KeyFingerprint resolves the secret of the context and returns its key fingerprint
+// the fingerprint of the resolved secret is returned even if it does not match the pinned fingerprint
+func (c *Context) KeyFingerprint() (string, error) {
+	secretResolver := c.SecretResolver
+	if pinned, isPinned := secretResolver.(*encryption.PinnedSecretResolver); isPinned {
+		secretResolver = pinned.Unpinned()
+	}
+	secret, errSecret := secretResolver.GetPlainSecret()
+	if errSecret != nil {
+		return "", errSecret
+	}
+	return encryption.KeyFingerprint(secret), nil
+}
+
+// PinnedKeyFingerprint returns the key fingerprint the context expects, empty if no fingerprint is pinned
+func (c *Context) PinnedKeyFingerprint() string {
+	if pinned, isPinned := c.SecretResolver.(*encryption.PinnedSecretResolver); isPinned {
+		return pinned.Pinned()
+	}
+	return ""
+}
+
 // EncodeValue encodes the given value and returns it as a base64 string
 func (c *Context) EncodeValue(plainValue string) (encodedValue string, err error) {
 	encodedString, errEncode := c.Encryption.EncodeValue(plainValue)
@@ -76,7 +158,7 @@ func (c *Context) EncodeValue(plainValue string) (encodedValue string, err error
 func (c *Context) DecodeValue(encodedValue string) (decodedValue string, err error) {
 	decodedBase64Bytes, errB64 := base64.StdEncoding.DecodeString(encodedValue)
 	if errB64 != nil {
-		return "", fmt.Errorf("could not decode base64 value: %s", errB64.Error())
+		return "", fmt.Errorf("%w: %s", encryption.ErrInvalidBase64, errB64.Error())
 	}
 	decodedString, errDecode := c.Encryption.DecodeValue(string(decodedBase64Bytes))
 	if errDecode != nil {
@@ -2,6 +2,7 @@ package config_generic
 
 import (
 	"encoding/base64"
+	"github.com/benammann/git-secrets/pkg/encryption"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -135,3 +136,35 @@ func TestRepository_SetSelectedContext(t *testing.T) {
 	assert.Nil(t, emptyOut)
 
 }
+
+func TestContext_Chain(t *testing.T) {
+	repo := initRepository(t, TestFileExtends, "default")
+
+	assert.Nil(t, repo.GetDefault().Parent)
+	assert.Equal(t, []string{"default"}, repo.GetDefault().ChainNames())
+	assert.Equal(t, []string{"prod", "default"}, repo.GetContext("prod").ChainNames())
+	assert.Equal(t, []string{"prod-eu", "prod", "default"}, repo.GetContext("prod-eu").ChainNames())
+	assert.Len(t, repo.GetContext("prod-eu").Ancestors(), 2)
+
+	t.Run("inherit the secret resolver from the parent context", func(t *testing.T) {
+		assert.Equal(t, repo.GetContext("prod").SecretResolver, repo.GetContext("prod-eu").SecretResolver)
+		assert.IsType(t, &encryption.FromEnvSecretResolver{}, repo.GetContext("prod-us").SecretResolver)
+	})
+
+	t.Run("decode secrets of an ancestor with the ancestor context", func(t *testing.T) {
+		_, errSelect := repo.SetSelectedContext("prod-us")
+		assert.NoError(t, errSelect)
+		secret := repo.GetCurrentSecret("databasePassword")
+		assert.Equal(t, "prod", secret.OriginContext.Name)
+		decoded, errDecode := secret.Decode()
+		assert.NoError(t, errDecode)
+		assert.NotEqual(t, "", decoded)
+	})
+}
+
+func TestRepository_GetRemovalContexts(t *testing.T) {
+	repo := initRepository(t, TestFileExtends, "default")
+	assert.Equal(t, []string{"default", "prod", "prod-eu"}, repo.GetRemovalContexts("default", "databaseHost", false))
+	assert.Equal(t, []string{"prod"}, repo.GetRemovalContexts("prod", "databaseHost", false))
+	assert.Equal(t, []string{"prod-eu", "prod-us"}, repo.GetDescendantContexts("prod"))
+}
@@ -0,0 +1,27 @@
+package config_generic
+
+// ExecConfig configures how secrets and configs are passed as environment variables to git secrets exec
+type ExecConfig struct {
+
+	// Prefix is prepended to every environment variable name
+	Prefix string
+
+	// Case describes the case transform of the keys, for example upper-snake
+	Case string
+
+	// Env maps secret or config keys to explicit environment variable names
+	Env map[string]string
+}
+
+// SetExecConfig sets the exec configuration
+func (c *Repository) SetExecConfig(execConfig *ExecConfig) {
+	c.execConfig = execConfig
+}
+
+// GetExecConfig returns the exec configuration, never nil
+func (c *Repository) GetExecConfig() *ExecConfig {
+	if c.execConfig == nil {
+		return &ExecConfig{}
+	}
+	return c.execConfig
+}
@@ -0,0 +1,27 @@
+package config_generic
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRepository_GetExecConfig(t *testing.T) {
+	t.Run("return an empty config if not configured", func(t *testing.T) {
+		repo := initRepository(t, TestFileBlankDefault, "default")
+		assert.Equal(t, &ExecConfig{}, repo.GetExecConfig())
+	})
+	t.Run("return the parsed exec config", func(t *testing.T) {
+		repo := initRepository(t, TestFileExec, "default")
+		execConfig := repo.GetExecConfig()
+		assert.Equal(t, "APP_", execConfig.Prefix)
+		assert.Equal(t, "upper-snake", execConfig.Case)
+		assert.Equal(t, map[string]string{"databaseHost": "DB_HOST"}, execConfig.Env)
+	})
+}
+
+func TestRepository_SetExecConfig(t *testing.T) {
+	repo := initRepository(t, TestFileBlankDefault, "default")
+	execConfig := &ExecConfig{Prefix: "APP_"}
+	repo.SetExecConfig(execConfig)
+	assert.Equal(t, execConfig, repo.GetExecConfig())
+}
@@ -0,0 +1,192 @@
+package config_generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pelletier/go-toml"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+type FileFormat string
+
+const (
+	FileFormatJson FileFormat = "json"
+	FileFormatYaml FileFormat = "yaml"
+	FileFormatToml FileFormat = "toml"
+)
+
+// FileFormats returns all supported config file formats
+func FileFormats() []FileFormat {
+	return []FileFormat{FileFormatJson, FileFormatYaml, FileFormatToml}
+}
+
+// DefaultConfigFileNames returns the config file names which are looked up if no file is passed
+func DefaultConfigFileNames() []string {
+	return []string{".git-secrets.json", ".git-secrets.yaml", ".git-secrets.yml", ".git-secrets.toml"}
+}
+
+// FindConfigFile searches the directory and its parents up to rootDir for the nearest config file named like DefaultConfigFileNames
+// rootDir is usually the git root, only the directory itself is searched if rootDir is empty or no parent of it
+// the ignored files are never returned, e.g. the global config file ~/.git-secrets.yaml which has a default config file name
+func FindConfigFile(fileSystem afero.Fs, dir string, rootDir string, ignoredFiles ...string) (string, bool) {
+	if relativeDir, errRel := filepath.Rel(rootDir, dir); rootDir == "" || errRel != nil || strings.HasPrefix(relativeDir, "..") {
+		rootDir = dir
+	}
+	for {
+		for _, fileName := range DefaultConfigFileNames() {
+			pathToFile := filepath.Join(dir, fileName)
+			if isIgnoredFile(pathToFile, ignoredFiles) {
+				continue
+			}
+			if _, errStat := fileSystem.Stat(pathToFile); errStat == nil {
+				return pathToFile, true
+			}
+		}
+		parentDir := filepath.Dir(dir)
+		if dir == rootDir || parentDir == dir {
+			return "", false
+		}
+		dir = parentDir
+	}
+}
+
+func isIgnoredFile(pathToFile string, ignoredFiles []string) bool {
+	for _, ignoredFile := range ignoredFiles {
+		if filepath.Clean(ignoredFile) == pathToFile {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultConfigFileName returns the default config file name of the format
+func DefaultConfigFileName(format FileFormat) string {
+	return fmt.Sprintf(".git-secrets.%s", format)
+}
+
+// IsFileFormat returns true if the format is supported
+func IsFileFormat(format string) bool {
+	for _, availableFormat := range FileFormats() {
+		if string(availableFormat) == format {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectFileFormat detects the format by the file extension, json is used for unknown extensions
+func DetectFileFormat(fileName string) FileFormat {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return FileFormatYaml
+	case ".toml":
+		return FileFormatToml
+	default:
+		return FileFormatJson
+	}
+}
+
+// NormalizeDocument converts the document to json which is then validated and parsed
+func NormalizeDocument(format FileFormat, contents []byte) ([]byte, error) {
+	switch format {
+	case FileFormatJson:
+		return contents, nil
+	case FileFormatYaml:
+		var document yaml.Node
+		if errParse := yaml.Unmarshal(contents, &document); errParse != nil {
+			return nil, fmt.Errorf("could not parse yaml: %s", errParse.Error())
+		}
+		keepYamlTimestamps(&document)
+		var parsed interface{}
+		if document.Kind != 0 {
+			if errDecode := document.Decode(&parsed); errDecode != nil {
+				return nil, fmt.Errorf("could not parse yaml: %s", errDecode.Error())
+			}
+		}
+		return json.Marshal(parsed)
+	case FileFormatToml:
+		tree, errParse := toml.LoadBytes(contents)
+		if errParse != nil {
+			return nil, fmt.Errorf("could not parse toml: %s", errParse.Error())
+		}
+		return json.Marshal(tree.ToMap())
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// EncodeDocument converts the json document to the format
+// yaml keeps the order of the json keys, toml sorts them alphabetically
+func EncodeDocument(format FileFormat, jsonContents []byte) ([]byte, error) {
+	switch format {
+	case FileFormatJson:
+		return jsonContents, nil
+	case FileFormatYaml:
+		var document yaml.Node
+		if errParse := yaml.Unmarshal(jsonContents, &document); errParse != nil {
+			return nil, fmt.Errorf("could not parse json: %s", errParse.Error())
+		}
+		resetYamlStyle(&document)
+		var out bytes.Buffer
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if errEncode := encoder.Encode(&document); errEncode != nil {
+			return nil, fmt.Errorf("could not encode yaml: %s", errEncode.Error())
+		}
+		return out.Bytes(), nil
+	case FileFormatToml:
+		var document map[string]interface{}
+		if errParse := json.Unmarshal(jsonContents, &document); errParse != nil {
+			return nil, fmt.Errorf("could not parse json: %s", errParse.Error())
+		}
+		var out bytes.Buffer
+		if errEncode := toml.NewEncoder(&out).Indentation("").Encode(integerNumbers(document)); errEncode != nil {
+			return nil, fmt.Errorf("could not encode toml: %s", errEncode.Error())
+		}
+		return out.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// keepYamlTimestamps reads unquoted dates as strings, so an expiry date like 2030-01-01 keeps its value instead of becoming a time
+func keepYamlTimestamps(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		keepYamlTimestamps(child)
+	}
+}
+
+// resetYamlStyle removes the json flow style and quoting so the document is written as block yaml
+func resetYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYamlStyle(child)
+	}
+}
+
+// integerNumbers converts whole json numbers to integers since toml distinguishes them from floats
+func integerNumbers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = integerNumbers(child)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = integerNumbers(child)
+		}
+	case float64:
+		if typed == math.Trunc(typed) {
+			return int64(typed)
+		}
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package config_generic
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetectFileFormat(t *testing.T) {
+	assert.Equal(t, FileFormatJson, DetectFileFormat(".git-secrets.json"))
+	assert.Equal(t, FileFormatYaml, DetectFileFormat("config/.git-secrets.yaml"))
+	assert.Equal(t, FileFormatYaml, DetectFileFormat(".git-secrets.YML"))
+	assert.Equal(t, FileFormatToml, DetectFileFormat(".git-secrets.toml"))
+	assert.Equal(t, FileFormatJson, DetectFileFormat("git-secrets"))
+}
+
+func TestEncodeDocument(t *testing.T) {
+
+	input := readTestFile(t, TestFileRealWorld)
+
+	for _, format := range FileFormats() {
+		t.Run(string(format), func(t *testing.T) {
+			encoded, errEncode := EncodeDocument(format, input)
+			assert.NoError(t, errEncode)
+			normalized, errNormalize := NormalizeDocument(format, encoded)
+			assert.NoError(t, errNormalize)
+			assert.JSONEq(t, string(input), string(normalized))
+			assert.NoError(t, ValidateDocument(normalized))
+		})
+	}
+
+	t.Run("keep the key order and quote numeric strings in yaml", func(t *testing.T) {
+		encoded, errEncode := EncodeDocument(FileFormatYaml, []byte(`{"version": 1, "context": {"default": {"configs": {"port": "3306"}}}}`))
+		assert.NoError(t, errEncode)
+		assert.Equal(t, "version: 1\ncontext:\n  default:\n    configs:\n      port: \"3306\"\n", string(encoded))
+	})
+
+	t.Run("write whole numbers as integers in toml", func(t *testing.T) {
+		encoded, errEncode := EncodeDocument(FileFormatToml, []byte(`{"version": 1}`))
+		assert.NoError(t, errEncode)
+		assert.Equal(t, "version = 1\n", string(encoded))
+	})
+
+}
+
+func TestNormalizeDocument(t *testing.T) {
+	_, errYaml := NormalizeDocument(FileFormatYaml, []byte("version: [1"))
+	assert.Error(t, errYaml)
+	_, errToml := NormalizeDocument(FileFormatToml, []byte("version = "))
+	assert.Error(t, errToml)
+	_, errFormat := NormalizeDocument("xml", []byte(""))
+	assert.Error(t, errFormat)
+}
@@ -0,0 +1,128 @@
+package config_generic
+
+import (
+	"github.com/benammann/git-secrets/pkg/config/writer"
+	"sort"
+)
+
+// Hook is notified when secrets are decoded and when the config is changed using the config writer
+// it is used to audit the access to the repository, the plain values are never passed
+type Hook interface {
+
+	// SecretDecoded is called after a secret has been decoded, errDecode is set if it could not be decoded
+	SecretDecoded(secret *Secret, errDecode error)
+
+	// ConfigChanged is called after each call of the config writer, errChange is set if the call failed
+	ConfigChanged(change *ConfigChange, errChange error)
+}
+
+// ConfigChange describes a call of the config writer
+type ConfigChange struct {
+
+	// Action is the name of the called method, e.g. SetSecret
+	Action string
+
+	// Context is the name of the changed context, empty if the change is not related to a context
+	Context string
+
+	// Names holds the names of the changed secrets, configs, contexts or files
+	Names []string
+}
+
+// SetHook sets the hook which is notified about decoded secrets and config changes, nil removes it
+func (c *Repository) SetHook(hook Hook) {
+	c.hook = hook
+}
+
+// notifySecretDecoded passes the decoded secret to the hook if there is one
+func (c *Repository) notifySecretDecoded(secret *Secret, errDecode error) {
+	if c.hook != nil {
+		c.hook.SecretDecoded(secret, errDecode)
+	}
+}
+
+// hookedWriter passes every call of the config writer to the hook of the repository
+type hookedWriter struct {
+	writer.ConfigWriter
+	repository *Repository
+}
+
+func (h *hookedWriter) notify(errChange error, action string, contextName string, names ...string) error {
+	if h.repository.hook != nil {
+		h.repository.hook.ConfigChanged(&ConfigChange{Action: action, Context: contextName, Names: names}, errChange)
+	}
+	return errChange
+}
+
+func (h *hookedWriter) SetSecret(contextName string, secretName string, secretEncodedValue string, force bool) error {
+	return h.notify(h.ConfigWriter.SetSecret(contextName, secretName, secretEncodedValue, force), "SetSecret", contextName, secretName)
+}
+
+func (h *hookedWriter) SetSecrets(contextName string, secrets map[string]string, force bool) error {
+	return h.notify(h.ConfigWriter.SetSecrets(contextName, secrets, force), "SetSecrets", contextName, mapNames(secrets)...)
+}
+
+func (h *hookedWriter) SetConfig(contextName string, configName string, configValue string, force bool) error {
+	return h.notify(h.ConfigWriter.SetConfig(contextName, configName, configValue, force), "SetConfig", contextName, configName)
+}
+
+func (h *hookedWriter) SetConfigs(contextName string, configs map[string]string, force bool) error {
+	return h.notify(h.ConfigWriter.SetConfigs(contextName, configs, force), "SetConfigs", contextName, mapNames(configs)...)
+}
+
+func (h *hookedWriter) AddContext(contextName string) error {
+	return h.notify(h.ConfigWriter.AddContext(contextName), "AddContext", contextName)
+}
+
+func (h *hookedWriter) AddFileToRender(targetName string, fileIn string, fileOut string) error {
+	return h.notify(h.ConfigWriter.AddFileToRender(targetName, fileIn, fileOut), "AddFileToRender", "", targetName, fileOut)
+}
+
+func (h *hookedWriter) RemoveSecret(contextName string, secretName string) error {
+	return h.notify(h.ConfigWriter.RemoveSecret(contextName, secretName), "RemoveSecret", contextName, secretName)
+}
+
+func (h *hookedWriter) RemoveConfig(contextName string, configName string) error {
+	return h.notify(h.ConfigWriter.RemoveConfig(contextName, configName), "RemoveConfig", contextName, configName)
+}
+
+func (h *hookedWriter) RemoveContext(contextName string) error {
+	return h.notify(h.ConfigWriter.RemoveContext(contextName), "RemoveContext", contextName)
+}
+
+func (h *hookedWriter) RemoveFileToRender(targetName string, fileOut string) error {
+	return h.notify(h.ConfigWriter.RemoveFileToRender(targetName, fileOut), "RemoveFileToRender", "", targetName, fileOut)
+}
+
+func (h *hookedWriter) RenameSecret(oldName string, newName string) error {
+	return h.notify(h.ConfigWriter.RenameSecret(oldName, newName), "RenameSecret", "", oldName, newName)
+}
+
+func (h *hookedWriter) RenameConfig(oldName string, newName string) error {
+	return h.notify(h.ConfigWriter.RenameConfig(oldName, newName), "RenameConfig", "", oldName, newName)
+}
+
+func (h *hookedWriter) RenameContext(oldName string, newName string) error {
+	return h.notify(h.ConfigWriter.RenameContext(oldName, newName), "RenameContext", "", oldName, newName)
+}
+
+func (h *hookedWriter) WriteConfig() error {
+	return h.notify(h.ConfigWriter.WriteConfig(), "WriteConfig", "")
+}
+
+func (h *hookedWriter) Commit() error {
+	return h.notify(h.ConfigWriter.Commit(), "Commit", "")
+}
+
+func (h *hookedWriter) Rollback() error {
+	return h.notify(h.ConfigWriter.Rollback(), "Rollback", "")
+}
+
+func mapNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
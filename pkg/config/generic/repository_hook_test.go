@@ -0,0 +1,68 @@
+package config_generic
+
+import (
+	"fmt"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// recordingHook records the calls of the hook as lines
+type recordingHook struct {
+	calls []string
+}
+
+func (r *recordingHook) SecretDecoded(secret *Secret, errDecode error) {
+	r.calls = append(r.calls, fmt.Sprintf("decode %s.%s %v", secret.OriginContext.Name, secret.Name, errDecode != nil))
+}
+
+func (r *recordingHook) ConfigChanged(change *ConfigChange, errChange error) {
+	r.calls = append(r.calls, fmt.Sprintf("%s %s %v %v", change.Action, change.Context, change.Names, errChange != nil))
+}
+
+func TestRepository_SetHook(t *testing.T) {
+
+	configPath := createSharedConfig(t, TestFileRealWorld)
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	assert.NoError(t, globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, true))
+	repo, errParse := ParseRepository(afero.NewOsFs(), configPath, globalConfig, map[string]string{})
+	assert.NoError(t, errParse)
+	_, errSelect := repo.SetSelectedContext("prod")
+	assert.NoError(t, errSelect)
+
+	t.Run("it should not wrap the writer without hook", func(t *testing.T) {
+		assert.IsType(t, &V1Writer{}, repo.GetConfigWriter())
+	})
+
+	hook := &recordingHook{}
+	repo.SetHook(hook)
+
+	t.Run("it should notify the hook about decoded secrets", func(t *testing.T) {
+		_, errDecode := repo.GetSecretsMapDecoded()
+		assert.NoError(t, errDecode)
+		assert.Equal(t, []string{"decode prod.databasePassword false"}, hook.calls)
+	})
+
+	t.Run("it should notify the hook about config changes", func(t *testing.T) {
+		hook.calls = nil
+		configWriter := repo.GetConfigWriter()
+		assert.NoError(t, configWriter.SetConfigs("prod", map[string]string{"databasePort": "3308", "databaseHost": "db"}, true))
+		assert.Error(t, configWriter.RemoveContext("missing"))
+		assert.NoError(t, configWriter.WriteConfig())
+		assert.Equal(t, []string{
+			"SetConfigs prod [databaseHost databasePort] false",
+			"RemoveContext missing [] true",
+			"WriteConfig  [] false",
+		}, hook.calls)
+	})
+
+	t.Run("it should keep the hook when the repository is rebuilt", func(t *testing.T) {
+		hook.calls = nil
+		assert.Equal(t, "3308", repo.GetCurrentConfig("databasePort").Value)
+		_, errDecode := repo.GetCurrentSecret("databasePassword").Decode()
+		assert.NoError(t, errDecode)
+		assert.Equal(t, []string{"decode prod.databasePassword false"}, hook.calls)
+	})
+
+}
@@ -0,0 +1,195 @@
+package config_generic
+
+import (
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+)
+
+// includeDefinitions parses all files included by the definition and merges them into it
+// include patterns are resolved relative to the including file and may contain wildcards
+func includeDefinitions(fileSystem afero.Fs, fileName string, definition *repositoryDefinition, includedBy []string) error {
+
+	for _, includePattern := range definition.include {
+
+		matches, errGlob := afero.Glob(fileSystem, filepath.Join(filepath.Dir(fileName), includePattern))
+		if errGlob != nil {
+			return fmt.Errorf("invalid include %s: %s", includePattern, errGlob.Error())
+		}
+
+		if len(matches) == 0 {
+			return fmt.Errorf("the include %s does not match any file", includePattern)
+		}
+
+		sort.Strings(matches)
+
+		for _, includedFile := range matches {
+			includedDefinition, errInclude := parseDefinitionFile(fileSystem, includedFile, includedBy)
+			if errInclude != nil {
+				return errInclude
+			}
+			if errMerge := mergeDefinition(definition, includedDefinition); errMerge != nil {
+				return errMerge
+			}
+		}
+
+	}
+
+	if len(definition.include) > 0 {
+		return validateDefinitionExtends(definition)
+	}
+
+	return nil
+
+}
+
+// validateDefinitionExtends validates the extends of the merged contexts, cycles may span multiple files
+func validateDefinitionExtends(definition *repositoryDefinition) error {
+	extends := make(map[string]string)
+	for contextName, context := range definition.contexts {
+		extends[contextName] = context.extends
+	}
+	return validateExtends(extends)
+}
+
+// mergeDefinition merges the included definition into the including one
+// there is no precedence between the files: each secret, config and render target must be owned by a single file
+// defining it in two files is an error naming both files, contexts are merged if their decryptSecret and extends do not differ
+func mergeDefinition(target *repositoryDefinition, source *repositoryDefinition) error {
+
+	if target.version != source.version {
+		return fmt.Errorf("%s uses version %d but %s uses version %d", target.configFileUsed, target.version, source.configFileUsed, source.version)
+	}
+
+	for _, contextName := range sortedDefinitionContexts(source.contexts) {
+
+		sourceContext := source.contexts[contextName]
+		targetContext := target.contexts[contextName]
+
+		if targetContext == nil {
+			targetContext = &contextDefinition{
+				source:  sourceContext.source,
+				secrets: make(map[string]*entryDefinition),
+				configs: make(map[string]*entryDefinition),
+			}
+			target.contexts[contextName] = targetContext
+		}
+
+		if sourceContext.extends != "" {
+			if targetContext.extends != "" && targetContext.extends != sourceContext.extends {
+				return fmt.Errorf("context %s extends %s in %s but %s in %s", contextName, targetContext.extends, targetContext.source, sourceContext.extends, sourceContext.source)
+			}
+			targetContext.extends = sourceContext.extends
+		}
+
+		if sourceContext.decryptSecret != nil {
+			if targetContext.decryptSecret != nil && !sameDecryptSecret(targetContext, sourceContext) {
+				return fmt.Errorf("context %s has a different decryptSecret in %s and %s", contextName, targetContext.decryptSecretSource, sourceContext.decryptSecretSource)
+			}
+			if targetContext.decryptSecret == nil {
+				targetContext.decryptSecret = sourceContext.decryptSecret
+				targetContext.decryptSecretSource = sourceContext.decryptSecretSource
+			}
+		}
+
+		for entryName, entry := range sourceContext.secrets {
+			if owner := definitionEntryOwner(target, entryName, true); owner != "" && owner != entry.source {
+				return fmt.Errorf("secret %s is defined in %s and %s", entryName, owner, entry.source)
+			}
+			targetContext.secrets[entryName] = entry
+		}
+
+		for entryName, entry := range sourceContext.configs {
+			if owner := definitionEntryOwner(target, entryName, false); owner != "" && owner != entry.source {
+				return fmt.Errorf("config %s is defined in %s and %s", entryName, owner, entry.source)
+			}
+			targetContext.configs[entryName] = entry
+		}
+
+	}
+
+	for targetName, renderTarget := range source.renderFiles {
+		if existing := target.renderFiles[targetName]; existing != nil && existing.source != renderTarget.source {
+			return fmt.Errorf("render target %s is defined in %s and %s", targetName, existing.source, renderTarget.source)
+		}
+		target.renderFiles[targetName] = renderTarget
+	}
+
+	// the exec config of the including file wins, otherwise the first included one is used
+	if target.exec == nil {
+		target.exec = source.exec
+	}
+
+	// a file may be included by multiple files, it is only listed once
+	for _, file := range source.files {
+		if definitionFile(target, file.configFileUsed) == nil {
+			target.files = append(target.files, file)
+			target.includedFiles = append(target.includedFiles, file.configFileUsed)
+		}
+	}
+
+	return nil
+
+}
+
+// sameDecryptSecret returns true if both contexts resolve the same key
+// a relative fromFile is resolved from the directory of each file, so the same value may point to different files
+func sameDecryptSecret(a *contextDefinition, b *contextDefinition) bool {
+	if *a.decryptSecret != *b.decryptSecret {
+		return false
+	}
+	if a.decryptSecret.FromFile == "" {
+		return true
+	}
+	pathA, errA := encryption.NewFileSecretResolver(a.decryptSecret.FromFile, filepath.Dir(a.decryptSecretSource)).Path()
+	pathB, errB := encryption.NewFileSecretResolver(b.decryptSecret.FromFile, filepath.Dir(b.decryptSecretSource)).Path()
+	return errA == nil && errB == nil && pathA == pathB
+}
+
+// definitionEntryOwner returns the file defining the secret or config in any context, empty if it is not defined
+func definitionEntryOwner(definition *repositoryDefinition, entryName string, isSecret bool) string {
+	for _, contextName := range sortedDefinitionContexts(definition.contexts) {
+		entries := definition.contexts[contextName].configs
+		if isSecret {
+			entries = definition.contexts[contextName].secrets
+		}
+		if entry := entries[entryName]; entry != nil {
+			return entry.source
+		}
+	}
+	return ""
+}
+
+// definitionFile returns the file of the definition or nil if the file is not part of it
+func definitionFile(definition *repositoryDefinition, configFileUsed string) *fileDefinition {
+	for _, file := range definition.files {
+		if file.configFileUsed == configFileUsed {
+			return file
+		}
+	}
+	return nil
+}
+
+func sortedDefinitionContexts(contexts map[string]*contextDefinition) []string {
+	var names []string
+	for contextName := range contexts {
+		names = append(names, contextName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ownFileDefinition describes the file the definition is parsed from, the contexts are marked as defined by it
+func ownFileDefinition(definition *repositoryDefinition) *fileDefinition {
+	file := &fileDefinition{
+		configFileUsed: definition.configFileUsed,
+		configWriter:   definition.configWriter,
+	}
+	for _, contextName := range sortedDefinitionContexts(definition.contexts) {
+		definition.contexts[contextName].source = definition.configFileUsed
+		file.contexts = append(file.contexts, contextName)
+	}
+	return file
+}
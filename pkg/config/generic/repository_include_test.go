@@ -0,0 +1,318 @@
+package config_generic
+
+import (
+	"encoding/json"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const TestFileInclude = "include/root.json"
+const TestFileIncludeConflict = "include/conflict/root.json"
+const TestFileIncludeCycle = "include/cycle/a.json"
+const TestFileIncludeMissing = "include/missing.json"
+
+func TestParseRepository_Include(t *testing.T) {
+
+	t.Run("it should merge the included files", func(t *testing.T) {
+		repo := initRepository(t, TestFileInclude, "default")
+		configs := repo.GetConfigMap()
+		assert.Equal(t, "database.svc.local", configs["databaseHost"])
+		assert.Equal(t, "8080", configs["apiPort"])
+		assert.Equal(t, "http://localhost:3000", configs["webUrl"])
+		assert.Len(t, repo.GetIncludedFiles(), 2)
+		assert.Equal(t, "api.json", filepath.Base(repo.GetIncludedFiles()[0]))
+		assert.Equal(t, "web.json", filepath.Base(repo.GetIncludedFiles()[1]))
+	})
+
+	t.Run("it should merge the contexts of the included files", func(t *testing.T) {
+		repo := initRepository(t, TestFileInclude, "prod")
+		configs := repo.GetConfigMap()
+		assert.Equal(t, "database-prod.svc.cluster", configs["databaseHost"])
+		assert.Equal(t, "8443", configs["apiPort"])
+		assert.Equal(t, "http://localhost:3000", configs["webUrl"])
+	})
+
+	t.Run("it should decode secrets of included files with the decryptSecret of the including file", func(t *testing.T) {
+		repo := initRepository(t, TestFileInclude, "default")
+		secrets, errDecode := repo.GetSecretsMapDecoded()
+		assert.NoError(t, errDecode)
+		assert.Contains(t, secrets, "apiToken")
+		assert.Contains(t, secrets, "databasePassword")
+	})
+
+	t.Run("it should reference the source file of each entry", func(t *testing.T) {
+		repo := initRepository(t, TestFileInclude, "default")
+		assert.Equal(t, "root.json", filepath.Base(repo.GetCurrentConfig("databaseHost").SourceFile))
+		assert.Equal(t, "api.json", filepath.Base(repo.GetCurrentConfig("apiPort").SourceFile))
+		assert.Equal(t, "api.json", filepath.Base(repo.GetCurrentSecret("apiToken").SourceFile))
+	})
+
+	t.Run("it should resolve render files relative to the included file", func(t *testing.T) {
+		repo := initRepository(t, TestFileInclude, "default")
+		target := repo.GetRenderTarget("api")
+		assert.NotNil(t, target)
+		assert.Equal(t, filepath.Join("services", "api", "env.dist"), relativeToInclude(t, target.FilesToRender[0].FileIn))
+	})
+
+	t.Run("it should resolve a relative fromFile from the directory of the file defining the context", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "/repo/root.json", []byte(`{"version": 1, "include": ["services/api.json"], "context": {"default": {"decryptSecret": {"fromName": "gitSecretsTest"}}}}`), 0644))
+		assert.NoError(t, afero.WriteFile(fs, "/repo/services/api.json", []byte(`{"version": 1, "context": {"default": {"decryptSecret": {"fromName": "gitSecretsTest"}}, "ci": {"decryptSecret": {"fromFile": "keys/ci.key"}}}}`), 0644))
+		repo, errParse := ParseRepository(fs, "/repo/root.json", global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider()), map[string]string{})
+		assert.NoError(t, errParse)
+		path, errPath := repo.GetContext("ci").SecretResolver.(*encryption.FromFileSecretResolver).Path()
+		assert.NoError(t, errPath)
+		assert.Equal(t, filepath.FromSlash("/repo/services/keys/ci.key"), path)
+	})
+
+	t.Run("it should fail if a relative fromFile points to different files", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "/repo/root.json", []byte(`{"version": 1, "include": ["services/api.json"], "context": {"default": {"decryptSecret": {"fromFile": "ci.key"}}}}`), 0644))
+		assert.NoError(t, afero.WriteFile(fs, "/repo/services/api.json", []byte(`{"version": 1, "context": {"default": {"decryptSecret": {"fromFile": "ci.key"}}}}`), 0644))
+		_, errParse := ParseRepository(fs, "/repo/root.json", global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider()), map[string]string{})
+		assert.ErrorContains(t, errParse, "context default has a different decryptSecret")
+	})
+
+	t.Run("it should fail if a secret is defined in two files", func(t *testing.T) {
+		_, errParse := createTestRepository(TestFileIncludeConflict, "default")
+		assert.ErrorContains(t, errParse, "secret apiToken is defined in")
+		assert.ErrorContains(t, errParse, filepath.Join("conflict", "root.json"))
+		assert.ErrorContains(t, errParse, filepath.Join("services", "api.json"))
+	})
+
+	t.Run("it should fail on cyclic includes", func(t *testing.T) {
+		_, errParse := createTestRepository(TestFileIncludeCycle, "default")
+		assert.ErrorContains(t, errParse, "cyclic include")
+	})
+
+	t.Run("it should fail if an include does not match any file", func(t *testing.T) {
+		_, errParse := createTestRepository(TestFileIncludeMissing, "default")
+		assert.ErrorContains(t, errParse, "does not match any file")
+	})
+
+}
+
+func relativeToInclude(t *testing.T, pathToFile string) string {
+	includeDir, _ := filepath.Abs("test_fs/include")
+	relativeFile, errRel := filepath.Rel(includeDir, pathToFile)
+	assert.NoError(t, errRel)
+	return relativeFile
+}
+
+// createIncludeRepository copies the include fixtures to a temp directory since the writers use the os file system
+func createIncludeRepository(t *testing.T) (*Repository, string) {
+	tempDir := t.TempDir()
+	for _, fileName := range []string{"root.json", "services/api.json", "services/web.json"} {
+		contents, errRead := testFiles.ReadFile("test_fs/include/" + fileName)
+		assert.NoError(t, errRead)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tempDir, fileName)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(tempDir, fileName), contents, 0644))
+	}
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	repo, errParse := ParseRepository(afero.NewOsFs(), filepath.Join(tempDir, "root.json"), globalConfig, map[string]string{})
+	assert.NoError(t, errParse)
+	_, errSelect := repo.SetSelectedContext("default")
+	assert.NoError(t, errSelect)
+	return repo, tempDir
+}
+
+func readIncludeSchema(t *testing.T, pathToFile string) V1Schema {
+	contents, errRead := os.ReadFile(pathToFile)
+	assert.NoError(t, errRead)
+	var schema V1Schema
+	assert.NoError(t, json.Unmarshal(contents, &schema))
+	return schema
+}
+
+func TestMultiFileWriter(t *testing.T) {
+
+	t.Run("it should write an existing entry to its owning file", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().SetConfig("default", "apiPort", "9090", true))
+		assert.Equal(t, "9090", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["default"].Configs["apiPort"])
+		assert.Empty(t, readIncludeSchema(t, filepath.Join(tempDir, "root.json")).Context["default"].Configs["apiPort"])
+	})
+
+	t.Run("it should write new entries to the including file", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().SetConfig("default", "newKey", "newValue", false))
+		assert.Equal(t, "newValue", readIncludeSchema(t, filepath.Join(tempDir, "root.json")).Context["default"].Configs["newKey"])
+	})
+
+	t.Run("it should split multiple entries by their owning files", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().SetConfigs("prod", map[string]string{
+			"apiPort":      "9443",
+			"databaseHost": "database.eu",
+		}, true))
+		assert.Equal(t, "9443", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["prod"].Configs["apiPort"])
+		assert.Equal(t, "database.eu", readIncludeSchema(t, filepath.Join(tempDir, "root.json")).Context["prod"].Configs["databaseHost"])
+	})
+
+	t.Run("it should add the context to the owning file if it is defined in another file", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().SetConfig("prod", "webUrl", "https://example.com", false))
+		assert.Equal(t, "https://example.com", readIncludeSchema(t, filepath.Join(tempDir, "services/web.json")).Context["prod"].Configs["webUrl"])
+	})
+
+	t.Run("it should not set entries of an unknown context", func(t *testing.T) {
+		repo, _ := createIncludeRepository(t)
+		assert.Error(t, repo.GetConfigWriter().SetConfig("staging", "webUrl", "https://example.com", false))
+	})
+
+	t.Run("it should not add a context which exists in an included file", func(t *testing.T) {
+		repo, _ := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().AddContext("staging"))
+		assert.Error(t, repo.GetConfigWriter().AddContext("staging"))
+		assert.Error(t, repo.GetConfigWriter().AddContext("prod"))
+	})
+
+	t.Run("it should add files to the render target of the owning file", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().AddFileToRender("api", "api/config.dist", "api/config.json"))
+		assert.Len(t, readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).RenderFiles["api"].Files, 2)
+		assert.Nil(t, readIncludeSchema(t, filepath.Join(tempDir, "root.json")).RenderFiles)
+	})
+
+}
+
+func TestFindConfigFile(t *testing.T) {
+
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/repo/.git-secrets.json", []byte("{}"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "/repo/services/api/.git-secrets.yaml", []byte("{}"), 0644))
+	assert.NoError(t, fs.MkdirAll("/repo/services/web/src", 0755))
+
+	assert.NoError(t, afero.WriteFile(fs, "/home/user/.git-secrets.yaml", []byte("secrets: {}"), 0644))
+	assert.NoError(t, fs.MkdirAll("/home/user/projects/app", 0755))
+
+	t.Run("it should find the config file in the directory", func(t *testing.T) {
+		configFile, found := FindConfigFile(fs, "/repo/services/api", "/repo")
+		assert.True(t, found)
+		assert.Equal(t, "/repo/services/api/.git-secrets.yaml", configFile)
+	})
+
+	t.Run("it should find the nearest config file of the parents", func(t *testing.T) {
+		configFile, found := FindConfigFile(fs, "/repo/services/web/src", "/repo")
+		assert.True(t, found)
+		assert.Equal(t, "/repo/.git-secrets.json", configFile)
+	})
+
+	t.Run("it should return false if there is no config file", func(t *testing.T) {
+		_, found := FindConfigFile(fs, "/other", "/other")
+		assert.False(t, found)
+	})
+
+	t.Run("it should stop the search at the root directory", func(t *testing.T) {
+		_, found := FindConfigFile(fs, "/repo/services/web/src", "/repo/services")
+		assert.False(t, found)
+		_, found = FindConfigFile(fs, "/repo/services/web/src", "")
+		assert.False(t, found)
+		_, found = FindConfigFile(fs, "/repo/services/web/src", "/other")
+		assert.False(t, found)
+	})
+
+	t.Run("it should skip the ignored files", func(t *testing.T) {
+		_, found := FindConfigFile(fs, "/home/user/projects/app", "/home/user", "/home/user/.git-secrets.yaml")
+		assert.False(t, found)
+		configFile, found := FindConfigFile(fs, "/home/user/projects/app", "/home/user")
+		assert.True(t, found)
+		assert.Equal(t, "/home/user/.git-secrets.yaml", configFile)
+	})
+
+}
+
+func TestMultiFileWriter_RemoveAndRename(t *testing.T) {
+
+	t.Run("it should remove the entry from its owning file", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().RemoveConfig("default", "apiPort"))
+		assert.NotContains(t, readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["prod"].Configs, "apiPort")
+		assert.Error(t, repo.GetConfigWriter().RemoveConfig("default", "apiPort"))
+	})
+
+	t.Run("it should rename the entry in its owning file", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.Error(t, repo.GetConfigWriter().RenameSecret("apiToken", "databasePassword"))
+		assert.NoError(t, repo.GetConfigWriter().RenameSecret("apiToken", "serviceToken"))
+		assert.Contains(t, readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["default"].Secrets, "serviceToken")
+	})
+
+	t.Run("it should rename the context in all files", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().RenameContext("prod", "production"))
+		assert.NotNil(t, readIncludeSchema(t, filepath.Join(tempDir, "root.json")).Context["production"])
+		assert.NotNil(t, readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["production"])
+		assert.NoError(t, repo.GetConfigWriter().RemoveContext("production"))
+		assert.Nil(t, readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["production"])
+		assert.Error(t, repo.GetConfigWriter().RemoveContext("production"))
+	})
+
+}
+
+func TestMultiFileWriter_Transaction(t *testing.T) {
+
+	t.Run("it should not write any file if one change fails", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		assert.Error(t, repo.GetConfigWriter().SetConfigs("prod", map[string]string{
+			"apiPort":    "9443",
+			"unknownKey": "value",
+		}, true))
+		assert.Equal(t, "8443", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["prod"].Configs["apiPort"])
+		assert.Equal(t, "8443", repo.GetConfigsByContext("prod")[0].Value)
+	})
+
+	t.Run("it should write all files at commit", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		configWriter := repo.GetConfigWriter()
+		assert.NoError(t, configWriter.Begin())
+		assert.NoError(t, configWriter.SetConfig("default", "apiPort", "9090", true))
+		assert.NoError(t, configWriter.SetConfig("default", "databaseHost", "db", true))
+		assert.Equal(t, "8080", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["default"].Configs["apiPort"])
+		assert.NoError(t, configWriter.Commit())
+		assert.Equal(t, "9090", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["default"].Configs["apiPort"])
+		assert.Equal(t, "db", readIncludeSchema(t, filepath.Join(tempDir, "root.json")).Context["default"].Configs["databaseHost"])
+		assert.Equal(t, "9090", repo.GetConfigMap()["apiPort"])
+		assert.Equal(t, "api.json", filepath.Base(repo.GetCurrentConfig("apiPort").SourceFile))
+	})
+
+	t.Run("it should restore the routing on rollback", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		configWriter := repo.GetConfigWriter()
+		assert.NoError(t, configWriter.Begin())
+		assert.NoError(t, configWriter.RenameConfig("apiPort", "port"))
+		assert.NoError(t, configWriter.Rollback())
+		assert.NoError(t, configWriter.SetConfig("default", "apiPort", "9090", true))
+		assert.Equal(t, "9090", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["default"].Configs["apiPort"])
+	})
+
+	t.Run("it should restore the written files if a later file can not be written", func(t *testing.T) {
+		repo, tempDir := createIncludeRepository(t)
+		rootBefore, errRead := os.ReadFile(filepath.Join(tempDir, "root.json"))
+		assert.NoError(t, errRead)
+
+		// the temporary file of api.json can not be created, so api.json is written after root.json and fails
+		assert.NoError(t, os.Mkdir(filepath.Join(tempDir, "services/api.json.tmp"), 0755))
+		assert.Error(t, repo.GetConfigWriter().SetConfigs("prod", map[string]string{
+			"apiPort":      "9443",
+			"databaseHost": "database.eu",
+		}, true))
+
+		rootAfter, errRead := os.ReadFile(filepath.Join(tempDir, "root.json"))
+		assert.NoError(t, errRead)
+		assert.Equal(t, string(rootBefore), string(rootAfter))
+		assert.Equal(t, "8443", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["prod"].Configs["apiPort"])
+
+		// the writers hold the restored schemas, so the next write does not contain the failed change
+		_ = os.Remove(filepath.Join(tempDir, "services/api.json.tmp"))
+		assert.NoError(t, repo.GetConfigWriter().SetConfig("prod", "apiPort", "9090", true))
+		assert.Equal(t, "database-prod.svc.cluster", readIncludeSchema(t, filepath.Join(tempDir, "root.json")).Context["prod"].Configs["databaseHost"])
+		assert.Equal(t, "9090", readIncludeSchema(t, filepath.Join(tempDir, "services/api.json")).Context["prod"].Configs["apiPort"])
+	})
+
+}
@@ -0,0 +1,624 @@
+package config_generic
+
+import (
+	"fmt"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+)
+
+// MultiFileWriter writes the changes back to the config file owning the secret, config or render target
+// new entries are written to the including config file
+type MultiFileWriter struct {
+	files        []*fileDefinition
+	secretOwners map[string]*fileDefinition
+	configOwners map[string]*fileDefinition
+	targetOwners map[string]*fileDefinition
+	extends      map[string]string
+
+	// snapshot holds the state at the beginning of the running transaction, nil if no transaction is running
+	snapshot *multiFileSnapshot
+
+	// onWrite is called after the config files have been written
+	onWrite func() error
+}
+
+// checkpointWriter is implemented by the writers of a single config file
+type checkpointWriter interface {
+	checkpoint() (func() error, error)
+}
+
+// multiFileSnapshot holds the routing state of the MultiFileWriter which is restored by Rollback
+type multiFileSnapshot struct {
+	secretOwners map[string]*fileDefinition
+	configOwners map[string]*fileDefinition
+	targetOwners map[string]*fileDefinition
+	extends      map[string]string
+	contexts     map[*fileDefinition][]string
+}
+
+// newMultiFileWriter creates a writer for the files, the first file is the including config file
+func newMultiFileWriter(files []*fileDefinition, definition *repositoryDefinition) *MultiFileWriter {
+
+	w := &MultiFileWriter{
+		files:        files,
+		secretOwners: make(map[string]*fileDefinition),
+		configOwners: make(map[string]*fileDefinition),
+		targetOwners: make(map[string]*fileDefinition),
+		extends:      make(map[string]string),
+	}
+
+	for contextName, context := range definition.contexts {
+		w.extends[contextName] = context.extends
+		for secretName, secret := range context.secrets {
+			w.secretOwners[secretName] = w.file(secret.source)
+		}
+		for configName, config := range context.configs {
+			w.configOwners[configName] = w.file(config.source)
+		}
+	}
+
+	for targetName, renderTarget := range definition.renderFiles {
+		w.targetOwners[targetName] = w.file(renderTarget.source)
+	}
+
+	return w
+
+}
+
+func (w *MultiFileWriter) SetSecret(contextName string, secretName string, secretEncodedValue string, force bool) error {
+	return w.SetSecrets(contextName, map[string]string{secretName: secretEncodedValue}, force)
+}
+
+// SetSecrets groups the secrets by their owning file and sets them file by file
+func (w *MultiFileWriter) SetSecrets(contextName string, secrets map[string]string, force bool) error {
+	return w.apply(func() error {
+		return w.setSecrets(contextName, secrets, force)
+	})
+}
+
+func (w *MultiFileWriter) setSecrets(contextName string, secrets map[string]string, force bool) error {
+
+	for _, file := range w.files {
+		fileSecrets := ownedEntries(secrets, w.secretOwners, file, w.files[0])
+		if len(fileSecrets) == 0 {
+			continue
+		}
+		if errContext := w.ensureContext(file, contextName); errContext != nil {
+			return errContext
+		}
+		if errSet := file.configWriter.SetSecrets(contextName, fileSecrets, force); errSet != nil {
+			return fmt.Errorf("%s: %s", file.configFileUsed, errSet.Error())
+		}
+		for secretName := range fileSecrets {
+			w.secretOwners[secretName] = file
+		}
+	}
+
+	return nil
+
+}
+
+func (w *MultiFileWriter) SetConfig(contextName string, configName string, configValue string, force bool) error {
+	return w.SetConfigs(contextName, map[string]string{configName: configValue}, force)
+}
+
+// SetConfigs groups the config entries by their owning file and sets them file by file
+func (w *MultiFileWriter) SetConfigs(contextName string, configs map[string]string, force bool) error {
+	return w.apply(func() error {
+		return w.setConfigs(contextName, configs, force)
+	})
+}
+
+func (w *MultiFileWriter) setConfigs(contextName string, configs map[string]string, force bool) error {
+
+	for _, file := range w.files {
+		fileConfigs := ownedEntries(configs, w.configOwners, file, w.files[0])
+		if len(fileConfigs) == 0 {
+			continue
+		}
+		if errContext := w.ensureContext(file, contextName); errContext != nil {
+			return errContext
+		}
+		if errSet := file.configWriter.SetConfigs(contextName, fileConfigs, force); errSet != nil {
+			return fmt.Errorf("%s: %s", file.configFileUsed, errSet.Error())
+		}
+		for configName := range fileConfigs {
+			w.configOwners[configName] = file
+		}
+	}
+
+	return nil
+
+}
+
+// AddContext adds the context to the including config file
+func (w *MultiFileWriter) AddContext(contextName string) error {
+	return w.apply(func() error {
+		return w.addContext(contextName)
+	})
+}
+
+func (w *MultiFileWriter) addContext(contextName string) error {
+
+	for _, file := range w.files {
+		if file.hasContext(contextName) {
+			return fmt.Errorf("the context %s does already exist in %s", contextName, file.configFileUsed)
+		}
+	}
+
+	if errAdd := w.files[0].configWriter.AddContext(contextName); errAdd != nil {
+		return errAdd
+	}
+
+	w.files[0].contexts = append(w.files[0].contexts, contextName)
+	w.extends[contextName] = ""
+	return nil
+
+}
+
+// AddFileToRender adds the file to the config file owning the render target
+func (w *MultiFileWriter) AddFileToRender(targetName string, fileIn string, fileOut string) error {
+	return w.apply(func() error {
+		return w.addFileToRender(targetName, fileIn, fileOut)
+	})
+}
+
+func (w *MultiFileWriter) addFileToRender(targetName string, fileIn string, fileOut string) error {
+
+	file := w.targetOwners[targetName]
+	if file == nil {
+		file = w.files[0]
+	}
+
+	if errAdd := file.configWriter.AddFileToRender(targetName, fileIn, fileOut); errAdd != nil {
+		return errAdd
+	}
+
+	w.targetOwners[targetName] = file
+	return nil
+
+}
+
+// RemoveSecret removes the secret from the config file owning it
+func (w *MultiFileWriter) RemoveSecret(contextName string, secretName string) error {
+	return w.apply(func() error {
+		return w.removeEntry(contextName, secretName, w.secretOwners, true)
+	})
+}
+
+// RemoveConfig removes the config entry from the config file owning it
+func (w *MultiFileWriter) RemoveConfig(contextName string, configName string) error {
+	return w.apply(func() error {
+		return w.removeEntry(contextName, configName, w.configOwners, false)
+	})
+}
+
+func (w *MultiFileWriter) removeEntry(contextName string, key string, owners map[string]*fileDefinition, isSecret bool) error {
+
+	file := owners[key]
+	if file == nil {
+		return fmt.Errorf("the %s %s does not exist", entryKind(isSecret), key)
+	}
+
+	var errRemove error
+	if isSecret {
+		errRemove = file.configWriter.RemoveSecret(contextName, key)
+	} else {
+		errRemove = file.configWriter.RemoveConfig(contextName, key)
+	}
+	if errRemove != nil {
+		return fmt.Errorf("%s: %s", file.configFileUsed, errRemove.Error())
+	}
+
+	// removing the entry from the default context removes it from all contexts
+	if contextName == config_const.DefaultContextName {
+		delete(owners, key)
+	}
+
+	return nil
+
+}
+
+// RenameSecret renames the secret in the config file owning it
+func (w *MultiFileWriter) RenameSecret(oldName string, newName string) error {
+	return w.apply(func() error {
+		return w.renameEntry(oldName, newName, w.secretOwners, true)
+	})
+}
+
+// RenameConfig renames the config entry in the config file owning it
+func (w *MultiFileWriter) RenameConfig(oldName string, newName string) error {
+	return w.apply(func() error {
+		return w.renameEntry(oldName, newName, w.configOwners, false)
+	})
+}
+
+func (w *MultiFileWriter) renameEntry(oldName string, newName string, owners map[string]*fileDefinition, isSecret bool) error {
+
+	file := owners[oldName]
+	if file == nil {
+		return fmt.Errorf("the %s %s does not exist", entryKind(isSecret), oldName)
+	}
+
+	if existingOwner := owners[newName]; existingOwner != nil {
+		return fmt.Errorf("the %s %s does already exist in %s", entryKind(isSecret), newName, existingOwner.configFileUsed)
+	}
+
+	var errRename error
+	if isSecret {
+		errRename = file.configWriter.RenameSecret(oldName, newName)
+	} else {
+		errRename = file.configWriter.RenameConfig(oldName, newName)
+	}
+	if errRename != nil {
+		return fmt.Errorf("%s: %s", file.configFileUsed, errRename.Error())
+	}
+
+	owners[newName] = file
+	delete(owners, oldName)
+	return nil
+
+}
+
+// RemoveContext removes the context and all contexts extending it from all config files
+func (w *MultiFileWriter) RemoveContext(contextName string) error {
+	return w.apply(func() error {
+		return w.removeContext(contextName)
+	})
+}
+
+func (w *MultiFileWriter) removeContext(contextName string) error {
+
+	removedContexts := append([]string{contextName}, descendantContexts(w.extends, contextName)...)
+
+	contextFound := false
+	for _, file := range w.files {
+		if !file.hasContext(contextName) {
+			continue
+		}
+		contextFound = true
+		if errRemove := file.configWriter.RemoveContext(contextName); errRemove != nil {
+			return fmt.Errorf("%s: %s", file.configFileUsed, errRemove.Error())
+		}
+	}
+
+	if contextFound == false {
+		return fmt.Errorf("the context %s does not exist", contextName)
+	}
+
+	for _, removedContext := range removedContexts {
+		for _, file := range w.files {
+			file.removeContext(removedContext)
+		}
+		delete(w.extends, removedContext)
+	}
+
+	return nil
+
+}
+
+// RenameContext renames the context in all config files
+func (w *MultiFileWriter) RenameContext(oldName string, newName string) error {
+	return w.apply(func() error {
+		return w.renameContext(oldName, newName)
+	})
+}
+
+func (w *MultiFileWriter) renameContext(oldName string, newName string) error {
+
+	for _, file := range w.files {
+		if file.hasContext(newName) {
+			return fmt.Errorf("the context %s does already exist in %s", newName, file.configFileUsed)
+		}
+	}
+
+	contextFound := false
+	for _, file := range w.files {
+		if !file.hasContext(oldName) {
+			continue
+		}
+		contextFound = true
+		if errRename := file.configWriter.RenameContext(oldName, newName); errRename != nil {
+			return fmt.Errorf("%s: %s", file.configFileUsed, errRename.Error())
+		}
+		file.removeContext(oldName)
+		file.contexts = append(file.contexts, newName)
+	}
+
+	if contextFound == false {
+		return fmt.Errorf("the context %s does not exist", oldName)
+	}
+
+	w.extends[newName] = w.extends[oldName]
+	delete(w.extends, oldName)
+	for contextName, parentName := range w.extends {
+		if parentName == oldName {
+			w.extends[contextName] = newName
+		}
+	}
+
+	return nil
+
+}
+
+// RemoveFileToRender removes the file from the config file owning the render target
+func (w *MultiFileWriter) RemoveFileToRender(targetName string, fileOut string) error {
+	return w.apply(func() error {
+		return w.removeFileToRender(targetName, fileOut)
+	})
+}
+
+func (w *MultiFileWriter) removeFileToRender(targetName string, fileOut string) error {
+
+	file := w.targetOwners[targetName]
+	if file == nil {
+		return fmt.Errorf("the render target %s does not exist", targetName)
+	}
+
+	return file.configWriter.RemoveFileToRender(targetName, fileOut)
+
+}
+
+// Begin starts a transaction on all config files, the changes are validated and written at once by Commit
+func (w *MultiFileWriter) Begin() error {
+
+	if w.snapshot != nil {
+		return fmt.Errorf("a transaction is already running")
+	}
+
+	for i, file := range w.files {
+		if errBegin := file.configWriter.Begin(); errBegin != nil {
+			for _, begunFile := range w.files[:i] {
+				_ = begunFile.configWriter.Rollback()
+			}
+			return fmt.Errorf("%s: %s", file.configFileUsed, errBegin.Error())
+		}
+	}
+
+	w.snapshot = &multiFileSnapshot{
+		secretOwners: copyOwners(w.secretOwners),
+		configOwners: copyOwners(w.configOwners),
+		targetOwners: copyOwners(w.targetOwners),
+		extends:      make(map[string]string),
+		contexts:     make(map[*fileDefinition][]string),
+	}
+	for contextName, parentName := range w.extends {
+		w.snapshot.extends[contextName] = parentName
+	}
+	for _, file := range w.files {
+		w.snapshot.contexts[file] = append([]string{}, file.contexts...)
+	}
+
+	return nil
+
+}
+
+// Commit validates all config files before any of them is written, the changes are rolled back if one is not valid
+func (w *MultiFileWriter) Commit() error {
+
+	if w.snapshot == nil {
+		return fmt.Errorf("no transaction is running")
+	}
+
+	for _, file := range w.files {
+		if validator, isValidator := file.configWriter.(interface{ validate() error }); isValidator {
+			if errValidate := validator.validate(); errValidate != nil {
+				_ = w.Rollback()
+				return fmt.Errorf("%s: not writing config since it is not valid: %s", file.configFileUsed, errValidate.Error())
+			}
+		}
+	}
+
+	// the files are written one after another, so the files written before a failed write are restored
+	restores := make([]func() error, len(w.files))
+	for i, file := range w.files {
+		if checkpointer, isCheckpointer := file.configWriter.(checkpointWriter); isCheckpointer {
+			restore, errCheckpoint := checkpointer.checkpoint()
+			if errCheckpoint != nil {
+				_ = w.Rollback()
+				return fmt.Errorf("%s: %s", file.configFileUsed, errCheckpoint.Error())
+			}
+			restores[i] = restore
+		}
+	}
+
+	for i, file := range w.files {
+		if errCommit := file.configWriter.Commit(); errCommit != nil {
+			for _, restore := range restores[:i] {
+				if restore != nil {
+					_ = restore()
+				}
+			}
+			for _, remainingFile := range w.files[i+1:] {
+				_ = remainingFile.configWriter.Rollback()
+			}
+			w.restoreSnapshot()
+			return fmt.Errorf("%s: %s, no config file has been changed", file.configFileUsed, errCommit.Error())
+		}
+	}
+
+	w.snapshot = nil
+
+	if w.onWrite == nil {
+		return nil
+	}
+	return w.onWrite()
+
+}
+
+// Rollback discards the changes of the running transaction in all config files
+func (w *MultiFileWriter) Rollback() error {
+
+	if w.snapshot == nil {
+		return fmt.Errorf("no transaction is running")
+	}
+
+	for _, file := range w.files {
+		_ = file.configWriter.Rollback()
+	}
+
+	w.restoreSnapshot()
+	return nil
+
+}
+
+// restoreSnapshot restores the routing state of the beginning of the running transaction and ends it
+func (w *MultiFileWriter) restoreSnapshot() {
+	for _, file := range w.files {
+		file.contexts = w.snapshot.contexts[file]
+	}
+	w.secretOwners = w.snapshot.secretOwners
+	w.configOwners = w.snapshot.configOwners
+	w.targetOwners = w.snapshot.targetOwners
+	w.extends = w.snapshot.extends
+	w.snapshot = nil
+}
+
+// apply runs the change in the running transaction or in a new one, so a change touching multiple files is written completely or not at all
+func (w *MultiFileWriter) apply(change func() error) error {
+
+	if w.snapshot != nil {
+		return change()
+	}
+
+	if errBegin := w.Begin(); errBegin != nil {
+		return errBegin
+	}
+
+	if errChange := change(); errChange != nil {
+		_ = w.Rollback()
+		return errChange
+	}
+
+	return w.Commit()
+
+}
+
+func (w *MultiFileWriter) setOnWrite(onWrite func() error) {
+	w.onWrite = onWrite
+}
+
+// definition returns the merged repository definition of all config files
+func (w *MultiFileWriter) definition() (*repositoryDefinition, error) {
+
+	var merged *repositoryDefinition
+
+	for _, file := range w.files {
+
+		source, isSource := file.configWriter.(definitionSource)
+		if !isSource {
+			return nil, fmt.Errorf("can not read the definition of %s", file.configFileUsed)
+		}
+
+		definition, errDefinition := source.definition()
+		if errDefinition != nil {
+			return nil, fmt.Errorf("%s: %s", file.configFileUsed, errDefinition.Error())
+		}
+		definition.files = []*fileDefinition{ownFileDefinition(definition)}
+
+		if merged == nil {
+			merged = definition
+			continue
+		}
+
+		if errMerge := mergeDefinition(merged, definition); errMerge != nil {
+			return nil, errMerge
+		}
+
+	}
+
+	if errExtends := validateDefinitionExtends(merged); errExtends != nil {
+		return nil, errExtends
+	}
+
+	merged.configWriter = w
+	return merged, nil
+
+}
+
+// WriteConfig writes all config files
+func (w *MultiFileWriter) WriteConfig() error {
+	for _, file := range w.files {
+		if errWrite := file.configWriter.WriteConfig(); errWrite != nil {
+			return fmt.Errorf("%s: %s", file.configFileUsed, errWrite.Error())
+		}
+	}
+	return nil
+}
+
+// ensureContext adds the context to the file if it is only defined in another file
+func (w *MultiFileWriter) ensureContext(file *fileDefinition, contextName string) error {
+
+	if file.hasContext(contextName) {
+		return nil
+	}
+
+	contextExists := false
+	for _, otherFile := range w.files {
+		if otherFile.hasContext(contextName) {
+			contextExists = true
+		}
+	}
+
+	if contextExists == false {
+		return fmt.Errorf("the context %s does not exist. Use git-secrets add context <contextName> to add a context", contextName)
+	}
+
+	if errAdd := file.configWriter.AddContext(contextName); errAdd != nil {
+		return fmt.Errorf("%s: %s", file.configFileUsed, errAdd.Error())
+	}
+
+	file.contexts = append(file.contexts, contextName)
+	return nil
+
+}
+
+func (w *MultiFileWriter) file(configFileUsed string) *fileDefinition {
+	for _, file := range w.files {
+		if file.configFileUsed == configFileUsed {
+			return file
+		}
+	}
+	return w.files[0]
+}
+
+func (f *fileDefinition) hasContext(contextName string) bool {
+	for _, fileContext := range f.contexts {
+		if fileContext == contextName {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fileDefinition) removeContext(contextName string) {
+	var remaining []string
+	for _, fileContext := range f.contexts {
+		if fileContext != contextName {
+			remaining = append(remaining, fileContext)
+		}
+	}
+	f.contexts = remaining
+}
+
+func copyOwners(owners map[string]*fileDefinition) map[string]*fileDefinition {
+	copied := make(map[string]*fileDefinition)
+	for key, owner := range owners {
+		copied[key] = owner
+	}
+	return copied
+}
+
+// ownedEntries returns the entries owned by the file, entries without owner belong to the fallback file
+func ownedEntries(entries map[string]string, owners map[string]*fileDefinition, file *fileDefinition, fallback *fileDefinition) map[string]string {
+	owned := make(map[string]string)
+	for entryName, value := range entries {
+		owner := owners[entryName]
+		if owner == nil {
+			owner = fallback
+		}
+		if owner == file {
+			owned[entryName] = value
+		}
+	}
+	return owned
+}
@@ -0,0 +1,298 @@
+package config_generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/jsonedit"
+	"github.com/benammann/git-secrets/pkg/yamledit"
+	"strings"
+)
+
+// MergeConflict is a key of the config file which has been changed on both sides of a merge
+type MergeConflict struct {
+	// Path is the path of the changed key, e.g. context.prod.secrets.databasePassword
+	Path []string
+	// Hint compares the decrypted values of a conflicting secret, it is empty if they could not be decrypted
+	Hint string
+	// Resolved is true if both sides encrypted the same value, our encrypted value is kept
+	// the values of unresolved conflicts are written between conflict markers
+	Resolved bool
+}
+
+func (m *MergeConflict) String() string {
+	if m.Hint == "" {
+		return fmt.Sprintf("%s: changed on both sides", strings.Join(m.Path, "."))
+	}
+	return fmt.Sprintf("%s: changed on both sides, %s", strings.Join(m.Path, "."), m.Hint)
+}
+
+// MergeConfigFiles merges the changes of two versions of a config file (ours, theirs) made on top of their common base
+// contexts, secrets, configs and render targets are merged key by key, the files of render targets and the includes
+// are merged as sets, the merged file keeps the formatting of ours
+// keys changed on both sides are returned as conflicts, the decrypted values of conflicting secrets are compared using
+// the keys of keySource, secrets encrypting the same value on both sides are resolved and keep our value
+// the lines of unresolved conflicts are written between git conflict markers showing our and their value
+// the decryptSecret of the merged versions is never used since they are not part of the working tree
+func MergeConfigFiles(format FileFormat, configPath string, base []byte, ours []byte, theirs []byte, keySource *Repository) ([]byte, []*MergeConflict, error) {
+
+	var documents [3]interface{}
+	var versions [3]int
+	for i, contents := range [][]byte{base, ours, theirs} {
+		// the base is empty if both sides added the config file
+		if i == 0 && len(bytes.TrimSpace(contents)) == 0 {
+			documents[i] = map[string]interface{}{}
+			continue
+		}
+		jsonContents, errNormalize := NormalizeDocument(format, contents)
+		if errNormalize != nil {
+			return nil, nil, errNormalize
+		}
+		if errParse := json.Unmarshal(jsonContents, &documents[i]); errParse != nil {
+			return nil, nil, fmt.Errorf("could not parse json: %s", errParse.Error())
+		}
+		var versionBase VersionFixType
+		_ = json.Unmarshal(jsonContents, &versionBase)
+		versions[i] = versionBase.Version
+	}
+
+	if versions[1] != versions[2] {
+		return nil, nil, fmt.Errorf("can not merge config files of version %d and %d, migrate both to the same version first", versions[1], versions[2])
+	}
+
+	merged, conflictPaths := jsonedit.Merge(documents[0], documents[1], documents[2])
+
+	mergedJson, errMarshal := json.MarshalIndent(merged, "", "  ")
+	if errMarshal != nil {
+		return nil, nil, fmt.Errorf("could not encode config: %s", errMarshal.Error())
+	}
+	if _, errValidate := parseUntrustedVersion(mergedJson, configPath, versions[1], keySource); errValidate != nil {
+		return nil, nil, fmt.Errorf("the merged config is not valid: %s", errValidate.Error())
+	}
+
+	var conflicts []*MergeConflict
+	if len(conflictPaths) > 0 {
+		// the repositories are only used to decrypt the conflicting secrets, so errors just omit the hints
+		oursRepository, _ := parseUntrustedVersion(mustMarshal(documents[1]), configPath, versions[1], keySource)
+		theirsRepository, _ := parseUntrustedVersion(mustMarshal(documents[2]), configPath, versions[2], keySource)
+		for _, conflictPath := range conflictPaths {
+			conflict := &MergeConflict{Path: conflictPath}
+			if oursRepository != nil && theirsRepository != nil {
+				conflict.Hint, conflict.Resolved = compareConflictingSecret(conflictPath, oursRepository, theirsRepository)
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	oursMerged, errEncode := encodeMerged(format, ours, documents[1], merged)
+	if errEncode != nil {
+		return nil, nil, errEncode
+	}
+
+	var unresolvedPaths [][]string
+	for _, conflict := range conflicts {
+		if !conflict.Resolved {
+			unresolvedPaths = append(unresolvedPaths, conflict.Path)
+		}
+	}
+	if len(unresolvedPaths) == 0 {
+		return oursMerged, conflicts, nil
+	}
+
+	// the merged file is encoded a second time with their values, the lines which differ are the conflicts
+	theirsMerged, errEncode := encodeMerged(format, ours, documents[1], replaceConflicts(merged, documents[2], unresolvedPaths))
+	if errEncode != nil {
+		return nil, nil, errEncode
+	}
+
+	return markConflicts(oursMerged, theirsMerged), conflicts, nil
+
+}
+
+// encodeMerged encodes the merged document, json and yaml files are edited in place so the formatting of ours is kept
+func encodeMerged(format FileFormat, ours []byte, oursDocument interface{}, merged interface{}) ([]byte, error) {
+	switch format {
+	case FileFormatJson:
+		document, errDocument := jsonedit.NewDocument(ours)
+		if errDocument != nil {
+			return nil, errDocument
+		}
+		if errApply := document.ApplyChanges(oursDocument, merged); errApply != nil {
+			return nil, errApply
+		}
+		return document.Bytes(), nil
+	case FileFormatYaml:
+		document, errDocument := yamledit.NewDocument(ours)
+		if errDocument != nil {
+			return nil, errDocument
+		}
+		if errApply := document.ApplyChanges(oursDocument, merged); errApply != nil {
+			return nil, errApply
+		}
+		return document.Bytes()
+	default:
+		encoded, errEncode := EncodeDocument(format, mustMarshal(merged))
+		if errEncode != nil {
+			return nil, fmt.Errorf("could not encode config: %s", errEncode.Error())
+		}
+		return encoded, nil
+	}
+}
+
+// replaceConflicts returns a copy of the merged document using their value at the conflicting paths
+func replaceConflicts(merged interface{}, theirs interface{}, paths [][]string) interface{} {
+
+	var replaced interface{}
+	_ = copyDocument(merged, &replaced)
+
+	for _, path := range paths {
+		if len(path) == 0 {
+			return theirs
+		}
+		parent, isObject := documentValue(replaced, path[:len(path)-1]).(map[string]interface{})
+		if !isObject {
+			continue
+		}
+		key := path[len(path)-1]
+		if theirsParent, theirsIsObject := documentValue(theirs, path[:len(path)-1]).(map[string]interface{}); theirsIsObject {
+			if value, exists := theirsParent[key]; exists {
+				parent[key] = value
+				continue
+			}
+		}
+		delete(parent, key)
+	}
+
+	return replaced
+
+}
+
+// documentValue returns the value at the path of the decoded json document, nil if it does not exist
+func documentValue(document interface{}, path []string) interface{} {
+	current := document
+	for _, key := range path {
+		object, isObject := current.(map[string]interface{})
+		if !isObject {
+			return nil
+		}
+		current = object[key]
+	}
+	return current
+}
+
+// markConflicts writes the lines which differ between ours and theirs between git conflict markers
+func markConflicts(ours []byte, theirs []byte) []byte {
+
+	oursLines := splitLines(ours)
+	theirsLines := splitLines(theirs)
+
+	// common lines are found by the longest common subsequence of the lines
+	common := make([][]int, len(oursLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(theirsLines)+1)
+	}
+	for i := len(oursLines) - 1; i >= 0; i-- {
+		for j := len(theirsLines) - 1; j >= 0; j-- {
+			if oursLines[i] == theirsLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var out, oursHunk, theirsHunk bytes.Buffer
+	flush := func() {
+		if oursHunk.Len() == 0 && theirsHunk.Len() == 0 {
+			return
+		}
+		out.WriteString("<<<<<<< ours\n")
+		out.Write(oursHunk.Bytes())
+		out.WriteString("=======\n")
+		out.Write(theirsHunk.Bytes())
+		out.WriteString(">>>>>>> theirs\n")
+		oursHunk.Reset()
+		theirsHunk.Reset()
+	}
+
+	i, j := 0, 0
+	for i < len(oursLines) || j < len(theirsLines) {
+		switch {
+		case i < len(oursLines) && j < len(theirsLines) && oursLines[i] == theirsLines[j]:
+			flush()
+			out.WriteString(oursLines[i])
+			i++
+			j++
+		case j == len(theirsLines) || (i < len(oursLines) && common[i+1][j] >= common[i][j+1]):
+			oursHunk.WriteString(oursLines[i])
+			i++
+		default:
+			theirsHunk.WriteString(theirsLines[j])
+			j++
+		}
+	}
+	flush()
+
+	return out.Bytes()
+
+}
+
+// splitLines splits the contents into lines which all end with a line break
+func splitLines(contents []byte) []string {
+	lines := strings.SplitAfter(string(contents), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+// compareConflictingSecret decrypts both values of a conflicting secret (context.<context>.secrets.<secret>)
+// it returns the hint for the conflict and whether both values are equal
+func compareConflictingSecret(path []string, ours *Repository, theirs *Repository) (string, bool) {
+
+	if len(path) < 4 || path[0] != "context" || path[2] != "secrets" {
+		return "", false
+	}
+
+	oursValue, oursFound := decryptedSecret(ours, path[1], path[3])
+	theirsValue, theirsFound := decryptedSecret(theirs, path[1], path[3])
+	if !oursFound || !theirsFound {
+		return "", false
+	}
+
+	if oursValue == theirsValue {
+		return "the decrypted values are equal, keeping ours", true
+	}
+	return "the decrypted values differ", false
+
+}
+
+// decryptedSecret returns the decrypted value of the secret defined by the context
+func decryptedSecret(repository *Repository, contextName string, secretName string) (string, bool) {
+	for _, secret := range repository.GetSecretsByContext(contextName) {
+		if secret.Name != secretName {
+			continue
+		}
+		decoded, errDecode := secret.Decode()
+		return decoded, errDecode == nil
+	}
+	return "", false
+}
+
+// parseUntrustedVersion parses a merged version of the config file, the keys are resolved by keySource
+func parseUntrustedVersion(jsonContents []byte, configPath string, version int, keySource *Repository) (*Repository, error) {
+	definition, errDefinition := parseVersionDefinition(jsonContents, configPath, version)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+	return buildUntrustedRepository(definition, keySource)
+}
+
+func mustMarshal(value interface{}) []byte {
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
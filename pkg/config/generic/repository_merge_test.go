@@ -0,0 +1,209 @@
+package config_generic
+
+import (
+	"encoding/json"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// changedMergeDocument returns the real world test file changed by the function
+func changedMergeDocument(t *testing.T, change func(document map[string]interface{})) []byte {
+	var document map[string]interface{}
+	assert.NoError(t, json.Unmarshal(readTestFile(t, TestFileRealWorld), &document))
+	change(document)
+	encoded, errMarshal := json.MarshalIndent(document, "", "  ")
+	assert.NoError(t, errMarshal)
+	return encoded
+}
+
+func mergeContext(document map[string]interface{}, contextName string) map[string]interface{} {
+	return document["context"].(map[string]interface{})[contextName].(map[string]interface{})
+}
+
+func mergeEntries(document map[string]interface{}, contextName string, kind string) map[string]interface{} {
+	return mergeContext(document, contextName)[kind].(map[string]interface{})
+}
+
+// mergeKeySource returns the working tree config resolving the keys of the merged versions
+func mergeKeySource(t *testing.T, withSecret bool) *Repository {
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	if withSecret {
+		_ = globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, false)
+	}
+	keySource, errParse := ParseDocument(".git-secrets.json", readTestFile(t, TestFileRealWorld), globalConfig, map[string]string{})
+	assert.NoError(t, errParse)
+	return keySource
+}
+
+func TestMergeConfigFiles(t *testing.T) {
+
+	base := readTestFile(t, TestFileRealWorld)
+	repo := initRepository(t, TestFileRealWorld, "prod")
+
+	encode := func(plainValue string) string {
+		encoded, errEncode := repo.GetContext("prod").EncodeValue(plainValue)
+		assert.NoError(t, errEncode)
+		return encoded
+	}
+
+	parseMerged := func(t *testing.T, merged []byte) map[string]interface{} {
+		var document map[string]interface{}
+		assert.NoError(t, json.Unmarshal(merged, &document))
+		return document
+	}
+
+	t.Run("it should merge changes of different keys", func(t *testing.T) {
+		ours := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "default", "configs")["apiUrl"] = "https://api.local"
+		})
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "configs")["databaseHost"] = "database-prod-2.svc.cluster"
+			document["context"].(map[string]interface{})["dev"] = map[string]interface{}{}
+		})
+		merged, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 0)
+		document := parseMerged(t, merged)
+		assert.Equal(t, "https://api.local", mergeEntries(document, "default", "configs")["apiUrl"])
+		assert.Equal(t, "database-prod-2.svc.cluster", mergeEntries(document, "prod", "configs")["databaseHost"])
+		assert.NotNil(t, document["context"].(map[string]interface{})["dev"])
+	})
+
+	t.Run("it should report a config changed on both sides", func(t *testing.T) {
+		ours := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "configs")["databasePort"] = "3308"
+		})
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "configs")["databasePort"] = "3309"
+		})
+		merged, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, []string{"context", "prod", "configs", "databasePort"}, conflicts[0].Path)
+		assert.False(t, conflicts[0].Resolved)
+		assert.Equal(t, "context.prod.configs.databasePort: changed on both sides", conflicts[0].String())
+		assert.Contains(t, string(merged), "<<<<<<< ours\n        \"databasePort\": \"3308\"\n=======\n        \"databasePort\": \"3309\"\n>>>>>>> theirs\n")
+		assert.Equal(t, 1, strings.Count(string(merged), "<<<<<<<"))
+	})
+
+	t.Run("it should merge the files of a render target added on both sides", func(t *testing.T) {
+		addFile := func(fileIn string, fileOut string) func(document map[string]interface{}) {
+			return func(document map[string]interface{}) {
+				target := document["renderFiles"].(map[string]interface{})["env"].(map[string]interface{})
+				target["files"] = append(target["files"].([]interface{}), map[string]interface{}{"fileIn": fileIn, "fileOut": fileOut})
+			}
+		}
+		ours := changedMergeDocument(t, addFile("templates/.env.test.dist", "templates/.env.test"))
+		theirs := changedMergeDocument(t, addFile("templates/.env.ci.dist", "templates/.env.ci"))
+		merged, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 0)
+		files := parseMerged(t, merged)["renderFiles"].(map[string]interface{})["env"].(map[string]interface{})["files"].([]interface{})
+		assert.Len(t, files, 3)
+		assert.Equal(t, "templates/.env.test", files[1].(map[string]interface{})["fileOut"])
+		assert.Equal(t, "templates/.env.ci", files[2].(map[string]interface{})["fileOut"])
+	})
+
+	t.Run("it should resolve a secret encrypting the same value on both sides", func(t *testing.T) {
+		ours := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "secrets")["databasePassword"] = encode("newPassword")
+		})
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "secrets")["databasePassword"] = encode("newPassword")
+		})
+		merged, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 1)
+		assert.True(t, conflicts[0].Resolved)
+		assert.Equal(t, "context.prod.secrets.databasePassword: changed on both sides, the decrypted values are equal, keeping ours", conflicts[0].String())
+		assert.Equal(t, mergeEntries(parseMerged(t, ours), "prod", "secrets")["databasePassword"], mergeEntries(parseMerged(t, merged), "prod", "secrets")["databasePassword"])
+	})
+
+	t.Run("it should hint that the decrypted values differ", func(t *testing.T) {
+		ours := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "secrets")["databasePassword"] = encode("oursPassword")
+		})
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "secrets")["databasePassword"] = encode("theirsPassword")
+		})
+		_, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 1)
+		assert.False(t, conflicts[0].Resolved)
+		assert.Equal(t, "the decrypted values differ", conflicts[0].Hint)
+
+		_, conflicts, errMerge = MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, false))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, "", conflicts[0].Hint)
+	})
+
+	t.Run("it should never use the decryptSecret of the merged versions", func(t *testing.T) {
+		markerFile := filepath.Join(t.TempDir(), "marker")
+		ours := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "prod", "secrets")["databasePassword"] = encode("oursPassword")
+		})
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeContext(document, "default")["decryptSecret"] = map[string]interface{}{"fromCommand": "touch " + markerFile}
+			mergeEntries(document, "prod", "secrets")["databasePassword"] = encode("theirsPassword")
+		})
+		_, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, "the decrypted values differ", conflicts[0].Hint)
+		assert.NoFileExists(t, markerFile)
+	})
+
+	t.Run("it should keep the formatting of ours", func(t *testing.T) {
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "default", "configs")["databasePort"] = "3310"
+		})
+		merged, _, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, base, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Equal(t, string(base[:50]), string(merged[:50]))
+		assert.Contains(t, string(merged), `"databasePort": "3310"`)
+	})
+
+	t.Run("it should merge yaml files", func(t *testing.T) {
+		yamlBase := readTestFile(t, TestFileRealWorldYaml)
+		ours := append(yamlBase, []byte("exec:\n  prefix: APP_\n")...)
+		merged, conflicts, errMerge := MergeConfigFiles(FileFormatYaml, ".git-secrets.yaml", yamlBase, ours, yamlBase, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 0)
+		assert.Contains(t, string(merged), "prefix: APP_")
+	})
+
+	t.Run("it should merge files added on both sides", func(t *testing.T) {
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeEntries(document, "default", "configs")["apiUrl"] = "https://api.local"
+		})
+		merged, conflicts, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", []byte{}, base, theirs, mergeKeySource(t, true))
+		assert.NoError(t, errMerge)
+		assert.Len(t, conflicts, 0)
+		assert.Equal(t, "https://api.local", mergeEntries(parseMerged(t, merged), "default", "configs")["apiUrl"])
+	})
+
+	t.Run("it should fail on different versions", func(t *testing.T) {
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			document["version"] = 2
+		})
+		_, _, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, base, theirs, mergeKeySource(t, true))
+		assert.EqualError(t, errMerge, "can not merge config files of version 1 and 2, migrate both to the same version first")
+	})
+
+	t.Run("it should fail if the merged config is not valid", func(t *testing.T) {
+		ours := changedMergeDocument(t, func(document map[string]interface{}) {
+			mergeContext(document, "staging")["extends"] = "prod"
+		})
+		theirs := changedMergeDocument(t, func(document map[string]interface{}) {
+			delete(document["context"].(map[string]interface{}), "prod")
+		})
+		_, _, errMerge := MergeConfigFiles(FileFormatJson, ".git-secrets.json", base, ours, theirs, mergeKeySource(t, true))
+		assert.Error(t, errMerge)
+		assert.Contains(t, errMerge.Error(), "the merged config is not valid")
+	})
+
+}
@@ -0,0 +1,258 @@
+package config_generic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/generator"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type EntryType string
+
+const (
+	EntryTypeString EntryType = "string"
+	EntryTypeNumber EntryType = "number"
+	EntryTypeBool   EntryType = "bool"
+	EntryTypeUrl    EntryType = "url"
+	EntryTypePem    EntryType = "pem"
+	EntryTypeJson   EntryType = "json"
+	EntryTypeJwt    EntryType = "jwt"
+)
+
+// ExpiresAtLayout is the date layout of the expiresAt and rotatedAt metadata, a full RFC3339 timestamp is accepted as well
+const ExpiresAtLayout = "2006-01-02"
+
+// metadataNow returns the current time, it is replaced in tests
+var metadataNow = time.Now
+
+// EntryTypes returns all available entry types
+func EntryTypes() []EntryType {
+	return []EntryType{EntryTypeString, EntryTypeNumber, EntryTypeBool, EntryTypeUrl, EntryTypePem, EntryTypeJson, EntryTypeJwt}
+}
+
+// Metadata annotates a secret or config entry, only available since schema v2
+type Metadata struct {
+
+	// Description describes what the entry is used for
+	Description string
+
+	// Owner references the person or team responsible for the entry
+	Owner string
+
+	// ExpiresAt holds the date when the entry expires (2006-01-02 or RFC3339)
+	ExpiresAt string
+
+	// Type describes the type of the plain value, for example number or url
+	Type EntryType
+
+	// RotateAfter holds the interval the value needs to be rotated in, days or weeks: 90d or 12w
+	RotateAfter string
+
+	// RotatedAt holds the date when the value has been set the last time (2006-01-02 or RFC3339)
+	RotatedAt string
+
+	// Pattern is a regular expression the plain value must match
+	Pattern string
+
+	// MinLength is the minimum number of characters of the plain value
+	MinLength int
+
+	// Charset restricts the characters of the plain value: alnum, hex, base64 or words
+	Charset string
+}
+
+// IsEmpty returns true if no metadata is set
+func (m Metadata) IsEmpty() bool {
+	return m == Metadata{}
+}
+
+// ExpiresAtTime parses the expiry date, returns nil if no expiry date is set
+func (m Metadata) ExpiresAtTime() (*time.Time, error) {
+	if m.ExpiresAt == "" {
+		return nil, nil
+	}
+	return parseMetadataTime(m.ExpiresAt)
+}
+
+// RotatedAtTime parses the date of the last rotation, returns nil if it is unknown
+func (m Metadata) RotatedAtTime() (*time.Time, error) {
+	if m.RotatedAt == "" {
+		return nil, nil
+	}
+	return parseMetadataTime(m.RotatedAt)
+}
+
+// RotateAfterDuration parses the rotation interval, returns 0 if the entry does not need to be rotated
+func (m Metadata) RotateAfterDuration() (time.Duration, error) {
+	if m.RotateAfter == "" {
+		return 0, nil
+	}
+	return parseRotateAfter(m.RotateAfter)
+}
+
+// RotationDueAt returns the date the entry needs to be rotated, nil if it does not need to be rotated or the last rotation is unknown
+func (m Metadata) RotationDueAt() (*time.Time, error) {
+	rotateAfter, errRotateAfter := m.RotateAfterDuration()
+	if errRotateAfter != nil || rotateAfter == 0 {
+		return nil, errRotateAfter
+	}
+	rotatedAt, errRotatedAt := m.RotatedAtTime()
+	if errRotatedAt != nil || rotatedAt == nil {
+		return nil, errRotatedAt
+	}
+	dueAt := rotatedAt.Add(rotateAfter)
+	return &dueAt, nil
+}
+
+// Validate checks the type, the expiry date and the rotation
+func (m Metadata) Validate() error {
+	if m.Type != "" && !IsEntryType(string(m.Type)) {
+		return fmt.Errorf("unknown type %s", m.Type)
+	}
+	if _, errExpires := m.ExpiresAtTime(); errExpires != nil {
+		return fmt.Errorf("invalid expiresAt: %s", errExpires.Error())
+	}
+	if _, errRotateAfter := m.RotateAfterDuration(); errRotateAfter != nil {
+		return fmt.Errorf("invalid rotateAfter: %s", errRotateAfter.Error())
+	}
+	if _, errRotatedAt := m.RotatedAtTime(); errRotatedAt != nil {
+		return fmt.Errorf("invalid rotatedAt: %s", errRotatedAt.Error())
+	}
+	if _, errPattern := regexp.Compile(m.Pattern); errPattern != nil {
+		return fmt.Errorf("invalid pattern: %s", errPattern.Error())
+	}
+	if m.MinLength < 0 {
+		return fmt.Errorf("invalid minLength: %d must not be negative", m.MinLength)
+	}
+	if m.Charset != "" && !generator.IsCharset(m.Charset) {
+		return fmt.Errorf("unknown charset %s", m.Charset)
+	}
+	return nil
+}
+
+// ValidateValue checks if the plain value matches the type, the minimum length, the charset and the pattern
+func (m Metadata) ValidateValue(plainValue string) error {
+	if errType := ValidateEntryValue(m.Type, plainValue); errType != nil {
+		return errType
+	}
+	if length := utf8.RuneCountInString(plainValue); length < m.MinLength {
+		return fmt.Errorf("the value has %d characters, at least %d are required", length, m.MinLength)
+	}
+	if m.Charset != "" {
+		if errCharset := generator.Matches(generator.Charset(m.Charset), plainValue); errCharset != nil {
+			return errCharset
+		}
+	}
+	if m.Pattern != "" {
+		pattern, errPattern := regexp.Compile(m.Pattern)
+		if errPattern != nil {
+			return fmt.Errorf("invalid pattern: %s", errPattern.Error())
+		}
+		if !pattern.MatchString(plainValue) {
+			return fmt.Errorf("the value does not match the pattern %s", m.Pattern)
+		}
+	}
+	return nil
+}
+
+// IsEntryType returns true if the type is known
+func IsEntryType(entryType string) bool {
+	for _, availableType := range EntryTypes() {
+		if string(availableType) == entryType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEntryValue checks if the plain value matches the type, an empty type accepts every value
+func ValidateEntryValue(entryType EntryType, plainValue string) error {
+	switch entryType {
+	case "", EntryTypeString:
+		return nil
+	case EntryTypeNumber:
+		if _, errParse := strconv.ParseFloat(plainValue, 64); errParse != nil {
+			return fmt.Errorf("%s is not a number", plainValue)
+		}
+	case EntryTypeBool:
+		if _, errParse := strconv.ParseBool(plainValue); errParse != nil {
+			return fmt.Errorf("%s is not a bool", plainValue)
+		}
+	case EntryTypeUrl:
+		parsedUrl, errParse := url.Parse(plainValue)
+		if errParse != nil || parsedUrl.Scheme == "" || parsedUrl.Host == "" {
+			return fmt.Errorf("the value is not an absolute url")
+		}
+	case EntryTypePem:
+		if block, _ := pem.Decode([]byte(plainValue)); block == nil {
+			return fmt.Errorf("the value is not pem encoded")
+		}
+	case EntryTypeJson:
+		if !json.Valid([]byte(plainValue)) {
+			return fmt.Errorf("the value is not valid json")
+		}
+	case EntryTypeJwt:
+		if !isJwt(plainValue) {
+			return fmt.Errorf("the value is not a jwt")
+		}
+	default:
+		return fmt.Errorf("unknown type %s", entryType)
+	}
+	return nil
+}
+
+// isJwt returns true if the value consists of a json header, a json payload and a signature encoded as base64url
+func isJwt(value string) bool {
+	segments := strings.Split(value, ".")
+	if len(segments) != 3 {
+		return false
+	}
+	for _, segment := range segments[:2] {
+		decoded, errDecode := base64.RawURLEncoding.DecodeString(segment)
+		if errDecode != nil || !json.Valid(decoded) {
+			return false
+		}
+	}
+	_, errSignature := base64.RawURLEncoding.DecodeString(segments[2])
+	return errSignature == nil
+}
+
+// parseRotateAfter parses an interval of days or weeks: 90d or 12w
+func parseRotateAfter(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("%s must be a number of days or weeks, e.g. 90d or 12w", value)
+	if len(value) < 2 {
+		return 0, invalid
+	}
+	count, errCount := strconv.Atoi(value[:len(value)-1])
+	if errCount != nil || count <= 0 {
+		return 0, invalid
+	}
+	day := 24 * time.Hour
+	switch value[len(value)-1] {
+	case 'd':
+		return time.Duration(count) * day, nil
+	case 'w':
+		return time.Duration(count) * 7 * day, nil
+	default:
+		return 0, invalid
+	}
+}
+
+// parseMetadataTime parses a date (2006-01-02) or a RFC3339 timestamp
+func parseMetadataTime(value string) (*time.Time, error) {
+	if parsed, errDate := time.Parse(ExpiresAtLayout, value); errDate == nil {
+		return &parsed, nil
+	}
+	parsed, errParse := time.Parse(time.RFC3339, value)
+	if errParse != nil {
+		return nil, fmt.Errorf("%s must be a date (%s) or a RFC3339 timestamp", value, ExpiresAtLayout)
+	}
+	return &parsed, nil
+}
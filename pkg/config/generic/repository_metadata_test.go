@@ -0,0 +1,105 @@
+package config_generic
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateEntryValue(t *testing.T) {
+	tests := []struct {
+		entryType EntryType
+		value     string
+		valid     bool
+	}{
+		{"", "anything", true},
+		{EntryTypeString, "anything", true},
+		{EntryTypeNumber, "3306", true},
+		{EntryTypeNumber, "1.5", true},
+		{EntryTypeNumber, "abc", false},
+		{EntryTypeBool, "true", true},
+		{EntryTypeBool, "yes", false},
+		{EntryTypeUrl, "https://example.com/path", true},
+		{EntryTypeUrl, "example.com", false},
+		{EntryTypePem, "-----BEGIN TEST-----\naGVsbG8=\n-----END TEST-----\n", true},
+		{EntryTypePem, "not pem", false},
+		{EntryTypeJson, `{"key": "value"}`, true},
+		{EntryTypeJson, `{"key":`, false},
+		{EntryTypeJwt, "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjM0In0.dGVzdA", true},
+		{EntryTypeJwt, "eyJhbGciOiJIUzI1NiJ9.not-json.dGVzdA", false},
+		{EntryTypeJwt, "a.b", false},
+		{"unknown", "value", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.entryType)+"/"+tt.value, func(t *testing.T) {
+			err := ValidateEntryValue(tt.entryType, tt.value)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMetadata_Validate(t *testing.T) {
+	assert.NoError(t, Metadata{}.Validate())
+	assert.NoError(t, Metadata{ExpiresAt: "2030-01-01", Type: EntryTypeUrl}.Validate())
+	assert.NoError(t, Metadata{ExpiresAt: "2030-01-01T10:00:00Z"}.Validate())
+	assert.Error(t, Metadata{ExpiresAt: "01.01.2030"}.Validate())
+	assert.Error(t, Metadata{Type: "unknown"}.Validate())
+	assert.NoError(t, Metadata{RotateAfter: "90d", RotatedAt: "2030-01-01"}.Validate())
+	assert.Error(t, Metadata{RotateAfter: "3 months"}.Validate())
+	assert.Error(t, Metadata{RotatedAt: "yesterday"}.Validate())
+	assert.NoError(t, Metadata{Pattern: "^sk_[a-z]+$", MinLength: 8, Charset: "hex"}.Validate())
+	assert.Error(t, Metadata{Pattern: "^(unclosed"}.Validate())
+	assert.Error(t, Metadata{MinLength: -1}.Validate())
+	assert.Error(t, Metadata{Charset: "emoji"}.Validate())
+}
+
+func TestMetadata_ValidateValue(t *testing.T) {
+	metadata := Metadata{Pattern: "^sk_", MinLength: 8}
+	assert.NoError(t, metadata.ValidateValue("sk_abcdef"))
+	assert.EqualError(t, metadata.ValidateValue("sk_abc"), "the value has 6 characters, at least 8 are required")
+	assert.EqualError(t, metadata.ValidateValue("pk_abcdef"), "the value does not match the pattern ^sk_")
+	assert.EqualError(t, Metadata{Charset: "hex"}.ValidateValue("xyz"), "the value contains characters outside of the charset hex")
+	assert.EqualError(t, Metadata{Type: EntryTypeNumber, MinLength: 1}.ValidateValue("abc"), "abc is not a number")
+}
+
+func TestMetadata_RotationDueAt(t *testing.T) {
+	dueAt, errDue := Metadata{RotatedAt: "2030-01-01"}.RotationDueAt()
+	assert.NoError(t, errDue)
+	assert.Nil(t, dueAt)
+
+	dueAt, errDue = Metadata{RotateAfter: "90d"}.RotationDueAt()
+	assert.NoError(t, errDue)
+	assert.Nil(t, dueAt)
+
+	dueAt, errDue = Metadata{RotateAfter: "90d", RotatedAt: "2030-01-01"}.RotationDueAt()
+	assert.NoError(t, errDue)
+	assert.Equal(t, "2030-04-01", dueAt.Format(ExpiresAtLayout))
+
+	dueAt, errDue = Metadata{RotateAfter: "2w", RotatedAt: "2030-01-01"}.RotationDueAt()
+	assert.NoError(t, errDue)
+	assert.Equal(t, "2030-01-15", dueAt.Format(ExpiresAtLayout))
+
+	for _, invalid := range []string{"d", "0d", "-1w", "12m", "90"} {
+		_, errDue = Metadata{RotateAfter: invalid, RotatedAt: "2030-01-01"}.RotationDueAt()
+		assert.Error(t, errDue, invalid)
+	}
+}
+
+func TestMetadata_ExpiresAtTime(t *testing.T) {
+	expiresAt, errParse := Metadata{}.ExpiresAtTime()
+	assert.NoError(t, errParse)
+	assert.Nil(t, expiresAt)
+
+	expiresAt, errParse = Metadata{ExpiresAt: "2030-01-02"}.ExpiresAtTime()
+	assert.NoError(t, errParse)
+	assert.Equal(t, 2030, expiresAt.Year())
+	assert.Equal(t, 2, expiresAt.Day())
+}
+
+func TestMetadata_IsEmpty(t *testing.T) {
+	assert.True(t, Metadata{}.IsEmpty())
+	assert.False(t, Metadata{Owner: "team"}.IsEmpty())
+}
@@ -14,28 +14,128 @@ type VersionFixType struct {
 
 func ParseRepository(fileSystem afero.Fs, fileName string, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string) (*Repository, error) {
 
-	pathToFile, _ := filepath.Abs(fileName)
+	definition, errDefinition := parseDefinitionFile(fileSystem, fileName, nil)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
 
-	fileContents, fileErr := afero.ReadFile(fileSystem, fileName)
-	if fileErr != nil {
-		return nil, fmt.Errorf("could not load test file %s: %s", fileName, fileErr.Error())
+	if len(definition.includedFiles) > 0 {
+		definition.configWriter = newMultiFileWriter(definition.files, definition)
+	}
+
+	return buildRepository(definition, globalConfig, overwrittenSecrets)
+
+}
+
+// ParseDocument parses the contents of a single config file without resolving its includes
+func ParseDocument(fileName string, contents []byte, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string) (*Repository, error) {
+
+	definition, errDefinition := parseDocumentDefinition(fileName, contents)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	return buildRepository(definition, globalConfig, overwrittenSecrets)
+
+}
+
+// ParseUntrustedDocument parses a single config file which is not part of the working tree, e.g. a historic version or a file passed by git
+// its decryptSecret is never used since it could run any command, the keys are resolved by the contexts of the same name of keySource
+func ParseUntrustedDocument(fileName string, contents []byte, keySource *Repository) (*Repository, error) {
+
+	definition, errDefinition := parseDocumentDefinition(fileName, contents)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	return buildUntrustedRepository(definition, keySource)
+
+}
+
+// ParseUntrustedRepository parses the config file and its includes like ParseRepository, the keys are resolved like ParseUntrustedDocument
+func ParseUntrustedRepository(fileSystem afero.Fs, fileName string, keySource *Repository) (*Repository, error) {
+
+	definition, errDefinition := parseDefinitionFile(fileSystem, fileName, nil)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	return buildUntrustedRepository(definition, keySource)
+
+}
+
+// buildUntrustedRepository builds the repository using the secret resolvers of keySource
+func buildUntrustedRepository(definition *repositoryDefinition, keySource *Repository) (*Repository, error) {
+	definition.untrusted = true
+	definition.keySource = keySource
+	return buildRepository(definition, nil, nil)
+}
+
+// parseDocumentDefinition parses the contents of a single config file into a definition without resolving its includes
+func parseDocumentDefinition(fileName string, contents []byte) (*repositoryDefinition, error) {
+
+	// yaml and toml files are validated and parsed as json
+	jsonContents, errNormalize := NormalizeDocument(DetectFileFormat(fileName), contents)
+	if errNormalize != nil {
+		return nil, errNormalize
 	}
 
 	var VersionBase VersionFixType
-	errParse := json.Unmarshal(fileContents, &VersionBase)
-	if errParse != nil {
+	if errParse := json.Unmarshal(jsonContents, &VersionBase); errParse != nil {
 		return nil, fmt.Errorf("could not parse json: %s", errParse.Error())
 	}
 
-	version := VersionBase.Version
+	pathToFile, _ := filepath.Abs(fileName)
+	return parseVersionDefinition(jsonContents, pathToFile, VersionBase.Version)
+
+}
+
+// parseVersionDefinition parses the json contents of a single config file using the schema of its version
+func parseVersionDefinition(jsonContents []byte, configPath string, version int) (*repositoryDefinition, error) {
 	if IsSchemaV1(version) {
-		configOut, errConfigOut := ParseSchemaV1(fileContents, pathToFile, globalConfig, overwrittenSecrets)
-		if errConfigOut != nil {
-			return nil, fmt.Errorf("could not parse as v1: %s", errConfigOut.Error())
+		definition, errDefinition := parseDefinitionV1(jsonContents, configPath)
+		if errDefinition != nil {
+			return nil, fmt.Errorf("could not parse as v1: %s", errDefinition.Error())
+		}
+		return definition, nil
+	} else if IsSchemaV2(version) {
+		definition, errDefinition := parseDefinitionV2(jsonContents, configPath)
+		if errDefinition != nil {
+			return nil, fmt.Errorf("could not parse as v2: %s", errDefinition.Error())
 		}
-		return configOut, nil
-	} else {
-		return nil, fmt.Errorf("unsupported version: %d", version)
+		return definition, nil
 	}
+	return nil, fmt.Errorf("unsupported version: %d", version)
+}
+
+// parseDefinitionFile parses the config file and merges all included config files into its definition
+// includedBy holds the abs paths of the including files to detect cyclic includes
+func parseDefinitionFile(fileSystem afero.Fs, fileName string, includedBy []string) (*repositoryDefinition, error) {
+
+	pathToFile, _ := filepath.Abs(fileName)
+
+	for _, includingFile := range includedBy {
+		if includingFile == pathToFile {
+			return nil, fmt.Errorf("cyclic include of %s", pathToFile)
+		}
+	}
+
+	fileContents, fileErr := afero.ReadFile(fileSystem, fileName)
+	if fileErr != nil {
+		return nil, fmt.Errorf("could not load test file %s: %s", fileName, fileErr.Error())
+	}
+
+	definition, errDefinition := parseDocumentDefinition(fileName, fileContents)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	definition.files = []*fileDefinition{ownFileDefinition(definition)}
+
+	if errInclude := includeDefinitions(fileSystem, fileName, definition, append(includedBy, pathToFile)); errInclude != nil {
+		return nil, fmt.Errorf("%s: %s", fileName, errInclude.Error())
+	}
+
+	return definition, nil
 
 }
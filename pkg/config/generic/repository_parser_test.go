@@ -36,4 +36,22 @@ func TestParseRepository(t *testing.T) {
 		assert.NoError(t, errParse)
 	})
 
+	t.Run("parse yaml and toml configs like json configs", func(t *testing.T) {
+		for _, fileName := range []string{TestFileRealWorldYaml, TestFileRealWorldToml} {
+			repository, errParse := createTestRepository(fileName, "prod")
+			assert.NoError(t, errParse)
+			assert.Equal(t, "3307", repository.GetCurrentConfig("databasePort").Value)
+			decoded, errDecode := repository.GetCurrentSecret("databasePassword").Decode()
+			assert.NoError(t, errDecode)
+			assert.NotEqual(t, "", decoded)
+			assert.Len(t, repository.RenderTargetNames(), 1)
+		}
+	})
+
+	t.Run("v2: parse valid config", func(t *testing.T) {
+		repository, errParse := createTestRepository(TestFileV2, "default")
+		assert.NoError(t, errParse)
+		assert.Equal(t, 2, repository.GetConfigVersion())
+	})
+
 }
@@ -5,11 +5,45 @@ import "fmt"
 type RenderTarget struct {
 	Name          string
 	FilesToRender []*FileToRender
+
+	// Partials holds glob patterns of templates which are parsed into every file of this target
+	Partials []string
 }
 
 type FileToRender struct {
 	FileIn  string
 	FileOut string
+
+	// Partials holds glob patterns of templates which are only parsed into this file, e.g. passed to git secrets render
+	Partials   []string            `json:",omitempty"`
+	Kubernetes *KubernetesManifest `json:",omitempty"`
+
+	// target is the render target the file has been added to, nil if the file is rendered on its own
+	target *RenderTarget
+}
+
+const KubernetesKindSecret = "Secret"
+const KubernetesKindConfigMap = "ConfigMap"
+
+// KubernetesManifest describes a natively rendered v1/Secret and v1/ConfigMap
+// name, namespace, labels and annotations are rendered as templates
+type KubernetesManifest struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// Kinds describes which manifests are rendered: Secret and / or ConfigMap, both if empty
+	Kinds []string
+
+	// StringData puts the plain secrets into stringData instead of base64 encoding them into data
+	StringData bool
+
+	// SecretKeys filters the secrets of the v1/Secret, all secrets are used if empty
+	SecretKeys []string
+
+	// ConfigKeys filters the configs of the v1/ConfigMap, all configs are used if empty
+	ConfigKeys []string
 }
 
 func NewRenderTarget(name string) *RenderTarget {
@@ -31,11 +65,60 @@ func (c *RenderTarget) AddFileToRender(fileIn string, fileOut string) error {
 	c.FilesToRender = append(c.FilesToRender, &FileToRender{
 		FileIn:  fileIn,
 		FileOut: fileOut,
+		target:  c,
+	})
+
+	return nil
+}
+
+// AddKubernetesManifest adds a natively rendered kubernetes manifest which is written to fileOut
+func (c *RenderTarget) AddKubernetesManifest(fileOut string, manifest *KubernetesManifest) error {
+
+	if manifest.Name == "" {
+		return fmt.Errorf("the kubernetes manifest of target %s requires a name", c.Name)
+	}
+
+	for _, kind := range manifest.Kinds {
+		if kind != KubernetesKindSecret && kind != KubernetesKindConfigMap {
+			return fmt.Errorf("unsupported kubernetes kind %s on target %s, use %s or %s", kind, c.Name, KubernetesKindSecret, KubernetesKindConfigMap)
+		}
+	}
+
+	// check if output file is double defined
+	for _, fileToRender := range c.FilesToRender {
+		if fileToRender.FileOut == fileOut {
+			return fmt.Errorf("output file %s is already defined on target %s", fileOut, c.Name)
+		}
+	}
+
+	c.FilesToRender = append(c.FilesToRender, &FileToRender{
+		FileOut:    fileOut,
+		Kubernetes: manifest,
 	})
 
 	return nil
 }
 
+// AddPartial adds a glob pattern of partial templates which are available in all files of the target
+func (c *RenderTarget) AddPartial(pattern string) error {
+	for _, partial := range c.Partials {
+		if partial == pattern {
+			return fmt.Errorf("partial %s is already defined on target %s", pattern, c.Name)
+		}
+	}
+	c.Partials = append(c.Partials, pattern)
+	return nil
+}
+
+// GetPartials returns the partials of the target followed by the partials of the file
+// the partials of the target are read when rendering, so partials added after the file are used as well
+func (f *FileToRender) GetPartials() []string {
+	if f.target == nil {
+		return f.Partials
+	}
+	return append(append([]string{}, f.target.Partials...), f.Partials...)
+}
+
 func (c *Repository) AddRenderTarget(target *RenderTarget) error {
 	if c.HasRenderTarget(target.Name) {
 		return fmt.Errorf("the render target %s already exists", target.Name)
@@ -63,3 +146,16 @@ func (c *Repository) RenderTargetNames() (names []string) {
 	}
 	return names
 }
+
+// RendersKind returns true if the manifest of the given kind should be rendered
+func (m *KubernetesManifest) RendersKind(kind string) bool {
+	if len(m.Kinds) == 0 {
+		return true
+	}
+	for _, renderedKind := range m.Kinds {
+		if renderedKind == kind {
+			return true
+		}
+	}
+	return false
+}
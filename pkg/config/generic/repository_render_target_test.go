@@ -20,6 +20,54 @@ func TestRenderTarget_AddFileToRender(t *testing.T) {
 	assert.Error(t, newRenderTarget.AddFileToRender("fileIn", "fileOut"))
 }
 
+func TestRenderTarget_AddKubernetesManifest(t *testing.T) {
+	newRenderTarget := NewRenderTarget("test")
+	assert.Error(t, newRenderTarget.AddKubernetesManifest("fileOut", &KubernetesManifest{}))
+	assert.Error(t, newRenderTarget.AddKubernetesManifest("fileOut", &KubernetesManifest{Name: "api", Kinds: []string{"Deployment"}}))
+	assert.NoError(t, newRenderTarget.AddKubernetesManifest("fileOut", &KubernetesManifest{Name: "api"}))
+	assert.Error(t, newRenderTarget.AddKubernetesManifest("fileOut", &KubernetesManifest{Name: "api"}))
+	assert.Error(t, newRenderTarget.AddFileToRender("fileIn", "fileOut"))
+	assert.Len(t, newRenderTarget.FilesToRender, 1)
+	assert.Equal(t, "", newRenderTarget.FilesToRender[0].FileIn)
+	assert.Equal(t, "api", newRenderTarget.FilesToRender[0].Kubernetes.Name)
+}
+
+func TestKubernetesManifest_RendersKind(t *testing.T) {
+	assert.True(t, (&KubernetesManifest{}).RendersKind(KubernetesKindSecret))
+	assert.True(t, (&KubernetesManifest{}).RendersKind(KubernetesKindConfigMap))
+	onlySecret := &KubernetesManifest{Kinds: []string{KubernetesKindSecret}}
+	assert.True(t, onlySecret.RendersKind(KubernetesKindSecret))
+	assert.False(t, onlySecret.RendersKind(KubernetesKindConfigMap))
+}
+
+func TestParseKubernetesRenderTarget(t *testing.T) {
+	repo := initRepository(t, TestFileKubernetes, "default")
+	target := repo.GetRenderTarget("k8s")
+	assert.NotNil(t, target)
+	assert.Len(t, target.FilesToRender, 1)
+	manifest := target.FilesToRender[0].Kubernetes
+	assert.NotNil(t, manifest)
+	assert.Equal(t, "api", manifest.Name)
+	assert.Equal(t, "{{.Configs.namespace}}", manifest.Namespace)
+	assert.Equal(t, map[string]string{"app": "api"}, manifest.Labels)
+	assert.Equal(t, []string{"Secret"}, manifest.Kinds)
+	assert.True(t, manifest.StringData)
+}
+
+func TestRenderTarget_AddPartial(t *testing.T) {
+	newRenderTarget := NewRenderTarget("test")
+	assert.NoError(t, newRenderTarget.AddFileToRender("fileIn", "fileOut"))
+	assert.NoError(t, newRenderTarget.AddPartial("partials/*.tpl"))
+	assert.Error(t, newRenderTarget.AddPartial("partials/*.tpl"))
+	assert.NoError(t, newRenderTarget.AddFileToRender("otherFileIn", "otherFileOut"))
+	assert.Equal(t, []string{"partials/*.tpl"}, newRenderTarget.FilesToRender[0].GetPartials())
+	assert.Equal(t, []string{"partials/*.tpl"}, newRenderTarget.FilesToRender[1].GetPartials())
+
+	newRenderTarget.FilesToRender[0].Partials = []string{"own/*.tpl"}
+	assert.Equal(t, []string{"partials/*.tpl", "own/*.tpl"}, newRenderTarget.FilesToRender[0].GetPartials())
+	assert.Equal(t, []string{"own/*.tpl"}, (&FileToRender{Partials: []string{"own/*.tpl"}}).GetPartials())
+}
+
 func TestRepository_AddRenderTarget(t *testing.T) {
 	newRenderTarget := NewRenderTarget("test")
 	repo := initRepository(t, TestFileBlankDefault, "default")
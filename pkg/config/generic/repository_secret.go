@@ -3,7 +3,9 @@ package config_generic
 import (
 	"fmt"
 	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	"github.com/benammann/git-secrets/pkg/encryption"
 	"sort"
+	"strings"
 )
 
 type Secret struct {
@@ -16,36 +18,46 @@ type Secret struct {
 
 	// OriginContext references the configured context to decode the secret
 	OriginContext *Context
+
+	// Metadata annotates the entry, only available since schema v2
+	Metadata Metadata
+
+	// SourceFile holds the abs path of the config file defining the entry
+	SourceFile string
+
+	// repository references the repository the secret has been added to, its hook is notified on decode
+	repository *Repository
 }
 
 // AddSecret adds a secret to the repository
 // also does some validations
 func (c *Repository) AddSecret(secret *Secret) error {
 
-	// if not default secret we need to check if the given secret is also configured in the default context
+	// if not default secret we need to check if the given secret is also configured in an ancestor context
 	// because we are not allowed to define variables only in a child context
 	if secret.OriginContext.Name != config_const.DefaultContextName {
 
-		// get all the default secrets
-		defaultSecrets := c.GetSecretsByContext(config_const.DefaultContextName)
-		defaultSecretFound := false
+		ancestorSecretFound := false
 
-		// check if it defined
-		for _, defaultSecret := range defaultSecrets {
-			if defaultSecret.Name == secret.Name {
-				defaultSecretFound = true
-				break
+		// check if it is defined in one of the ancestors
+		for _, ancestor := range secret.OriginContext.Ancestors() {
+			for _, ancestorSecret := range c.GetSecretsByContext(ancestor.Name) {
+				if ancestorSecret.Name == secret.Name {
+					ancestorSecretFound = true
+					break
+				}
 			}
 		}
 
 		// return error if not defined
-		if defaultSecretFound == false {
-			return fmt.Errorf("secret %s defined in context %s is not defined in the default context", secret.Name, secret.OriginContext.Name)
+		if ancestorSecretFound == false {
+			return fmt.Errorf("secret %s defined in context %s is not defined in the parent contexts %s", secret.Name, secret.OriginContext.Name, strings.Join(secret.OriginContext.ChainNames()[1:], ", "))
 		}
 
 	}
 
 	// append the secret to the repository
+	secret.repository = c
 	c.secrets = append(c.secrets, secret)
 
 	// sort the secrets alphabetically
@@ -66,41 +78,20 @@ func (c *Repository) GetSecretsByContext(contextName string) (res []*Secret) {
 	return res
 }
 
-// GetCurrentSecrets merges the default secrets with the context secrets
-// the default secrets are overwritten by the context secrets
+// GetCurrentSecrets merges the secrets of the current context with the secrets of its ancestors
+// the secrets of a context overwrite the secrets of its ancestors
 func (c *Repository) GetCurrentSecrets() (res []*Secret) {
 
-	// get all default secrets
-	defaultSecrets := c.GetSecretsByContext(config_const.DefaultContextName)
-
-	// if not default, merge the secrets with the default secrets
-	if !c.IsDefault() {
-
-		// result is context secrets
-		contextSecrets := c.GetSecretsByContext(c.context.Name)
-
-		// add the default secret if it is missing in the context secrets
-		for _, defaultSecret := range defaultSecrets {
-
-			found := false
-			for _, contextSecret := range contextSecrets {
-				if defaultSecret.Name == contextSecret.Name {
-					found = true
-					break
-				}
-			}
+	found := make(map[string]bool)
 
-			if found == false {
-				contextSecrets = append(contextSecrets, defaultSecret)
+	for _, context := range c.context.Chain() {
+		for _, secret := range c.GetSecretsByContext(context.Name) {
+			if found[secret.Name] {
+				continue
 			}
-
+			found[secret.Name] = true
+			res = append(res, secret)
 		}
-
-		res = contextSecrets
-
-	} else {
-		// result is just the default secrets
-		res = defaultSecrets
 	}
 
 	return res
@@ -117,8 +108,23 @@ func (c *Repository) GetCurrentSecret(secretName string) *Secret {
 	return nil
 }
 
+// Decode decrypts the secret using its origin context, the hook of the repository is notified
 func (s *Secret) Decode() (string, error) {
-	return s.OriginContext.DecodeValue(s.EncodedValue)
+	decodedValue, errDecode := s.OriginContext.DecodeValue(s.EncodedValue)
+	if s.repository != nil {
+		s.repository.notifySecretDecoded(s, errDecode)
+	}
+	return decodedValue, errDecode
+}
+
+// Fingerprint decodes the secret and returns the keyed fingerprint of its value
+// fingerprints of secrets encrypted with the same secret can be compared without revealing the values
+func (s *Secret) Fingerprint() (string, error) {
+	decodedValue, errDecode := s.Decode()
+	if errDecode != nil {
+		return "", errDecode
+	}
+	return encryption.Fingerprint(s.OriginContext.SecretResolver, decodedValue)
 }
 
 // GetSecretsMapDecoded decodes the secrets of the current context and puts them into a map[string]string
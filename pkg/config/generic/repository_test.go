@@ -6,6 +6,8 @@ import (
 	global_config "github.com/benammann/git-secrets/pkg/config/global"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -28,6 +30,13 @@ const TestFileInvalidJson = "generic_repository_test-invalid-json.json"
 const TestFileInvalidJsonV1 = "generic_repository_test-invalid-version-v1.json"
 const TestFileInvalidVersion = "generic_repository_test-invalid-version.json"
 const TestFileBlankDefaultRenderFilesMissingKey = "generic_repository_test-blank-render-files-missing-key.json"
+const TestFileExec = "generic_repository_test-exec.json"
+const TestFileKubernetes = "generic_repository_test-kubernetes.json"
+const TestFileV2 = "generic_repository_test-v2.json"
+const TestFileExtends = "generic_repository_test-extends.json"
+const TestFileRealWorldYaml = "generic_repository_test-real-world.yaml"
+const TestFileRealWorldToml = "generic_repository_test-real-world.toml"
+const TestFileKeyFingerprint = "generic_repository_test-key-fingerprint.json"
 
 func createTestRepository(fileName string, selectedContextName string) (*Repository, error) {
 	fileName = fmt.Sprintf("test_fs/%s", fileName)
@@ -99,3 +108,43 @@ func TestRepository_IsDefault(t *testing.T) {
 	assert.Equal(t, "prod", prodContext.Name)
 
 }
+
+func TestRepository_SyncAfterWrite(t *testing.T) {
+
+	createWritableRepository := func(t *testing.T) *Repository {
+		contents, errRead := testFiles.ReadFile("test_fs/" + TestFileExtends)
+		assert.NoError(t, errRead)
+		configPath := filepath.Join(t.TempDir(), ".git-secrets.json")
+		assert.NoError(t, os.WriteFile(configPath, contents, 0644))
+		globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+		repo, errParse := ParseRepository(afero.NewOsFs(), configPath, globalConfig, map[string]string{})
+		assert.NoError(t, errParse)
+		_, errSelect := repo.SetSelectedContext("prod-eu")
+		assert.NoError(t, errSelect)
+		return repo
+	}
+
+	t.Run("it should reflect the written changes", func(t *testing.T) {
+		repo := createWritableRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().SetConfig("prod", "databaseName", "app-prod", false))
+		assert.Equal(t, "app-prod", repo.GetCurrentConfig("databaseName").Value)
+		assert.Equal(t, "prod-eu", repo.GetCurrent().Name)
+	})
+
+	t.Run("it should reflect the changes after the commit", func(t *testing.T) {
+		repo := createWritableRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().Begin())
+		assert.NoError(t, repo.GetConfigWriter().AddContext("staging"))
+		assert.Nil(t, repo.GetContext("staging"))
+		assert.NoError(t, repo.GetConfigWriter().Commit())
+		assert.NotNil(t, repo.GetContext("staging"))
+	})
+
+	t.Run("it should select the default context if the selected context is removed", func(t *testing.T) {
+		repo := createWritableRepository(t)
+		assert.NoError(t, repo.GetConfigWriter().RemoveContext("prod"))
+		assert.Equal(t, "default", repo.GetCurrent().Name)
+		assert.Len(t, repo.GetContexts(), 1)
+	})
+
+}
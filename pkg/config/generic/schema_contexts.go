@@ -0,0 +1,140 @@
+package config_generic
+
+import (
+	"fmt"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	"sort"
+)
+
+// contextSchema gives the writers access to the contexts of a schema version, so secrets, configs and contexts are removed and renamed the same way
+type contextSchema interface {
+	// contextExtends returns the extended context of each context, empty if the context does not extend
+	contextExtends() map[string]string
+	// renderTargets returns the render targets of the schema
+	renderTargets() map[string]*V1RenderTarget
+	// hasContext returns true if the context exists
+	hasContext(contextName string) bool
+	// moveContext moves the context to the new name without updating the contexts extending it
+	moveContext(oldName string, newName string)
+	// deleteContext deletes the context
+	deleteContext(contextName string)
+	// setExtends sets the context extended by the context
+	setExtends(contextName string, extends string)
+	// definesEntry returns true if the context defines the secret or config entry
+	definesEntry(contextName string, key string, isSecret bool) bool
+	// moveEntry moves the secret or config entry of the context to the new name if the context defines it
+	moveEntry(contextName string, oldKey string, newKey string, isSecret bool)
+	// deleteEntry deletes the secret or config entry of the context
+	deleteEntry(contextName string, key string, isSecret bool)
+}
+
+// removeSchemaEntry removes the secret or config entry from the context
+// if the entry is removed from the context defining it, it is also removed from all child contexts
+func removeSchemaEntry(schema contextSchema, contextName string, key string, isSecret bool) error {
+
+	if !schema.hasContext(contextName) {
+		return fmt.Errorf("the context %s does not exist", contextName)
+	}
+
+	defines := func(contextName string) bool {
+		return schema.definesEntry(contextName, key, isSecret)
+	}
+
+	if !defines(contextName) {
+		return fmt.Errorf("%s %s is not defined in context %s", entryKind(isSecret), key, contextName)
+	}
+
+	removeFrom := removalContexts(schema.contextExtends(), contextName, defines)
+
+	// the render targets can only be changed if the entry is removed from all contexts
+	if len(removeFrom) == len(definingContexts(schema, key, isSecret)) {
+		if errRemoveKey := removeKubernetesKey(schema.renderTargets(), key, isSecret); errRemoveKey != nil {
+			return errRemoveKey
+		}
+	}
+
+	for _, removeContext := range removeFrom {
+		schema.deleteEntry(removeContext, key, isSecret)
+	}
+
+	return nil
+
+}
+
+// renameSchemaEntry renames the secret or config entry in all contexts and render targets
+func renameSchemaEntry(schema contextSchema, oldName string, newName string, isSecret bool) error {
+
+	if len(definingContexts(schema, oldName, isSecret)) == 0 {
+		return fmt.Errorf("the %s %s does not exist", entryKind(isSecret), oldName)
+	}
+
+	if len(definingContexts(schema, newName, isSecret)) > 0 {
+		return fmt.Errorf("the %s %s does already exist", entryKind(isSecret), newName)
+	}
+
+	for contextName := range schema.contextExtends() {
+		schema.moveEntry(contextName, oldName, newName, isSecret)
+	}
+
+	renameKubernetesKey(schema.renderTargets(), oldName, newName, isSecret)
+	return nil
+
+}
+
+// removeSchemaContext removes the context and all contexts extending it
+func removeSchemaContext(schema contextSchema, contextName string) error {
+
+	if contextName == config_const.DefaultContextName {
+		return fmt.Errorf("the default context can not be removed")
+	}
+
+	if !schema.hasContext(contextName) {
+		return fmt.Errorf("the context %s does not exist", contextName)
+	}
+
+	for _, descendant := range descendantContexts(schema.contextExtends(), contextName) {
+		schema.deleteContext(descendant)
+	}
+	schema.deleteContext(contextName)
+
+	return nil
+
+}
+
+// renameSchemaContext renames the context and updates the contexts extending it
+func renameSchemaContext(schema contextSchema, oldName string, newName string) error {
+
+	if oldName == config_const.DefaultContextName {
+		return fmt.Errorf("the default context can not be renamed")
+	}
+
+	if !schema.hasContext(oldName) {
+		return fmt.Errorf("the context %s does not exist", oldName)
+	}
+
+	if schema.hasContext(newName) {
+		return fmt.Errorf("the context %s does already exist", newName)
+	}
+
+	schema.moveContext(oldName, newName)
+
+	for contextName, extends := range schema.contextExtends() {
+		if extends == oldName {
+			schema.setExtends(contextName, newName)
+		}
+	}
+
+	return nil
+
+}
+
+// definingContexts returns the names of the contexts defining the secret or config entry sorted alphabetically
+func definingContexts(schema contextSchema, key string, isSecret bool) (res []string) {
+	for contextName := range schema.contextExtends() {
+		if schema.definesEntry(contextName, key, isSecret) {
+			res = append(res, contextName)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,55 @@
+package config_generic
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestContextSchema(t *testing.T) {
+
+	v1Schema := ParseAsSchemaV1(t, "real-world.json")
+	v2Schema := V2Schema{Version: 2, Context: make(V2Context), RenderFiles: v1Schema.RenderFiles}
+	for contextName, context := range v1Schema.Context {
+		v2Context := &V2ContextAwareSecrets{Extends: context.Extends, Secrets: make(map[string]*V2Entry), Configs: make(map[string]*V2Entry)}
+		setEntryValues(v2Context.Secrets, context.Secrets)
+		setEntryValues(v2Context.Configs, context.Configs)
+		v2Schema.Context[contextName] = v2Context
+	}
+
+	for name, newSchema := range map[string]func() contextSchema{
+		"v1": func() contextSchema {
+			var schema V1Schema
+			assert.NoError(t, copyDocument(v1Schema, &schema))
+			return &schema
+		},
+		"v2": func() contextSchema {
+			var schema V2Schema
+			assert.NoError(t, copyDocument(v2Schema, &schema))
+			return &schema
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+
+			schema := newSchema()
+			assert.Equal(t, []string{"default", "prod", "staging"}, definingContexts(schema, "databasePassword", true))
+			assert.NoError(t, removeSchemaEntry(schema, "prod", "databaseHost", false))
+			assert.Equal(t, []string{"default", "staging"}, definingContexts(schema, "databaseHost", false))
+			assert.Error(t, removeSchemaEntry(schema, "prod", "databaseHost", false))
+
+			assert.NoError(t, renameSchemaEntry(schema, "databasePassword", "databaseSecret", true))
+			assert.Equal(t, []string{"default", "prod", "staging"}, definingContexts(schema, "databaseSecret", true))
+			assert.Error(t, renameSchemaEntry(schema, "databasePort", "databaseHost", false))
+
+			schema = newSchema()
+			schema.setExtends("staging", "prod")
+			assert.NoError(t, renameSchemaContext(schema, "prod", "production"))
+			assert.Equal(t, "production", schema.contextExtends()["staging"])
+			assert.Error(t, renameSchemaContext(schema, "default", "base"))
+			assert.NoError(t, removeSchemaContext(schema, "production"))
+			assert.False(t, schema.hasContext("staging"))
+			assert.Error(t, removeSchemaContext(schema, "default"))
+
+		})
+	}
+
+}
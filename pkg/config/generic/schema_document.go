@@ -0,0 +1,153 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/jsonedit"
+	"github.com/benammann/git-secrets/pkg/yamledit"
+	"github.com/spf13/afero"
+	"os"
+)
+
+// copyDocument deep copies the json representation of in into out
+func copyDocument(in interface{}, out interface{}) error {
+	encoded, errMarshal := json.Marshal(in)
+	if errMarshal != nil {
+		return errMarshal
+	}
+	return json.Unmarshal(encoded, out)
+}
+
+// writeSchema writes the schema to the config file
+// existing json and yaml files are edited in place so only the changed values are touched, new files and toml files are encoded as a whole
+// model must be a pointer to an empty schema of the same version, it is used to find the changed values
+func writeSchema(fs afero.Fs, configPath string, schema interface{}, model interface{}) error {
+
+	newConfig, _ := json.MarshalIndent(schema, "", "  ")
+
+	format := DetectFileFormat(configPath)
+	existingConfig, errRead := afero.ReadFile(fs, configPath)
+	if errRead != nil && !os.IsNotExist(errRead) {
+		return fmt.Errorf("could not read config: %s", errRead.Error())
+	}
+	if errRead == nil && format != FileFormatToml {
+		// a failed edit is not replaced by a full rewrite, it would drop the comments and unknown keys without notice
+		editedConfig, errEdit := editDocument(format, existingConfig, model, newConfig)
+		if errEdit != nil {
+			return fmt.Errorf("could not edit config %s: %s", configPath, errEdit.Error())
+		}
+		newConfig = editedConfig
+	} else {
+		encodedConfig, errEncode := EncodeDocument(format, newConfig)
+		if errEncode != nil {
+			return fmt.Errorf("could not encode config: %s", errEncode.Error())
+		}
+		newConfig = encodedConfig
+	}
+
+	return writeConfigFile(fs, configPath, newConfig)
+
+}
+
+// writeConfigFile writes the contents to a temporary file first and moves it in place, so readers never see a partially written config
+// the mode of an existing config file is kept
+func writeConfigFile(fs afero.Fs, configPath string, newConfig []byte) error {
+
+	fileMode := os.FileMode(0664)
+	if info, errStat := fs.Stat(configPath); errStat == nil {
+		fileMode = info.Mode()
+	}
+
+	tmpPath := configPath + ".tmp"
+	if errWrite := afero.WriteFile(fs, tmpPath, newConfig, fileMode); errWrite != nil {
+		_ = fs.Remove(tmpPath)
+		return fmt.Errorf("could not overwrite config: %s", errWrite.Error())
+	}
+
+	if errRename := fs.Rename(tmpPath, configPath); errRename != nil {
+		_ = fs.Remove(tmpPath)
+		return fmt.Errorf("could not overwrite config: %s", errRename.Error())
+	}
+
+	return nil
+
+}
+
+// checkpointFile reads the config file and returns a function writing it back, a config file which did not exist is removed
+func checkpointFile(fs afero.Fs, configPath string) (func() error, error) {
+
+	contents, errRead := afero.ReadFile(fs, configPath)
+	if os.IsNotExist(errRead) {
+		return func() error {
+			return fs.Remove(configPath)
+		}, nil
+	}
+	if errRead != nil {
+		return nil, fmt.Errorf("could not read config: %s", errRead.Error())
+	}
+
+	info, errStat := fs.Stat(configPath)
+	if errStat != nil {
+		return nil, fmt.Errorf("could not read config: %s", errStat.Error())
+	}
+
+	return func() error {
+		unlock, errLock := lockConfig(fs, configPath)
+		if errLock != nil {
+			return errLock
+		}
+		defer unlock()
+		if errWrite := afero.WriteFile(fs, configPath, contents, info.Mode()); errWrite != nil {
+			return fmt.Errorf("could not restore config: %s", errWrite.Error())
+		}
+		return nil
+	}, nil
+
+}
+
+// editDocument applies the changes between the existing and the new config to the existing json or yaml document
+// the existing config is read through the model first, so keys unknown to the schema are never touched
+func editDocument(format FileFormat, existingConfig []byte, model interface{}, newConfig []byte) ([]byte, error) {
+
+	normalizedConfig, errNormalize := NormalizeDocument(format, existingConfig)
+	if errNormalize != nil {
+		return nil, errNormalize
+	}
+
+	if errParse := json.Unmarshal(normalizedConfig, model); errParse != nil {
+		return nil, errParse
+	}
+	knownConfig, _ := json.Marshal(model)
+
+	var before, after interface{}
+	if errBefore := json.Unmarshal(knownConfig, &before); errBefore != nil {
+		return nil, errBefore
+	}
+	if errAfter := json.Unmarshal(newConfig, &after); errAfter != nil {
+		return nil, errAfter
+	}
+
+	switch format {
+	case FileFormatJson:
+		document, errDocument := jsonedit.NewDocument(existingConfig)
+		if errDocument != nil {
+			return nil, errDocument
+		}
+		if errApply := document.ApplyChanges(before, after); errApply != nil {
+			return nil, errApply
+		}
+		return document.Bytes(), nil
+	case FileFormatYaml:
+		document, errDocument := yamledit.NewDocument(existingConfig)
+		if errDocument != nil {
+			return nil, errDocument
+		}
+		if errApply := document.ApplyChanges(before, after); errApply != nil {
+			return nil, errApply
+		}
+		return document.Bytes()
+	default:
+		return nil, fmt.Errorf("%s documents can not be edited in place", format)
+	}
+
+}
@@ -0,0 +1,171 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const goldenInput = "golden/v1-input.json"
+const goldenInputV2 = "golden/v2-input.json"
+
+// assertGoldenWrite runs the write on a copy of the golden input and compares the result with the golden file
+func assertGoldenWrite(t *testing.T, goldenFile string, write func(writer *V1Writer) error) {
+	assertGoldenFile(t, goldenInput, goldenFile, func(fs afero.Fs, configPath string) error {
+		var schema V1Schema
+		assert.NoError(t, json.Unmarshal(readTestFile(t, goldenInput), &schema))
+		return write(NewV1Writer(fs, schema, configPath))
+	})
+}
+
+// assertGoldenWriteV2 runs the write on a copy of the v2 golden input and compares the result with the golden file
+func assertGoldenWriteV2(t *testing.T, goldenFile string, write func(writer *V2Writer) error) {
+	assertGoldenFile(t, goldenInputV2, goldenFile, func(fs afero.Fs, configPath string) error {
+		var schema V2Schema
+		assert.NoError(t, json.Unmarshal(readTestFile(t, goldenInputV2), &schema))
+		return write(NewV2Writer(fs, schema, configPath))
+	})
+}
+
+func assertGoldenFile(t *testing.T, inputFile string, goldenFile string, write func(fs afero.Fs, configPath string) error) {
+
+	fs := afero.NewMemMapFs()
+	configPath := ".git-secrets.json"
+	assert.NoError(t, afero.WriteFile(fs, configPath, readTestFile(t, inputFile), 0664))
+
+	assert.NoError(t, write(fs, configPath))
+
+	written, errRead := afero.ReadFile(fs, configPath)
+	assert.NoError(t, errRead)
+	assert.Equal(t, string(readTestFile(t, fmt.Sprintf("golden/%s", goldenFile))), string(written))
+
+}
+
+func TestV1Writer_Golden(t *testing.T) {
+
+	t.Run("write config without changes", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-input.json", func(writer *V1Writer) error {
+			return writer.WriteConfig()
+		})
+	})
+
+	t.Run("overwrite a secret", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-set-secret.json", func(writer *V1Writer) error {
+			return writer.SetSecret("default", "databasePassword", "newEncodedValue", true)
+		})
+	})
+
+	t.Run("add and overwrite secrets at once", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-set-secrets.json", func(writer *V1Writer) error {
+			return writer.SetSecrets("default", map[string]string{"apiKey": "encodedApiKey", "databasePassword": "newEncodedValue"}, true)
+		})
+	})
+
+	t.Run("add a config", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-set-config.json", func(writer *V1Writer) error {
+			return writer.SetConfig("prod", "databaseHost", "database-prod.svc.cluster", false)
+		})
+	})
+
+	t.Run("add configs", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-set-configs.json", func(writer *V1Writer) error {
+			return writer.SetConfigs("default", map[string]string{"databaseName": "app", "databasePort": "3308"}, true)
+		})
+	})
+
+	t.Run("add a context", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-add-context.json", func(writer *V1Writer) error {
+			return writer.AddContext("staging")
+		})
+	})
+
+	t.Run("add a file to an existing target", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-add-file-to-render.json", func(writer *V1Writer) error {
+			return writer.AddFileToRender("env", "templates/.env.test.dist", "templates/.env.test")
+		})
+	})
+
+	t.Run("add a file to a new target", func(t *testing.T) {
+		assertGoldenWrite(t, "v1-add-file-to-render-new-target.json", func(writer *V1Writer) error {
+			return writer.AddFileToRender("k8s", "k8s/secret.yaml.dist", "k8s/secret.yaml")
+		})
+	})
+
+}
+
+func TestV2Writer_Golden(t *testing.T) {
+
+	t.Run("write config without changes", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-input.json", func(writer *V2Writer) error {
+			return writer.WriteConfig()
+		})
+	})
+
+	t.Run("overwrite a secret", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-set-secret.json", func(writer *V2Writer) error {
+			return writer.SetSecret("default", "databasePassword", "newEncodedValue", true)
+		})
+	})
+
+	t.Run("add and overwrite secrets at once", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-set-secrets.json", func(writer *V2Writer) error {
+			return writer.SetSecrets("default", map[string]string{"apiKey": "encodedApiKey", "databasePassword": "newEncodedValue"}, true)
+		})
+	})
+
+	t.Run("add a config", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-set-config.json", func(writer *V2Writer) error {
+			return writer.SetConfig("prod", "databaseHost", "database-prod.svc.cluster", false)
+		})
+	})
+
+	t.Run("add configs", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-set-configs.json", func(writer *V2Writer) error {
+			return writer.SetConfigs("default", map[string]string{"databaseName": "app", "databasePort": "3308"}, true)
+		})
+	})
+
+	t.Run("add a context", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-add-context.json", func(writer *V2Writer) error {
+			return writer.AddContext("staging")
+		})
+	})
+
+	t.Run("add a file to an existing target", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-add-file-to-render.json", func(writer *V2Writer) error {
+			return writer.AddFileToRender("env", "templates/.env.test.dist", "templates/.env.test")
+		})
+	})
+
+	t.Run("add a file to a new target", func(t *testing.T) {
+		assertGoldenWriteV2(t, "v2-add-file-to-render-new-target.json", func(writer *V2Writer) error {
+			return writer.AddFileToRender("k8s", "k8s/secret.yaml.dist", "k8s/secret.yaml")
+		})
+	})
+
+}
+
+func TestWriteSchema(t *testing.T) {
+
+	t.Run("fail instead of rewriting a config which can not be edited", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		configPath := ".git-secrets.json"
+		assert.NoError(t, afero.WriteFile(fs, configPath, []byte(`{"version": 1,`), 0664))
+		assert.Error(t, writeSchema(fs, configPath, V1Schema{Version: 1}, &V1Schema{}))
+		written, errRead := afero.ReadFile(fs, configPath)
+		assert.NoError(t, errRead)
+		assert.Equal(t, `{"version": 1,`, string(written))
+	})
+
+	t.Run("encode a new config as a whole", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		configPath := ".git-secrets.json"
+		assert.NoError(t, writeSchema(fs, configPath, V1Schema{Version: 1}, &V1Schema{}))
+		exists, errExists := afero.Exists(fs, configPath)
+		assert.NoError(t, errExists)
+		assert.True(t, exists)
+	})
+
+}
@@ -0,0 +1,98 @@
+package config_generic
+
+import (
+	"fmt"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	"sort"
+	"strings"
+)
+
+// validateExtends checks the extends of all contexts (contextName -> extended contextName)
+// every extended context must exist, the default context can not extend and cycles are not allowed
+func validateExtends(extends map[string]string) error {
+
+	if extends[config_const.DefaultContextName] != "" {
+		return fmt.Errorf("context: %s: the default context can not extend another context", config_const.DefaultContextName)
+	}
+
+	var contextNames []string
+	for contextName := range extends {
+		contextNames = append(contextNames, contextName)
+	}
+	sort.Strings(contextNames)
+
+	for _, contextName := range contextNames {
+		parentName := extends[contextName]
+		if parentName == "" {
+			continue
+		}
+		if _, exists := extends[parentName]; !exists {
+			return fmt.Errorf("context: %s: extends the context %s which does not exist", contextName, parentName)
+		}
+		visited := []string{contextName}
+		for current := parentName; current != ""; current = extends[current] {
+			for _, visitedName := range visited {
+				if visitedName == current {
+					return fmt.Errorf("context: %s: cyclic extends %s -> %s", contextName, strings.Join(visited, " -> "), current)
+				}
+			}
+			visited = append(visited, current)
+		}
+	}
+
+	return nil
+
+}
+
+// contextAncestors returns the names of the parent contexts, the nearest first and the default context last
+// contexts without extends have the default context as parent, the extends must be validated before
+func contextAncestors(extends map[string]string, contextName string) (res []string) {
+	for current := contextName; current != config_const.DefaultContextName; {
+		parentName := extends[current]
+		if parentName == "" {
+			parentName = config_const.DefaultContextName
+		}
+		res = append(res, parentName)
+		current = parentName
+	}
+	return res
+}
+
+// descendantContexts returns the names of all contexts inheriting from the context, sorted by name
+func descendantContexts(extends map[string]string, contextName string) (res []string) {
+	for otherName := range extends {
+		if otherName == contextName {
+			continue
+		}
+		for _, ancestor := range contextAncestors(extends, otherName) {
+			if ancestor == contextName {
+				res = append(res, otherName)
+				break
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// removalContexts returns the contexts an entry has to be removed from when removing it from the context
+// if no ancestor defines the entry the child contexts overwriting it would become invalid, so they are removed as well
+func removalContexts(extends map[string]string, contextName string, defines func(contextName string) bool) []string {
+
+	res := []string{contextName}
+
+	for _, ancestor := range contextAncestors(extends, contextName) {
+		if defines(ancestor) {
+			return res
+		}
+	}
+
+	for _, descendant := range descendantContexts(extends, contextName) {
+		if defines(descendant) {
+			res = append(res, descendant)
+		}
+	}
+
+	return res
+
+}
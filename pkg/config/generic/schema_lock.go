@@ -0,0 +1,87 @@
+package config_generic
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/jsonedit"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/spf13/afero"
+	"os"
+	"strings"
+)
+
+// lockConfig acquires the advisory lock of the config file and returns the function to release it
+func lockConfig(fs afero.Fs, configPath string) (func(), error) {
+	unlock, errLock := utility.LockFile(fs, configPath)
+	if errLock != nil {
+		return nil, fmt.Errorf("could not write config: %s", errLock.Error())
+	}
+	return unlock, nil
+}
+
+// readChangedSchema reads the config file into current and reports whether it differs from base
+// the schemas are compared by the hash of their json encoding, so formatting changes are ignored
+func readChangedSchema(fs afero.Fs, configPath string, base interface{}, current interface{}) (bool, error) {
+
+	contents, errRead := afero.ReadFile(fs, configPath)
+	if os.IsNotExist(errRead) {
+		return false, nil
+	}
+	if errRead != nil {
+		return false, fmt.Errorf("could not read config: %s", errRead.Error())
+	}
+
+	jsonContents, errNormalize := NormalizeDocument(DetectFileFormat(configPath), contents)
+	if errNormalize != nil {
+		return false, fmt.Errorf("could not read config: %s", errNormalize.Error())
+	}
+	if errParse := json.Unmarshal(jsonContents, current); errParse != nil {
+		return false, fmt.Errorf("could not parse config: %s", errParse.Error())
+	}
+
+	currentHash, errCurrent := schemaHash(current)
+	if errCurrent != nil {
+		return false, errCurrent
+	}
+	baseHash, errBase := schemaHash(base)
+	if errBase != nil {
+		return false, errBase
+	}
+
+	return currentHash != baseHash, nil
+
+}
+
+// schemaHash returns the sha256 hash of the json encoded schema
+func schemaHash(schema interface{}) ([sha256.Size]byte, error) {
+	encoded, errMarshal := json.Marshal(schema)
+	if errMarshal != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("could not encode config: %s", errMarshal.Error())
+	}
+	return sha256.Sum256(encoded), nil
+}
+
+// mergeSchema merges the changes made on top of base (ours) with the changes written by another process (theirs) into out
+// changes to different keys are merged, an error naming the keys is returned if both changed the same key differently
+func mergeSchema(base interface{}, ours interface{}, theirs interface{}, out interface{}) error {
+
+	var decoded [3]interface{}
+	for i, schema := range []interface{}{base, ours, theirs} {
+		if errDecode := copyDocument(schema, &decoded[i]); errDecode != nil {
+			return fmt.Errorf("could not merge config: %s", errDecode.Error())
+		}
+	}
+
+	merged, conflicts := jsonedit.Merge(decoded[0], decoded[1], decoded[2])
+	if len(conflicts) > 0 {
+		var conflictPaths []string
+		for _, conflict := range conflicts {
+			conflictPaths = append(conflictPaths, strings.Join(conflict, "."))
+		}
+		return fmt.Errorf("conflicting changes at %s", strings.Join(conflictPaths, ", "))
+	}
+
+	return copyDocument(merged, out)
+
+}
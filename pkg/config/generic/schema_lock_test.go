@@ -0,0 +1,167 @@
+package config_generic
+
+import (
+	"fmt"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const envHelperConfigPath = "GIT_SECRETS_TEST_CONFIG_PATH"
+const envHelperConfigKey = "GIT_SECRETS_TEST_CONFIG_KEY"
+
+// createSharedConfig copies the test file to a temporary directory and returns its path
+func createSharedConfig(t *testing.T, fileName string) string {
+	contents, errRead := testFiles.ReadFile("test_fs/" + fileName)
+	assert.NoError(t, errRead)
+	configPath := filepath.Join(t.TempDir(), ".git-secrets"+filepath.Ext(fileName))
+	assert.NoError(t, os.WriteFile(configPath, contents, 0644))
+	return configPath
+}
+
+// parseSharedConfig parses the config from the os file system like the cli does
+func parseSharedConfig(t *testing.T, configPath string) *Repository {
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	repo, errParse := ParseRepository(afero.NewOsFs(), configPath, globalConfig, map[string]string{})
+	assert.NoError(t, errParse)
+	_, errSelect := repo.SetSelectedContext("default")
+	assert.NoError(t, errSelect)
+	return repo
+}
+
+func TestLockConfig(t *testing.T) {
+
+	t.Run("it should wait for the lock to be released", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		unlock, errLock := lockConfig(fs, "wait.json")
+		assert.NoError(t, errLock)
+		exists, _ := afero.Exists(fs, "wait.json.lock")
+		assert.True(t, exists)
+
+		acquired := make(chan bool)
+		go func() {
+			unlockSecond, errSecond := lockConfig(fs, "wait.json")
+			assert.NoError(t, errSecond)
+			unlockSecond()
+			acquired <- true
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("the lock should not be acquired twice")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		unlock()
+		<-acquired
+		exists, _ = afero.Exists(fs, "wait.json.lock")
+		assert.False(t, exists)
+	})
+
+	t.Run("it should remove a stale lock", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "stale.json.lock", []byte("1\n"), 0664))
+		staleTime := time.Now().Add(-2 * utility.StaleLockAge)
+		assert.NoError(t, fs.Chtimes("stale.json.lock", staleTime, staleTime))
+		unlock, errLock := lockConfig(fs, "stale.json")
+		assert.NoError(t, errLock)
+		unlock()
+	})
+
+}
+
+func TestConcurrentWrites(t *testing.T) {
+
+	for _, fileName := range []string{TestFileConfigEntries, TestFileV2, TestFileRealWorldYaml} {
+		t.Run(fmt.Sprintf("it should not lose updates of concurrent writers to %s", fileName), func(t *testing.T) {
+
+			configPath := createSharedConfig(t, fileName)
+
+			// all repositories are parsed before the first write, so every writer has to merge
+			var repos []*Repository
+			for i := 0; i < 10; i++ {
+				repos = append(repos, parseSharedConfig(t, configPath))
+			}
+
+			var wg sync.WaitGroup
+			for i, repo := range repos {
+				wg.Add(1)
+				go func(i int, repo *Repository) {
+					defer wg.Done()
+					assert.NoError(t, repo.GetConfigWriter().SetConfig("default", fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i), false))
+				}(i, repo)
+			}
+			wg.Wait()
+
+			written := parseSharedConfig(t, configPath)
+			for i := range repos {
+				assert.Equal(t, fmt.Sprintf("value%d", i), written.GetCurrentConfig(fmt.Sprintf("key%d", i)).Value)
+			}
+			assert.Equal(t, "database.svc.local", written.GetCurrentConfig("databaseHost").Value)
+
+			exists, _ := afero.Exists(afero.NewOsFs(), utility.LockPath(configPath))
+			assert.False(t, exists)
+
+		})
+	}
+
+	t.Run("it should not lose updates of concurrent processes", func(t *testing.T) {
+
+		configPath := createSharedConfig(t, TestFileConfigEntries)
+
+		var commands []*exec.Cmd
+		for i := 0; i < 8; i++ {
+			command := exec.Command(os.Args[0], "-test.run=TestConcurrentWritesHelperProcess")
+			command.Env = append(os.Environ(), envHelperConfigPath+"="+configPath, fmt.Sprintf("%s=process%d", envHelperConfigKey, i))
+			assert.NoError(t, command.Start())
+			commands = append(commands, command)
+		}
+		for _, command := range commands {
+			assert.NoError(t, command.Wait())
+		}
+
+		written := parseSharedConfig(t, configPath)
+		for i := range commands {
+			assert.NotNil(t, written.GetCurrentConfig(fmt.Sprintf("process%d", i)))
+		}
+
+	})
+
+	t.Run("it should fail on conflicting changes", func(t *testing.T) {
+
+		configPath := createSharedConfig(t, TestFileConfigEntries)
+		first := parseSharedConfig(t, configPath)
+		second := parseSharedConfig(t, configPath)
+
+		assert.NoError(t, first.GetConfigWriter().SetConfig("default", "databasePort", "3307", true))
+		errWrite := second.GetConfigWriter().SetConfig("default", "databasePort", "3308", true)
+		assert.EqualError(t, errWrite, fmt.Sprintf("the config file %s has been changed by another process: conflicting changes at context.default.configs.databasePort", configPath))
+
+		assert.Equal(t, "3307", parseSharedConfig(t, configPath).GetCurrentConfig("databasePort").Value)
+
+		// the conflicting change is discarded, so the next write of the second writer succeeds
+		assert.NoError(t, second.GetConfigWriter().SetConfig("default", "databaseName", "app", false))
+		assert.Equal(t, "3307", parseSharedConfig(t, configPath).GetCurrentConfig("databasePort").Value)
+		assert.Equal(t, "app", parseSharedConfig(t, configPath).GetCurrentConfig("databaseName").Value)
+
+	})
+
+}
+
+// TestConcurrentWritesHelperProcess writes a config entry when started as helper process by TestConcurrentWrites
+func TestConcurrentWritesHelperProcess(t *testing.T) {
+	configPath := os.Getenv(envHelperConfigPath)
+	if configPath == "" {
+		t.Skip("only used as helper process")
+	}
+	repo := parseSharedConfig(t, configPath)
+	key := os.Getenv(envHelperConfigKey)
+	assert.NoError(t, repo.GetConfigWriter().SetConfig("default", key, key, false))
+}
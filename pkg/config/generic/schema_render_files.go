@@ -0,0 +1,110 @@
+package config_generic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// removeFileToRender removes the file from the render target, the target is removed if nothing is left to render
+func removeFileToRender(renderFiles map[string]*V1RenderTarget, targetName string, fileOut string) error {
+
+	renderTarget := renderFiles[targetName]
+	if renderTarget == nil {
+		return fmt.Errorf("the render target %s does not exist", targetName)
+	}
+
+	var remainingFiles []*V1RenderTargetFileEntry
+	for _, fileToRender := range renderTarget.Files {
+		if fileToRender.FileOut != fileOut {
+			remainingFiles = append(remainingFiles, fileToRender)
+		}
+	}
+
+	if len(remainingFiles) == len(renderTarget.Files) {
+		return fmt.Errorf("the file %s is not rendered by target %s", fileOut, targetName)
+	}
+
+	renderTarget.Files = remainingFiles
+
+	if len(renderTarget.Files) == 0 && renderTarget.Kubernetes == nil {
+		delete(renderFiles, targetName)
+	}
+
+	return nil
+
+}
+
+// removeKubernetesKey removes the secret or config from the key filters of the kubernetes render targets
+// an empty filter exports all entries, so nothing is changed if the last key of a filter would be removed
+func removeKubernetesKey(renderFiles map[string]*V1RenderTarget, key string, isSecret bool) error {
+
+	remainingKeys := make(map[string][]string)
+
+	for _, targetName := range sortedRenderTargetNames(renderFiles) {
+
+		keys := kubernetesKeys(renderFiles[targetName], isSecret)
+		if keys == nil {
+			continue
+		}
+
+		var remaining []string
+		for _, filteredKey := range *keys {
+			if filteredKey != key {
+				remaining = append(remaining, filteredKey)
+			}
+		}
+
+		if len(remaining) == len(*keys) {
+			continue
+		}
+
+		if len(remaining) == 0 {
+			return fmt.Errorf("%s is the only key exported by the kubernetes manifest of target %s, change the target first", key, targetName)
+		}
+
+		remainingKeys[targetName] = remaining
+
+	}
+
+	for targetName, remaining := range remainingKeys {
+		*kubernetesKeys(renderFiles[targetName], isSecret) = remaining
+	}
+
+	return nil
+
+}
+
+// renameKubernetesKey renames the secret or config in the key filters of the kubernetes render targets
+func renameKubernetesKey(renderFiles map[string]*V1RenderTarget, oldKey string, newKey string, isSecret bool) {
+	for _, renderTarget := range renderFiles {
+		keys := kubernetesKeys(renderTarget, isSecret)
+		if keys == nil {
+			continue
+		}
+		for i, filteredKey := range *keys {
+			if filteredKey == oldKey {
+				(*keys)[i] = newKey
+			}
+		}
+	}
+}
+
+// kubernetesKeys returns the secret or config key filter of the kubernetes manifest, nil if the target has no manifest
+func kubernetesKeys(renderTarget *V1RenderTarget, isSecret bool) *[]string {
+	if renderTarget.Kubernetes == nil {
+		return nil
+	}
+	if isSecret {
+		return &renderTarget.Kubernetes.SecretKeys
+	}
+	return &renderTarget.Kubernetes.ConfigKeys
+}
+
+func sortedRenderTargetNames(renderFiles map[string]*V1RenderTarget) []string {
+	var names []string
+	for targetName := range renderFiles {
+		names = append(names, targetName)
+	}
+	sort.Strings(names)
+	return names
+}
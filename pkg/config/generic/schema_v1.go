@@ -3,7 +3,6 @@ package config_generic
 import (
 	"encoding/json"
 	"fmt"
-	config_const "github.com/benammann/git-secrets/pkg/config/const"
 	global_config "github.com/benammann/git-secrets/pkg/config/global"
 	"github.com/benammann/git-secrets/pkg/encryption"
 	"github.com/benammann/git-secrets/schema"
@@ -11,21 +10,54 @@ import (
 	"github.com/xeipuuv/gojsonschema"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
 type V1Schema struct {
 	Schema      string                     `json:"$schema,omitempty"`
 	Version     int                        `json:"version"`
+	Include     []string                   `json:"include,omitempty"`
 	Context     V1Context                  `json:"context"`
 	RenderFiles map[string]*V1RenderTarget `json:"renderFiles,omitempty"`
+	Exec        *V1Exec                    `json:"exec,omitempty"`
 }
 
 type V1DecryptSecret struct {
-	FromName string `json:"fromName,omitempty"`
-	FromEnv  string `json:"fromEnv,omitempty"`
+	FromName       string `json:"fromName,omitempty"`
+	FromEnv        string `json:"fromEnv,omitempty"`
+	FromFile       string `json:"fromFile,omitempty"`
+	FromCommand    string `json:"fromCommand,omitempty"`
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
+}
+
+// methods returns the names of the configured decryption methods
+func (d *V1DecryptSecret) methods() (res []string) {
+	for name, value := range map[string]string{"fromName": d.FromName, "fromEnv": d.FromEnv, "fromFile": d.FromFile, "fromCommand": d.FromCommand} {
+		if value != "" {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// validateDecryptSecret checks that a context uses at most one decryption method, the default context needs exactly one
+func validateDecryptSecret(contextKey string, decryptSecret *V1DecryptSecret) error {
+	if decryptSecret == nil {
+		return nil
+	}
+	methods := decryptSecret.methods()
+	if len(methods) > 1 {
+		return fmt.Errorf("context: %s: you can only use either one decryptSecret method (fromName, fromEnv, fromFile or fromCommand) but %s are set", contextKey, strings.Join(methods, " and "))
+	}
+	if len(methods) == 0 && contextKey == "default" {
+		return fmt.Errorf("context: %s: you must specify at least one decryption method", contextKey)
+	}
+	return nil
 }
 
 type V1ContextAwareSecrets struct {
+	Extends       string            `json:"extends,omitempty"`
 	DecryptSecret *V1DecryptSecret  `json:"decryptSecret,omitempty"`
 	Secrets       map[string]string `json:"secrets,omitempty"`
 	Configs       map[string]string `json:"configs,omitempty"`
@@ -33,13 +65,33 @@ type V1ContextAwareSecrets struct {
 
 type V1Context map[string]*V1ContextAwareSecrets
 
+type V1Exec struct {
+	Prefix string            `json:"prefix,omitempty"`
+	Case   string            `json:"case,omitempty"`
+	Env    map[string]string `json:"env,omitempty"`
+}
+
 type V1RenderTargetFileEntry struct {
 	FileIn  string `json:"fileIn"`
 	FileOut string `json:"fileOut"`
 }
 
 type V1RenderTarget struct {
-	Files []*V1RenderTargetFileEntry `json:"files"`
+	Files      []*V1RenderTargetFileEntry `json:"files,omitempty"`
+	Partials   []string                   `json:"partials,omitempty"`
+	Kubernetes *V1KubernetesTarget        `json:"kubernetes,omitempty"`
+}
+
+type V1KubernetesTarget struct {
+	FileOut     string            `json:"fileOut"`
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Kinds       []string          `json:"kinds,omitempty"`
+	StringData  bool              `json:"stringData,omitempty"`
+	SecretKeys  []string          `json:"secretKeys,omitempty"`
+	ConfigKeys  []string          `json:"configKeys,omitempty"`
 }
 
 var jsonLoaderV1 gojsonschema.JSONLoader
@@ -52,6 +104,61 @@ func IsSchemaV1(version int) bool {
 	return !(version < 1 || version > 1)
 }
 
+// contextExtends returns the extended context of each context, empty if the context does not extend
+func (s *V1Schema) contextExtends() map[string]string {
+	extends := make(map[string]string)
+	for contextKey, contextValue := range s.Context {
+		extends[contextKey] = contextValue.Extends
+	}
+	return extends
+}
+
+func (s *V1Schema) renderTargets() map[string]*V1RenderTarget {
+	return s.RenderFiles
+}
+
+func (s *V1Schema) hasContext(contextName string) bool {
+	return s.Context[contextName] != nil
+}
+
+func (s *V1Schema) moveContext(oldName string, newName string) {
+	s.Context[newName] = s.Context[oldName]
+	delete(s.Context, oldName)
+}
+
+func (s *V1Schema) deleteContext(contextName string) {
+	delete(s.Context, contextName)
+}
+
+func (s *V1Schema) setExtends(contextName string, extends string) {
+	s.Context[contextName].Extends = extends
+}
+
+// entries returns the secrets or the configs of the context
+func (s *V1Schema) entries(contextName string, isSecret bool) map[string]string {
+	if isSecret {
+		return s.Context[contextName].Secrets
+	}
+	return s.Context[contextName].Configs
+}
+
+func (s *V1Schema) definesEntry(contextName string, key string, isSecret bool) bool {
+	_, exists := s.entries(contextName, isSecret)[key]
+	return exists
+}
+
+func (s *V1Schema) moveEntry(contextName string, oldKey string, newKey string, isSecret bool) {
+	entries := s.entries(contextName, isSecret)
+	if value, exists := entries[oldKey]; exists {
+		entries[newKey] = value
+		delete(entries, oldKey)
+	}
+}
+
+func (s *V1Schema) deleteEntry(contextName string, key string, isSecret bool) {
+	delete(s.entries(contextName, isSecret), key)
+}
+
 func (s *V1Schema) validateSchemaV1() error {
 
 	if !IsSchemaV1(s.Version) {
@@ -65,20 +172,18 @@ func (s *V1Schema) validateSchemaV1() error {
 
 	// check for only one or none decryptSecret method
 	for contextKey, contextValue := range s.Context {
-		if contextValue.DecryptSecret == nil {
-			continue
-		}
-		if contextValue.DecryptSecret.FromEnv != "" && contextValue.DecryptSecret.FromName != "" {
-			return fmt.Errorf("context: %s: you can only use either one decryptSecret method (FromEnv or FromName)", contextKey)
-		}
-		if contextValue.DecryptSecret.FromEnv == "" && contextValue.DecryptSecret.FromName == "" && contextKey == "default" {
-			return fmt.Errorf("context: %s: you must specify at least one decryption method", contextKey)
+		if errDecryptSecret := validateDecryptSecret(contextKey, contextValue.DecryptSecret); errDecryptSecret != nil {
+			return errDecryptSecret
 		}
 	}
 
-	defaultContext := s.Context["default"]
+	extends := s.contextExtends()
 
-	// check if secret keys exists in default context
+	if errExtends := validateExtends(extends); errExtends != nil {
+		return errExtends
+	}
+
+	// check if secret keys exists in a parent context
 	for contextKey, contextValue := range s.Context {
 
 		// skip default context
@@ -86,19 +191,17 @@ func (s *V1Schema) validateSchemaV1() error {
 			continue
 		}
 
-		if contextValue.Secrets != nil {
-			for secretKey, _ := range contextValue.Secrets {
-				if defaultContext.Secrets[secretKey] == "" {
-					return fmt.Errorf("secret %s exists in context %s but not in default context", secretKey, contextKey)
-				}
+		ancestors := contextAncestors(extends, contextKey)
+
+		for secretKey := range contextValue.Secrets {
+			if !s.ancestorsDefine(ancestors, secretKey, true) {
+				return fmt.Errorf("secret %s exists in context %s but not in its parent contexts %s", secretKey, contextKey, strings.Join(ancestors, ", "))
 			}
 		}
 
-		if contextValue.Configs != nil {
-			for configKey, _ := range contextValue.Configs {
-				if defaultContext.Configs[configKey] == "" {
-					return fmt.Errorf("config entry %s exists in context %s but not in default context", configKey, contextKey)
-				}
+		for configKey := range contextValue.Configs {
+			if !s.ancestorsDefine(ancestors, configKey, false) {
+				return fmt.Errorf("config entry %s exists in context %s but not in its parent contexts %s", configKey, contextKey, strings.Join(ancestors, ", "))
 			}
 		}
 
@@ -108,8 +211,33 @@ func (s *V1Schema) validateSchemaV1() error {
 
 }
 
+// ancestorsDefine returns true if one of the ancestors defines the secret or the config entry
+func (s *V1Schema) ancestorsDefine(ancestors []string, key string, isSecret bool) bool {
+	for _, ancestor := range ancestors {
+		if isSecret && s.Context[ancestor].Secrets[key] != "" {
+			return true
+		}
+		if !isSecret && s.Context[ancestor].Configs[key] != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func ParseSchemaV1(jsonInput []byte, configFileUsed string, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string) (*Repository, error) {
 
+	definition, errDefinition := parseDefinitionV1(jsonInput, configFileUsed)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	return buildRepository(definition, globalConfig, overwrittenSecrets)
+
+}
+
+// parseDefinitionV1 validates and parses the json input into a repository definition
+func parseDefinitionV1(jsonInput []byte, configFileUsed string) (*repositoryDefinition, error) {
+
 	jsonContentLoader := gojsonschema.NewStringLoader(string(jsonInput))
 	res, errValidate := gojsonschema.Validate(jsonLoaderV1, jsonContentLoader)
 	if errValidate != nil {
@@ -133,120 +261,61 @@ func ParseSchemaV1(jsonInput []byte, configFileUsed string, globalConfig *global
 		return nil, fmt.Errorf("validation error: %s", errValidate.Error())
 	}
 
-	// all resulting contexts
-	var contexts []*Context
-
-	// locally store the default context
-	var defaultContext *Context
-
-	// all render targets to add
-	var renderTargets []*RenderTarget
-
-	// first, initialize all contexts
+	contexts := make(map[string]*contextDefinition)
 	for contextKey, contextValue := range Parsed.Context {
-		localContext := &Context{
-			Name:             contextKey,
-			EncryptedSecrets: contextValue.Secrets,
-			Configs:          contextValue.Configs,
-		}
-		// reference the default context
-		if localContext.Name == config_const.DefaultContextName {
-			defaultContext = localContext
-		}
-		contexts = append(contexts, localContext)
-	}
-
-	// important, always default first since some logics depend on a fully defined default context
-	sort.SliceStable(contexts, func(i, j int) bool {
-		return contexts[i].Name == config_const.DefaultContextName
-	})
-
-	for _, context := range contexts {
-		context.SecretResolver = getSecretResolverV1(Parsed.Context[context.Name].DecryptSecret, defaultContext, globalConfig, overwrittenSecrets)
-		context.Encryption = encryption.NewAesEngine(context.SecretResolver)
-	}
-
-	if Parsed.RenderFiles != nil {
-		for targetName, renderTarget := range Parsed.RenderFiles {
-			if renderTarget.Files != nil {
-				finalRenderTarget := NewRenderTarget(targetName)
-				for _, fileToRender := range renderTarget.Files {
-					configDir := filepath.Dir(configFileUsed)
-					fileIn := filepath.Join(configDir, fileToRender.FileIn)
-					fileOut := filepath.Join(configDir, fileToRender.FileOut)
-					errAddFile := finalRenderTarget.AddFileToRender(fileIn, fileOut)
-					if errAddFile != nil {
-						return nil, fmt.Errorf("could not add file (%s -> %s) to target %s: %s", fileToRender.FileIn, fileToRender.FileOut, finalRenderTarget.Name, errAddFile.Error())
-					}
-				}
-				renderTargets = append(renderTargets, finalRenderTarget)
-			}
+		contexts[contextKey] = &contextDefinition{
+			extends:             contextValue.Extends,
+			decryptSecret:       contextValue.DecryptSecret,
+			decryptSecretSource: configFileUsed,
+			secrets:             plainEntries(contextValue.Secrets, configFileUsed),
+			configs:             plainEntries(contextValue.Configs, configFileUsed),
 		}
 	}
 
-	var secrets []*Secret
+	return &repositoryDefinition{
+		version:        1,
+		configFileUsed: configFileUsed,
+		configWriter:   NewV1Writer(afero.NewOsFs(), Parsed, configFileUsed),
+		include:        Parsed.Include,
+		contexts:       contexts,
+		renderFiles:    renderTargetDefinitions(Parsed.RenderFiles, configFileUsed),
+		exec:           Parsed.Exec,
+	}, nil
 
-	for _, context := range contexts {
-		for secretKey, encryptedSecret := range context.EncryptedSecrets {
-			secrets = append(secrets, &Secret{
-				Name:          secretKey,
-				OriginContext: context,
-				EncodedValue:  encryptedSecret,
-			})
-		}
-	}
-
-	var configs []*Config
-	for _, context := range contexts {
-		for configKey, configValue := range context.Configs {
-			configs = append(configs, &Config{
-				Name:          configKey,
-				Value:         configValue,
-				OriginContext: context,
-			})
-		}
-	}
-
-	configWriter := NewV1Writer(afero.NewOsFs(), Parsed, configFileUsed)
-	repository := NewRepository(1, configFileUsed, configWriter)
-
-	for _, resultingContext := range contexts {
-		errAddContext := repository.AddContext(resultingContext)
-		if errAddContext != nil {
-			return nil, fmt.Errorf("could not add context to repository: %s", errAddContext.Error())
-		}
-	}
-
-	for _, secretOut := range secrets {
-		errAddSecret := repository.AddSecret(secretOut)
-		if errAddSecret != nil {
-			return nil, fmt.Errorf("could not add secret to repository: %s", errAddSecret.Error())
-		}
-	}
-
-	for _, configOut := range configs {
-		errAddConfig := repository.AddConfig(configOut)
-		if errAddConfig != nil {
-			return nil, fmt.Errorf("could not add config to repository: %s", errAddConfig.Error())
-		}
-	}
+}
 
-	for _, renderTargetOut := range renderTargets {
-		if errAddTarget := repository.AddRenderTarget(renderTargetOut); errAddTarget != nil {
-			return nil, fmt.Errorf("could not add render target: %s", errAddTarget.Error())
-		}
+// plainEntries converts plain v1 values to entries without metadata
+func plainEntries(values map[string]string, configFileUsed string) map[string]*entryDefinition {
+	entries := make(map[string]*entryDefinition)
+	for key, value := range values {
+		entries[key] = &entryDefinition{value: value, source: configFileUsed}
 	}
-
-	return repository, nil
-
+	return entries
 }
 
-func getSecretResolverV1(val *V1DecryptSecret, defaultContext *Context, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string) encryption.SecretResolver {
-	if val != nil && val.FromEnv != "" {
-		return encryption.NewEnvSecretResolver(val.FromEnv)
+// getSecretResolverV1 returns the secret resolver of the context, it is verified against the key fingerprint if one is pinned
+// a context setting only a key fingerprint pins the secret resolver inherited from its parent
+// source is the config file defining the decryptSecret, relative paths of fromFile are resolved from its directory and fromCommand must be trusted for it
+func getSecretResolverV1(val *V1DecryptSecret, parentContext *Context, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string, source string) encryption.SecretResolver {
+	var secretResolver encryption.SecretResolver
+	switch {
+	case val != nil && val.FromEnv != "":
+		secretResolver = encryption.NewEnvSecretResolver(val.FromEnv)
+	case val != nil && val.FromName != "":
+		secretResolver = encryption.NewMergedSecretResolver(val.FromName, globalConfig, overwrittenSecrets)
+	case val != nil && val.FromFile != "":
+		secretResolver = encryption.NewFileSecretResolver(val.FromFile, filepath.Dir(source))
+	case val != nil && val.FromCommand != "":
+		secretResolver = encryption.NewCommandSecretResolver(val.FromCommand, source, globalConfig, encryption.DefaultCommandTimeout)
+	default:
+		secretResolver = parentContext.SecretResolver
+		if pinned, isPinned := secretResolver.(*encryption.PinnedSecretResolver); isPinned && val != nil && val.KeyFingerprint != "" {
+			// the fingerprint of the context replaces the one of its parent
+			secretResolver = pinned.Unpinned()
+		}
 	}
-	if val != nil && val.FromName != "" {
-		return encryption.NewMergedSecretResolver(val.FromName, globalConfig, overwrittenSecrets)
+	if val != nil && val.KeyFingerprint != "" {
+		return encryption.NewPinnedSecretResolver(secretResolver, val.KeyFingerprint)
 	}
-	return defaultContext.SecretResolver
+	return secretResolver
 }
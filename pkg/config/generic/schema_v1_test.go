@@ -7,6 +7,7 @@ import (
 	"github.com/benammann/git-secrets/pkg/encryption"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"path/filepath"
 	"testing"
 )
 
@@ -68,6 +69,23 @@ func TestV1Schema_validateSchemaV1(t *testing.T) {
 		assert.NotEqual(t, "", parsed.Context["prod"].Configs["test"])
 		assert.Error(t, parsed.validateSchemaV1())
 	})
+	t.Run("fail on cyclic extends", func(t *testing.T) {
+		parsed := ParseAsSchemaV1(t, "extends-cycle.json")
+		assert.Error(t, parsed.validateSchemaV1())
+	})
+	t.Run("fail if the extended context does not exist", func(t *testing.T) {
+		parsed := ParseAsSchemaV1(t, "extends-missing.json")
+		assert.Error(t, parsed.validateSchemaV1())
+	})
+	t.Run("fail if a key is only defined in a sibling context", func(t *testing.T) {
+		parsed := ParseAsSchemaV1(t, "extends-sibling-key.json")
+		assert.Error(t, parsed.validateSchemaV1())
+	})
+	t.Run("fail if the default context extends another context", func(t *testing.T) {
+		parsed := ParseAsSchemaV1(t, "real-world.json")
+		parsed.Context["default"].Extends = "prod"
+		assert.Error(t, parsed.validateSchemaV1())
+	})
 	t.Run("do not fail if schema is valid", func(t *testing.T) {
 		parsed := ParseAsSchemaV1(t, "real-world.json")
 		assert.NoError(t, parsed.validateSchemaV1())
@@ -85,7 +103,7 @@ func Test_getSecretResolverV1(t *testing.T) {
 		defaultCtx := repo.GetContext("default")
 		assert.NotNil(t, defaultCtx)
 		assert.NotNil(t, defaultCtx.SecretResolver)
-		assert.Equal(t, defaultCtx.SecretResolver, getSecretResolverV1(nil, defaultCtx, globalConfig, mergeGlobalSecrets))
+		assert.Equal(t, defaultCtx.SecretResolver, getSecretResolverV1(nil, defaultCtx, globalConfig, mergeGlobalSecrets, ""))
 	})
 	t.Run("return from env secret resolver", func(t *testing.T) {
 		repo := initRepository(t, TestFileBlankDefaultFromEnv, "default")
@@ -101,4 +119,66 @@ func Test_getSecretResolverV1(t *testing.T) {
 		assert.NotNil(t, defaultCtx.SecretResolver)
 		assert.IsType(t, &encryption.MergedSecretResolver{}, defaultCtx.SecretResolver)
 	})
+	t.Run("return from file and from command secret resolvers", func(t *testing.T) {
+		fileResolver := getSecretResolverV1(&V1DecryptSecret{FromFile: "keys/prod.key"}, nil, globalConfig, mergeGlobalSecrets, "/project/.git-secrets.json")
+		assert.IsType(t, &encryption.FromFileSecretResolver{}, fileResolver)
+		path, _ := fileResolver.(*encryption.FromFileSecretResolver).Path()
+		assert.Equal(t, filepath.Join("/project", "keys", "prod.key"), path)
+		commandResolver := getSecretResolverV1(&V1DecryptSecret{FromCommand: "pass show git-secrets"}, nil, globalConfig, mergeGlobalSecrets, "/project/.git-secrets.json")
+		assert.IsType(t, &encryption.FromCommandSecretResolver{}, commandResolver)
+		assert.Equal(t, "/project/.git-secrets.json", commandResolver.(*encryption.FromCommandSecretResolver).File())
+		_, errUntrusted := commandResolver.GetPlainSecret()
+		assert.ErrorIs(t, errUntrusted, encryption.ErrUntrustedCommand)
+		assert.IsType(t, &encryption.PinnedSecretResolver{}, getSecretResolverV1(&V1DecryptSecret{FromCommand: "pass show git-secrets", KeyFingerprint: "dd42c1f78b389d88"}, nil, globalConfig, mergeGlobalSecrets, ""))
+	})
+	t.Run("return pinned secret resolver if a key fingerprint is set", func(t *testing.T) {
+		repo := initRepository(t, TestFileKeyFingerprint, "default")
+		defaultCtx := repo.GetContext("default")
+		assert.IsType(t, &encryption.PinnedSecretResolver{}, defaultCtx.SecretResolver)
+		fingerprint, errFingerprint := defaultCtx.KeyFingerprint()
+		assert.NoError(t, errFingerprint)
+		assert.Equal(t, "dd42c1f78b389d88", fingerprint)
+		assert.Equal(t, "dd42c1f78b389d88", defaultCtx.PinnedKeyFingerprint())
+
+		prodFingerprint, errProdFingerprint := repo.GetContext("prod").KeyFingerprint()
+		assert.NoError(t, errProdFingerprint)
+		assert.Equal(t, "dd42c1f78b389d88", prodFingerprint)
+		assert.Equal(t, "0123456789abcdef", repo.GetContext("prod").PinnedKeyFingerprint())
+		assert.Equal(t, "", initRepository(t, TestFileBlankDefault, "default").GetDefault().PinnedKeyFingerprint())
+
+		_, errDecode := repo.GetCurrentSecret("databasePassword").Decode()
+		assert.NoError(t, errDecode)
+
+		_, errSelect := repo.SetSelectedContext("staging")
+		assert.NoError(t, errSelect)
+		_, errDecode = repo.GetCurrentSecret("databasePassword").Decode()
+		assert.NoError(t, errDecode)
+
+		_, errSelect = repo.SetSelectedContext("prod")
+		assert.NoError(t, errSelect)
+		_, errDecode = repo.GetCurrentSecret("databasePassword").Decode()
+		assert.ErrorIs(t, errDecode, encryption.ErrKeyMismatch)
+		assert.EqualError(t, errDecode, "key mismatch: the secret has the fingerprint dd42c1f78b389d88 but the context expects 0123456789abcdef")
+	})
+	t.Run("pin the inherited secret resolver if only a key fingerprint is set", func(t *testing.T) {
+		repo := initRepository(t, TestFileKeyFingerprint, "default")
+		inherited := getSecretResolverV1(&V1DecryptSecret{KeyFingerprint: "0123456789abcdef"}, repo.GetDefault(), globalConfig, mergeGlobalSecrets, "")
+		assert.IsType(t, &encryption.PinnedSecretResolver{}, inherited)
+		assert.Equal(t, "0123456789abcdef", inherited.(*encryption.PinnedSecretResolver).Pinned())
+		_, errSecret := inherited.GetPlainSecret()
+		assert.ErrorIs(t, errSecret, encryption.ErrKeyMismatch)
+
+		unpinned := initRepository(t, TestFileBlankDefault, "default").GetDefault()
+		pinned := getSecretResolverV1(&V1DecryptSecret{KeyFingerprint: "dd42c1f78b389d88"}, unpinned, globalConfig, mergeGlobalSecrets, "")
+		assert.IsType(t, &encryption.PinnedSecretResolver{}, pinned)
+		_, errSecret = pinned.GetPlainSecret()
+		assert.NoError(t, errSecret)
+	})
+}
+
+func Test_validateDecryptSecret(t *testing.T) {
+	assert.NoError(t, validateDecryptSecret("default", &V1DecryptSecret{FromFile: "~/.keys/git-secrets"}))
+	assert.NoError(t, validateDecryptSecret("prod", nil))
+	assert.EqualError(t, validateDecryptSecret("prod", &V1DecryptSecret{FromFile: "key.txt", FromCommand: "pass show key"}), "context: prod: you can only use either one decryptSecret method (fromName, fromEnv, fromFile or fromCommand) but fromCommand and fromFile are set")
+	assert.EqualError(t, validateDecryptSecret("default", &V1DecryptSecret{}), "context: default: you must specify at least one decryption method")
 }
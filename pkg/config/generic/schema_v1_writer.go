@@ -1,37 +1,59 @@
 package config_generic
 
 import (
-	"encoding/json"
 	"fmt"
 	config_const "github.com/benammann/git-secrets/pkg/config/const"
 	"github.com/spf13/afero"
-	"os"
+	"sort"
 )
 
 type V1Writer struct {
-	schema     V1Schema
-	configPath string
-	fs         afero.Fs
+	documentWriter[V1Schema]
 }
 
 func NewV1Writer(fs afero.Fs, schema V1Schema, configPath string) *V1Writer {
-	return &V1Writer{
-		fs:         fs,
-		schema:     schema,
-		configPath: configPath,
-	}
+	writer := &V1Writer{
+		documentWriter: newDocumentWriter(fs, schema, configPath, schemaHooks[V1Schema]{
+			validate: validateWrittenSchemaV1,
+			parse:    parseDefinitionV1,
+		}),
+	}
+	writer.owner = writer
+	return writer
 }
 
 func (v *V1Writer) SetSecret(contextName string, secretName string, secretEncodedValue string, force bool) error {
+	return v.SetSecrets(contextName, map[string]string{secretName: secretEncodedValue}, force)
+}
+
+// SetSecrets sets multiple secrets at once and writes the config a single time
+// nothing is changed if one of the secrets can not be set
+func (v *V1Writer) SetSecrets(contextName string, secrets map[string]string, force bool) error {
 
 	if v.schema.Context[contextName] == nil {
 		return fmt.Errorf("the context %s does not exist", contextName)
 	}
 
+	for _, secretName := range sortedMapKeys(secrets) {
+		if err := v.canSetSecret(contextName, secretName, force); err != nil {
+			return err
+		}
+	}
+
 	if v.schema.Context[contextName].Secrets == nil {
 		v.schema.Context[contextName].Secrets = make(map[string]string)
 	}
 
+	for secretName, secretEncodedValue := range secrets {
+		v.schema.Context[contextName].Secrets[secretName] = secretEncodedValue
+	}
+
+	return v.write()
+
+}
+
+func (v *V1Writer) canSetSecret(contextName string, secretName string, force bool) error {
+
 	if contextName != config_const.DefaultContextName && v.schema.Context[config_const.DefaultContextName].Secrets[secretName] == "" {
 		return fmt.Errorf("you need to define secret entry %s in the default context first", secretName)
 	}
@@ -40,22 +62,42 @@ func (v *V1Writer) SetSecret(contextName string, secretName string, secretEncode
 		return fmt.Errorf("the secret %s does already exist. Use --force to overwrite", secretName)
 	}
 
-	v.schema.Context[contextName].Secrets[secretName] = secretEncodedValue
-
-	return v.WriteConfig()
+	return nil
 
 }
 
 func (v *V1Writer) SetConfig(contextName string, configName string, configValue string, force bool) error {
+	return v.SetConfigs(contextName, map[string]string{configName: configValue}, force)
+}
+
+// SetConfigs sets multiple config entries at once and writes the config a single time
+// nothing is changed if one of the entries can not be set
+func (v *V1Writer) SetConfigs(contextName string, configs map[string]string, force bool) error {
 
 	if v.schema.Context[contextName] == nil {
 		return fmt.Errorf("the context %s does not exist. Use git-secrets add context <contextName> to add a context", contextName)
 	}
 
+	for _, configName := range sortedMapKeys(configs) {
+		if err := v.canSetConfig(contextName, configName, force); err != nil {
+			return err
+		}
+	}
+
 	if v.schema.Context[contextName].Configs == nil {
 		v.schema.Context[contextName].Configs = make(map[string]string)
 	}
 
+	for configName, configValue := range configs {
+		v.schema.Context[contextName].Configs[configName] = configValue
+	}
+
+	return v.write()
+
+}
+
+func (v *V1Writer) canSetConfig(contextName string, configName string, force bool) error {
+
 	if contextName != config_const.DefaultContextName && v.schema.Context[config_const.DefaultContextName].Configs[configName] == "" {
 		return fmt.Errorf("you need to define config entry %s in the default context first", configName)
 	}
@@ -64,9 +106,7 @@ func (v *V1Writer) SetConfig(contextName string, configName string, configValue
 		return fmt.Errorf("the config entry %s does already exist. Use --force to overwrite", configName)
 	}
 
-	v.schema.Context[contextName].Configs[configName] = configValue
-
-	return v.WriteConfig()
+	return nil
 
 }
 
@@ -81,7 +121,7 @@ func (v *V1Writer) AddContext(contextName string) error {
 		Configs: make(map[string]string),
 	}
 
-	return v.WriteConfig()
+	return v.write()
 
 }
 
@@ -115,36 +155,94 @@ func (v *V1Writer) AddFileToRender(targetName string, fileIn string, fileOut str
 		FileOut: fileOut,
 	})
 
-	return v.WriteConfig()
+	return v.write()
 
 }
 
-func (v *V1Writer) WriteConfig() error {
+// RemoveSecret removes the secret from the context
+// if the secret is removed from the context defining it, it is also removed from all child contexts
+func (v *V1Writer) RemoveSecret(contextName string, secretName string) error {
+	return v.removeEntry(contextName, secretName, true)
+}
 
-	for contextName, context := range v.schema.Context {
-		if context.Secrets == nil && contextName == config_const.DefaultContextName {
-			context.Secrets = make(map[string]string)
-		}
+// RemoveConfig removes the config entry from the context
+// if the entry is removed from the context defining it, it is also removed from all child contexts
+func (v *V1Writer) RemoveConfig(contextName string, configName string) error {
+	return v.removeEntry(contextName, configName, false)
+}
+
+func (v *V1Writer) removeEntry(contextName string, key string, isSecret bool) error {
+	if errRemove := removeSchemaEntry(&v.schema, contextName, key, isSecret); errRemove != nil {
+		return errRemove
 	}
+	return v.write()
+}
+
+// RenameSecret renames the secret in all contexts and render targets
+func (v *V1Writer) RenameSecret(oldName string, newName string) error {
+	return v.renameEntry(oldName, newName, true)
+}
 
-	if errValidate := v.schema.validateSchemaV1(); errValidate != nil {
-		return fmt.Errorf("not writing config since it is not valid: %s", errValidate.Error())
+// RenameConfig renames the config entry in all contexts and render targets
+func (v *V1Writer) RenameConfig(oldName string, newName string) error {
+	return v.renameEntry(oldName, newName, false)
+}
+
+func (v *V1Writer) renameEntry(oldName string, newName string, isSecret bool) error {
+	if errRename := renameSchemaEntry(&v.schema, oldName, newName, isSecret); errRename != nil {
+		return errRename
 	}
+	return v.write()
+}
 
-	newConfig, _ := json.MarshalIndent(v.schema, "", "  ")
+// RemoveContext removes the context and all contexts extending it
+func (v *V1Writer) RemoveContext(contextName string) error {
+	if errRemove := removeSchemaContext(&v.schema, contextName); errRemove != nil {
+		return errRemove
+	}
+	return v.write()
+}
 
-	f, err := v.fs.OpenFile(v.configPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0664)
-	if err != nil {
-		return fmt.Errorf("could not open config: %s", err.Error())
+// RenameContext renames the context and updates the contexts extending it
+func (v *V1Writer) RenameContext(oldName string, newName string) error {
+	if errRename := renameSchemaContext(&v.schema, oldName, newName); errRename != nil {
+		return errRename
 	}
+	return v.write()
+}
 
-	defer f.Close()
+// RemoveFileToRender removes the file from the render target
+func (v *V1Writer) RemoveFileToRender(targetName string, fileOut string) error {
+	if errRemove := removeFileToRender(v.schema.RenderFiles, targetName, fileOut); errRemove != nil {
+		return errRemove
+	}
+	return v.write()
+}
 
-	_, errWrite := f.Write(newConfig)
-	if errWrite != nil {
-		return fmt.Errorf("could not overwrite config: %s", errWrite.Error())
+// validateWrittenSchemaV1 validates the schema before it is written, the default context always holds the secrets
+func validateWrittenSchemaV1(schema *V1Schema) error {
+	for contextName, context := range schema.Context {
+		if context.Secrets == nil && contextName == config_const.DefaultContextName {
+			context.Secrets = make(map[string]string)
+		}
 	}
+	return schema.validateSchemaV1()
+}
 
-	return nil
+// entryKind returns the name of the entry kind used in messages
+func entryKind(isSecret bool) string {
+	if isSecret {
+		return "secret"
+	}
+	return "config entry"
+}
 
+// sortedMapKeys returns the keys of the map sorted alphabetically
+func sortedMapKeys(values map[string]string) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
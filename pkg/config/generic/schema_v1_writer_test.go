@@ -176,6 +176,42 @@ func TestV1Writer_SetConfig(t *testing.T) {
 
 }
 
+func TestV1Writer_SetConfigs(t *testing.T) {
+
+	t.Run("write all config entries", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileRealWorld)
+		assert.NoError(t, writer.SetConfigs("default", map[string]string{"databaseName": "work-cli", "databaseUser": "root"}, false))
+		assert.Equal(t, "work-cli", getSchema().Context["default"].Configs["databaseName"])
+		assert.Equal(t, "root", getSchema().Context["default"].Configs["databaseUser"])
+	})
+
+	t.Run("change nothing if one entry can not be set", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileRealWorld)
+		assert.Error(t, writer.SetConfigs("default", map[string]string{"databaseName": "work-cli", "databasePort": "3307"}, false))
+		assert.Equal(t, "", getSchema().Context["default"].Configs["databaseName"])
+		assert.Equal(t, "3306", getSchema().Context["default"].Configs["databasePort"])
+	})
+
+}
+
+func TestV1Writer_SetSecrets(t *testing.T) {
+
+	t.Run("write all secrets", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileRealWorld)
+		assert.NoError(t, writer.SetSecrets("default", map[string]string{"apiKey": "<encryptedValueA>", "apiToken": "<encryptedValueB>"}, false))
+		assert.Equal(t, "<encryptedValueA>", getSchema().Context["default"].Secrets["apiKey"])
+		assert.Equal(t, "<encryptedValueB>", getSchema().Context["default"].Secrets["apiToken"])
+	})
+
+	t.Run("change nothing if one secret can not be set", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileRealWorld)
+		assert.Error(t, writer.SetSecrets("prod", map[string]string{"databasePassword": "<encryptedValueA>", "apiKey": "<encryptedValueB>"}, true))
+		assert.Equal(t, "", getSchema().Context["prod"].Secrets["apiKey"])
+		assert.NotEqual(t, "<encryptedValueA>", getSchema().Context["prod"].Secrets["databasePassword"])
+	})
+
+}
+
 func TestV1Writer_SetSecret(t *testing.T) {
 
 	t.Run("fail if context does not exists", func(t *testing.T) {
@@ -256,3 +292,208 @@ func TestV1Writer_WriteConfig(t *testing.T) {
 	})
 
 }
+
+func TestV1Writer_WriteConfigYaml(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configPath := ".git-secrets.yaml"
+	assert.NoError(t, afero.WriteFile(fs, configPath, readTestFile(t, TestFileRealWorldYaml), 0664))
+
+	writer := NewV1Writer(fs, ParseAsSchemaV1(t, "real-world.json"), configPath)
+	assert.NoError(t, writer.SetConfig("default", "databaseHost", "db.local", true))
+
+	written, errRead := afero.ReadFile(fs, configPath)
+	assert.NoError(t, errRead)
+	assert.Contains(t, string(written), "databaseHost: db.local\n")
+
+	normalized, errNormalize := NormalizeDocument(FileFormatYaml, written)
+	assert.NoError(t, errNormalize)
+	assert.NoError(t, ValidateDocument(normalized))
+}
+
+func TestV1Writer_RemoveSecret(t *testing.T) {
+
+	t.Run("remove the secret from all contexts if it is removed from the default context", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		assert.NoError(t, writer.RemoveSecret("default", "databasePassword"))
+		assert.NotContains(t, getSchema().Context["default"].Secrets, "databasePassword")
+		assert.NotContains(t, getSchema().Context["prod"].Secrets, "databasePassword")
+	})
+
+	t.Run("only remove the overwrite of a child context", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		assert.NoError(t, writer.RemoveSecret("prod", "databasePassword"))
+		assert.Contains(t, getSchema().Context["default"].Secrets, "databasePassword")
+		assert.NotContains(t, getSchema().Context["prod"].Secrets, "databasePassword")
+	})
+
+	t.Run("fail if the context does not define the secret", func(t *testing.T) {
+		writer, _, _ := NewWrappedV1Writer(t, TestFileExtends)
+		assert.Error(t, writer.RemoveSecret("prod-eu", "databasePassword"))
+		assert.Error(t, writer.RemoveSecret("staging", "databasePassword"))
+	})
+
+}
+
+func TestV1Writer_RemoveConfig(t *testing.T) {
+
+	t.Run("remove the overwrites of the child contexts", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		assert.NoError(t, writer.RemoveConfig("default", "databaseHost"))
+		for _, context := range getSchema().Context {
+			assert.NotContains(t, context.Configs, "databaseHost")
+		}
+	})
+
+	t.Run("keep the overwrites of the child contexts if a parent still defines the entry", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		assert.NoError(t, writer.RemoveConfig("prod", "databaseHost"))
+		assert.NotContains(t, getSchema().Context["prod"].Configs, "databaseHost")
+		assert.Equal(t, "database-prod.eu.svc.cluster", getSchema().Context["prod-eu"].Configs["databaseHost"])
+	})
+
+	t.Run("remove the entry from the kubernetes key filters", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileKubernetes)
+		assert.NoError(t, writer.SetConfig("default", "image", "api:latest", false))
+		writer.schema.RenderFiles["k8s"].Kubernetes.ConfigKeys = []string{"namespace", "image"}
+		assert.NoError(t, writer.RemoveConfig("default", "image"))
+		assert.Equal(t, []string{"namespace"}, getSchema().RenderFiles["k8s"].Kubernetes.ConfigKeys)
+	})
+
+	t.Run("fail if the entry is the only key of a kubernetes key filter", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileKubernetes)
+		writer.schema.RenderFiles["k8s"].Kubernetes.ConfigKeys = []string{"namespace"}
+		assert.Error(t, writer.RemoveConfig("default", "namespace"))
+		assert.Contains(t, getSchema().Context["default"].Configs, "namespace")
+	})
+
+}
+
+func TestV1Writer_RenameSecret(t *testing.T) {
+
+	t.Run("rename the secret in all contexts", func(t *testing.T) {
+		writer, original, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		prodValue := original.Context["prod"].Secrets["databasePassword"]
+		assert.NoError(t, writer.RenameSecret("databasePassword", "dbPassword"))
+		assert.NotContains(t, getSchema().Context["default"].Secrets, "databasePassword")
+		assert.Equal(t, prodValue, getSchema().Context["prod"].Secrets["dbPassword"])
+	})
+
+	t.Run("fail if the secret does not exist or the new name is taken", func(t *testing.T) {
+		writer, _, _ := NewWrappedV1Writer(t, TestFileExtends)
+		assert.Error(t, writer.RenameSecret("unknown", "dbPassword"))
+		assert.NoError(t, writer.SetSecret("default", "other", "value", false))
+		assert.Error(t, writer.RenameSecret("databasePassword", "other"))
+	})
+
+}
+
+func TestV1Writer_RenameConfig(t *testing.T) {
+	writer, _, getSchema := NewWrappedV1Writer(t, TestFileKubernetes)
+	writer.schema.RenderFiles["k8s"].Kubernetes.ConfigKeys = []string{"namespace"}
+	assert.NoError(t, writer.RenameConfig("namespace", "k8sNamespace"))
+	assert.Equal(t, "my-namespace", getSchema().Context["default"].Configs["k8sNamespace"])
+	assert.Equal(t, []string{"k8sNamespace"}, getSchema().RenderFiles["k8s"].Kubernetes.ConfigKeys)
+}
+
+func TestV1Writer_RemoveContext(t *testing.T) {
+
+	t.Run("remove the context and the contexts extending it", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		assert.NoError(t, writer.RemoveContext("prod"))
+		assert.Len(t, getSchema().Context, 1)
+		assert.NotNil(t, getSchema().Context["default"])
+	})
+
+	t.Run("fail to remove the default context", func(t *testing.T) {
+		writer, _, _ := NewWrappedV1Writer(t, TestFileExtends)
+		assert.Error(t, writer.RemoveContext("default"))
+		assert.Error(t, writer.RemoveContext("staging"))
+	})
+
+}
+
+func TestV1Writer_RenameContext(t *testing.T) {
+
+	t.Run("rename the context and update the contexts extending it", func(t *testing.T) {
+		writer, original, getSchema := NewWrappedV1Writer(t, TestFileExtends)
+		prodConfigs := original.Context["prod"].Configs
+		assert.NoError(t, writer.RenameContext("prod", "production"))
+		assert.Nil(t, getSchema().Context["prod"])
+		assert.Equal(t, prodConfigs, getSchema().Context["production"].Configs)
+		assert.Equal(t, "production", getSchema().Context["prod-eu"].Extends)
+		assert.Equal(t, "production", getSchema().Context["prod-us"].Extends)
+	})
+
+	t.Run("fail to rename the default context or to an existing context", func(t *testing.T) {
+		writer, _, _ := NewWrappedV1Writer(t, TestFileExtends)
+		assert.Error(t, writer.RenameContext("default", "base"))
+		assert.Error(t, writer.RenameContext("prod", "prod-eu"))
+		assert.Error(t, writer.RenameContext("staging", "qa"))
+	})
+
+}
+
+func TestV1Writer_RemoveFileToRender(t *testing.T) {
+
+	t.Run("remove the file and the empty render target", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileBlankDefault)
+		assert.NoError(t, writer.AddFileToRender("env", "in-1", "out-1"))
+		assert.NoError(t, writer.AddFileToRender("env", "in-2", "out-2"))
+		assert.NoError(t, writer.RemoveFileToRender("env", "out-1"))
+		assert.Len(t, getSchema().RenderFiles["env"].Files, 1)
+		assert.NoError(t, writer.RemoveFileToRender("env", "out-2"))
+		assert.Nil(t, getSchema().RenderFiles["env"])
+	})
+
+	t.Run("fail if the file or the target does not exist", func(t *testing.T) {
+		writer, _, _ := NewWrappedV1Writer(t, TestFileBlankDefault)
+		assert.NoError(t, writer.AddFileToRender("env", "in-1", "out-1"))
+		assert.Error(t, writer.RemoveFileToRender("env", "out-2"))
+		assert.Error(t, writer.RemoveFileToRender("unknown", "out-1"))
+	})
+
+}
+
+func TestV1Writer_Transaction(t *testing.T) {
+
+	t.Run("write the changes once at commit", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileBlankDefault)
+		assert.NoError(t, writer.Begin())
+		assert.NoError(t, writer.SetConfig("default", "first", "1", false))
+		assert.NoError(t, writer.SetConfig("default", "second", "2", false))
+		assert.Empty(t, getSchema().Context["default"].Configs)
+		assert.NoError(t, writer.Commit())
+		assert.Equal(t, map[string]string{"first": "1", "second": "2"}, getSchema().Context["default"].Configs)
+	})
+
+	t.Run("discard the changes on rollback", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileBlankDefault)
+		assert.NoError(t, writer.Begin())
+		assert.NoError(t, writer.AddContext("prod"))
+		assert.NoError(t, writer.Rollback())
+		assert.Nil(t, writer.schema.Context["prod"])
+		assert.NoError(t, writer.AddContext("staging"))
+		assert.Nil(t, getSchema().Context["prod"])
+		assert.NotNil(t, getSchema().Context["staging"])
+	})
+
+	t.Run("roll back if the changes are not valid at commit", func(t *testing.T) {
+		writer, _, getSchema := NewWrappedV1Writer(t, TestFileBlankDefault)
+		assert.NoError(t, writer.Begin())
+		assert.NoError(t, writer.SetConfig("default", "first", "1", false))
+		delete(writer.schema.Context, "default")
+		assert.Error(t, writer.Commit())
+		assert.NotNil(t, writer.schema.Context["default"])
+		assert.Empty(t, writer.schema.Context["default"].Configs)
+		assert.NotNil(t, getSchema().Context["default"])
+	})
+
+	t.Run("fail on nested or missing transactions", func(t *testing.T) {
+		writer, _, _ := NewWrappedV1Writer(t, TestFileBlankDefault)
+		assert.Error(t, writer.Commit())
+		assert.Error(t, writer.Rollback())
+		assert.NoError(t, writer.Begin())
+		assert.Error(t, writer.Begin())
+	})
+
+}
@@ -0,0 +1,289 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/benammann/git-secrets/schema"
+	"github.com/spf13/afero"
+	"github.com/xeipuuv/gojsonschema"
+	"sort"
+	"strings"
+)
+
+type V2Schema struct {
+	Schema      string                     `json:"$schema,omitempty"`
+	Version     int                        `json:"version"`
+	Include     []string                   `json:"include,omitempty"`
+	Context     V2Context                  `json:"context"`
+	RenderFiles map[string]*V1RenderTarget `json:"renderFiles,omitempty"`
+	Exec        *V1Exec                    `json:"exec,omitempty"`
+}
+
+type V2Entry struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+	Type        string `json:"type,omitempty"`
+	RotateAfter string `json:"rotateAfter,omitempty"`
+	RotatedAt   string `json:"rotatedAt,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	MinLength   int    `json:"minLength,omitempty"`
+	Charset     string `json:"charset,omitempty"`
+}
+
+type V2ContextAwareSecrets struct {
+	Extends       string              `json:"extends,omitempty"`
+	DecryptSecret *V1DecryptSecret    `json:"decryptSecret,omitempty"`
+	Secrets       map[string]*V2Entry `json:"secrets,omitempty"`
+	Configs       map[string]*V2Entry `json:"configs,omitempty"`
+}
+
+type V2Context map[string]*V2ContextAwareSecrets
+
+var jsonLoaderV2 gojsonschema.JSONLoader
+
+func init() {
+	jsonLoaderV2 = gojsonschema.NewStringLoader(string(schema.GetSchemaContents(schema.V2)))
+}
+
+func IsSchemaV2(version int) bool {
+	return version == 2
+}
+
+// metadata returns the metadata of the entry
+func (e *V2Entry) metadata() Metadata {
+	return Metadata{
+		Description: e.Description,
+		Owner:       e.Owner,
+		ExpiresAt:   e.ExpiresAt,
+		Type:        EntryType(e.Type),
+		RotateAfter: e.RotateAfter,
+		RotatedAt:   e.RotatedAt,
+		Pattern:     e.Pattern,
+		MinLength:   e.MinLength,
+		Charset:     e.Charset,
+	}
+}
+
+// hasEntry returns true if the entry exists and holds a value
+func hasEntry(entries map[string]*V2Entry, key string) bool {
+	return entries[key] != nil && entries[key].Value != ""
+}
+
+// contextExtends returns the extended context of each context, empty if the context does not extend
+func (s *V2Schema) contextExtends() map[string]string {
+	extends := make(map[string]string)
+	for contextKey, contextValue := range s.Context {
+		extends[contextKey] = contextValue.Extends
+	}
+	return extends
+}
+
+func (s *V2Schema) renderTargets() map[string]*V1RenderTarget {
+	return s.RenderFiles
+}
+
+func (s *V2Schema) hasContext(contextName string) bool {
+	return s.Context[contextName] != nil
+}
+
+func (s *V2Schema) moveContext(oldName string, newName string) {
+	s.Context[newName] = s.Context[oldName]
+	delete(s.Context, oldName)
+}
+
+func (s *V2Schema) deleteContext(contextName string) {
+	delete(s.Context, contextName)
+}
+
+func (s *V2Schema) setExtends(contextName string, extends string) {
+	s.Context[contextName].Extends = extends
+}
+
+// entries returns the secrets or the configs of the context
+func (s *V2Schema) entries(contextName string, isSecret bool) map[string]*V2Entry {
+	if isSecret {
+		return s.Context[contextName].Secrets
+	}
+	return s.Context[contextName].Configs
+}
+
+func (s *V2Schema) definesEntry(contextName string, key string, isSecret bool) bool {
+	return hasEntry(s.entries(contextName, isSecret), key)
+}
+
+func (s *V2Schema) moveEntry(contextName string, oldKey string, newKey string, isSecret bool) {
+	entries := s.entries(contextName, isSecret)
+	if value, exists := entries[oldKey]; exists {
+		entries[newKey] = value
+		delete(entries, oldKey)
+	}
+}
+
+func (s *V2Schema) deleteEntry(contextName string, key string, isSecret bool) {
+	delete(s.entries(contextName, isSecret), key)
+}
+
+func (s *V2Schema) validateSchemaV2() error {
+
+	if !IsSchemaV2(s.Version) {
+		return fmt.Errorf("not able to process version %d", s.Version)
+	}
+
+	// check for default context
+	if s.Context["default"] == nil {
+		return fmt.Errorf("context.default is required")
+	}
+
+	// check for only one or none decryptSecret method
+	for contextKey, contextValue := range s.Context {
+		if errDecryptSecret := validateDecryptSecret(contextKey, contextValue.DecryptSecret); errDecryptSecret != nil {
+			return errDecryptSecret
+		}
+	}
+
+	extends := s.contextExtends()
+
+	if errExtends := validateExtends(extends); errExtends != nil {
+		return errExtends
+	}
+
+	for contextKey, contextValue := range s.Context {
+
+		ancestors := contextAncestors(extends, contextKey)
+
+		for _, secretKey := range sortedV2Keys(contextValue.Secrets) {
+			if errMetadata := contextValue.Secrets[secretKey].metadata().Validate(); errMetadata != nil {
+				return fmt.Errorf("secret %s in context %s: %s", secretKey, contextKey, errMetadata.Error())
+			}
+			// check if secret keys exists in a parent context
+			if contextKey != "default" && s.ancestorEntry(ancestors, secretKey, true) == nil {
+				return fmt.Errorf("secret %s exists in context %s but not in its parent contexts %s", secretKey, contextKey, strings.Join(ancestors, ", "))
+			}
+		}
+
+		for _, configKey := range sortedV2Keys(contextValue.Configs) {
+			configEntry := contextValue.Configs[configKey]
+			configMetadata := configEntry.metadata()
+			if errMetadata := configMetadata.Validate(); errMetadata != nil {
+				return fmt.Errorf("config entry %s in context %s: %s", configKey, contextKey, errMetadata.Error())
+			}
+			// check if config keys exists in a parent context
+			if contextKey != "default" && s.ancestorEntry(ancestors, configKey, false) == nil {
+				return fmt.Errorf("config entry %s exists in context %s but not in its parent contexts %s", configKey, contextKey, strings.Join(ancestors, ", "))
+			}
+			// configs are plain values, so their type and rules can be validated right away
+			for _, ancestor := range ancestors {
+				if ancestorConfig := s.Context[ancestor].Configs[configKey]; ancestorConfig != nil {
+					inheritRules(&configMetadata, ancestorConfig.metadata())
+				}
+			}
+			if errValue := configMetadata.ValidateValue(configEntry.Value); errValue != nil {
+				return fmt.Errorf("config entry %s in context %s is not valid: %s", configKey, contextKey, errValue.Error())
+			}
+		}
+
+	}
+
+	return nil
+
+}
+
+// ancestorEntry returns the nearest entry of the ancestors or nil if no ancestor defines it
+func (s *V2Schema) ancestorEntry(ancestors []string, key string, isSecret bool) *V2Entry {
+	for _, ancestor := range ancestors {
+		entries := s.Context[ancestor].Configs
+		if isSecret {
+			entries = s.Context[ancestor].Secrets
+		}
+		if hasEntry(entries, key) {
+			return entries[key]
+		}
+	}
+	return nil
+}
+
+func ParseSchemaV2(jsonInput []byte, configFileUsed string, globalConfig *global_config.GlobalConfigProvider, overwrittenSecrets map[string]string) (*Repository, error) {
+
+	definition, errDefinition := parseDefinitionV2(jsonInput, configFileUsed)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	return buildRepository(definition, globalConfig, overwrittenSecrets)
+
+}
+
+// parseDefinitionV2 validates and parses the json input into a repository definition
+func parseDefinitionV2(jsonInput []byte, configFileUsed string) (*repositoryDefinition, error) {
+
+	jsonContentLoader := gojsonschema.NewStringLoader(string(jsonInput))
+	res, errValidate := gojsonschema.Validate(jsonLoaderV2, jsonContentLoader)
+	if errValidate != nil {
+		return nil, fmt.Errorf("could not validateSchemaV2 json schema: %s", errValidate.Error())
+	}
+
+	if res.Valid() == false {
+		for _, schemaErr := range res.Errors() {
+			fmt.Println(schemaErr.String())
+		}
+		return nil, fmt.Errorf("invalid json passed")
+	}
+
+	var Parsed V2Schema
+	errParse := json.Unmarshal(jsonInput, &Parsed)
+	if errParse != nil {
+		return nil, fmt.Errorf("could not parse json: %s", errParse.Error())
+	}
+
+	if errValidate := Parsed.validateSchemaV2(); errValidate != nil {
+		return nil, fmt.Errorf("validation error: %s", errValidate.Error())
+	}
+
+	contexts := make(map[string]*contextDefinition)
+	for contextKey, contextValue := range Parsed.Context {
+		contexts[contextKey] = &contextDefinition{
+			extends:             contextValue.Extends,
+			decryptSecret:       contextValue.DecryptSecret,
+			decryptSecretSource: configFileUsed,
+			secrets:             annotatedEntries(contextValue.Secrets, configFileUsed),
+			configs:             annotatedEntries(contextValue.Configs, configFileUsed),
+		}
+	}
+
+	return &repositoryDefinition{
+		version:        2,
+		configFileUsed: configFileUsed,
+		configWriter:   NewV2Writer(afero.NewOsFs(), Parsed, configFileUsed),
+		include:        Parsed.Include,
+		contexts:       contexts,
+		renderFiles:    renderTargetDefinitions(Parsed.RenderFiles, configFileUsed),
+		exec:           Parsed.Exec,
+	}, nil
+
+}
+
+// annotatedEntries converts v2 entries to entries with metadata
+func annotatedEntries(entries map[string]*V2Entry, configFileUsed string) map[string]*entryDefinition {
+	definitions := make(map[string]*entryDefinition)
+	for key, entry := range entries {
+		definitions[key] = &entryDefinition{
+			value:    entry.Value,
+			metadata: entry.metadata(),
+			source:   configFileUsed,
+		}
+	}
+	return definitions
+}
+
+// sortedV2Keys returns the keys of the entries sorted alphabetically
+func sortedV2Keys(entries map[string]*V2Entry) []string {
+	var keys []string
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ParseAsSchemaV2(t *testing.T, fileName TestFile) V2Schema {
+	fs := afero.FromIOFS{
+		FS: testFiles,
+	}
+	fileBytes, errRead := afero.ReadFile(fs, fmt.Sprintf("test_fs/schema/v2/%s", fileName))
+	assert.NoError(t, errRead)
+
+	var Parsed V2Schema
+	errParse := json.Unmarshal(fileBytes, &Parsed)
+	assert.NoError(t, errParse)
+	return Parsed
+
+}
+
+func TestIsSchemaV2(t *testing.T) {
+	assert.True(t, IsSchemaV2(2))
+	assert.False(t, IsSchemaV2(1))
+	assert.False(t, IsSchemaV2(0))
+}
+
+func TestParseSchemaV2(t *testing.T) {
+
+	t.Run("parse metadata of the default context", func(t *testing.T) {
+		repository := initRepository(t, TestFileV2, "default")
+
+		secret := repository.GetCurrentSecret("databasePassword")
+		assert.Equal(t, "password of the application database user", secret.Metadata.Description)
+		assert.Equal(t, "team-backend", secret.Metadata.Owner)
+		assert.Equal(t, "2030-01-01", secret.Metadata.ExpiresAt)
+		assert.Equal(t, EntryTypeString, secret.Metadata.Type)
+		assert.Equal(t, "90d", secret.Metadata.RotateAfter)
+		assert.Equal(t, "2024-01-01", secret.Metadata.RotatedAt)
+
+		decoded, errDecode := secret.Decode()
+		assert.NoError(t, errDecode)
+		assert.NotEqual(t, "", decoded)
+
+		assert.Equal(t, "database.svc.local", repository.GetCurrentConfig("databaseHost").Value)
+		assert.Equal(t, EntryTypeNumber, repository.GetCurrentConfig("databasePort").Metadata.Type)
+	})
+
+	t.Run("inherit metadata except the expiry and rotation dates from the default context", func(t *testing.T) {
+		repository := initRepository(t, TestFileV2, "prod")
+
+		secret := repository.GetCurrentSecret("databasePassword")
+		assert.Equal(t, "prod", secret.OriginContext.Name)
+		assert.Equal(t, "team-backend", secret.Metadata.Owner)
+		assert.Equal(t, "2031-01-01", secret.Metadata.ExpiresAt)
+		assert.Equal(t, "90d", secret.Metadata.RotateAfter)
+		assert.Equal(t, "", secret.Metadata.RotatedAt)
+		assert.Equal(t, 16, secret.Metadata.MinLength)
+		assert.Equal(t, "alnum", secret.Metadata.Charset)
+		assert.EqualError(t, secret.Metadata.ValidateValue("short"), "the value has 5 characters, at least 16 are required")
+
+		port := repository.GetCurrentConfig("databasePort")
+		assert.Equal(t, "3307", port.Value)
+		assert.Equal(t, EntryTypeNumber, port.Metadata.Type)
+		assert.Equal(t, "^[0-9]{4}$", port.Metadata.Pattern)
+	})
+
+}
+
+func TestV2Schema_validateSchemaV2(t *testing.T) {
+	t.Run("fail if a config does not match the type of the default context", func(t *testing.T) {
+		parsed := ParseAsSchemaV2(t, "invalid-config-type.json")
+		assert.Error(t, parsed.validateSchemaV2())
+	})
+	t.Run("fail if expiresAt is not a date", func(t *testing.T) {
+		parsed := ParseAsSchemaV2(t, "invalid-expires-at.json")
+		assert.Error(t, parsed.validateSchemaV2())
+	})
+	t.Run("fail if secret is defined in child but not in default context", func(t *testing.T) {
+		parsed := ParseAsSchemaV2(t, "secret-missing-in-default.json")
+		assert.Error(t, parsed.validateSchemaV2())
+	})
+	t.Run("fail on unsupported versions", func(t *testing.T) {
+		parsed := V2Schema{Version: 1}
+		assert.Error(t, parsed.validateSchemaV2())
+	})
+}
@@ -0,0 +1,255 @@
+package config_generic
+
+import (
+	"fmt"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	"github.com/spf13/afero"
+)
+
+type V2Writer struct {
+	documentWriter[V2Schema]
+}
+
+func NewV2Writer(fs afero.Fs, schema V2Schema, configPath string) *V2Writer {
+	writer := &V2Writer{
+		documentWriter: newDocumentWriter(fs, schema, configPath, schemaHooks[V2Schema]{
+			validate: validateWrittenSchemaV2,
+			parse:    parseDefinitionV2,
+		}),
+	}
+	writer.owner = writer
+	return writer
+}
+
+func (v *V2Writer) SetSecret(contextName string, secretName string, secretEncodedValue string, force bool) error {
+	return v.SetSecrets(contextName, map[string]string{secretName: secretEncodedValue}, force)
+}
+
+// SetSecrets sets multiple secrets at once and writes the config a single time
+// the metadata of existing entries is kept, nothing is changed if one of the secrets can not be set
+func (v *V2Writer) SetSecrets(contextName string, secrets map[string]string, force bool) error {
+
+	if v.schema.Context[contextName] == nil {
+		return fmt.Errorf("the context %s does not exist", contextName)
+	}
+
+	for _, secretName := range sortedMapKeys(secrets) {
+		if err := v.canSetSecret(contextName, secretName, force); err != nil {
+			return err
+		}
+	}
+
+	if v.schema.Context[contextName].Secrets == nil {
+		v.schema.Context[contextName].Secrets = make(map[string]*V2Entry)
+	}
+
+	setEntryValues(v.schema.Context[contextName].Secrets, secrets)
+	v.markRotated(contextName, sortedMapKeys(secrets))
+
+	return v.write()
+
+}
+
+func (v *V2Writer) canSetSecret(contextName string, secretName string, force bool) error {
+
+	if contextName != config_const.DefaultContextName && !hasEntry(v.schema.Context[config_const.DefaultContextName].Secrets, secretName) {
+		return fmt.Errorf("you need to define secret entry %s in the default context first", secretName)
+	}
+
+	if hasEntry(v.schema.Context[contextName].Secrets, secretName) && force == false {
+		return fmt.Errorf("the secret %s does already exist. Use --force to overwrite", secretName)
+	}
+
+	return nil
+
+}
+
+func (v *V2Writer) SetConfig(contextName string, configName string, configValue string, force bool) error {
+	return v.SetConfigs(contextName, map[string]string{configName: configValue}, force)
+}
+
+// SetConfigs sets multiple config entries at once and writes the config a single time
+// the metadata of existing entries is kept, nothing is changed if one of the entries can not be set
+// the values are validated against their type and rules right away, even if a transaction is running
+func (v *V2Writer) SetConfigs(contextName string, configs map[string]string, force bool) error {
+
+	if v.schema.Context[contextName] == nil {
+		return fmt.Errorf("the context %s does not exist. Use git-secrets add context <contextName> to add a context", contextName)
+	}
+
+	for _, configName := range sortedMapKeys(configs) {
+		if err := v.canSetConfig(contextName, configName, force); err != nil {
+			return err
+		}
+	}
+
+	// the changes are applied to a copy which replaces the schema once it is valid
+	var changed V2Schema
+	if errCopy := copyDocument(v.schema, &changed); errCopy != nil {
+		return fmt.Errorf("could not copy config: %s", errCopy.Error())
+	}
+
+	if changed.Context[contextName].Configs == nil {
+		changed.Context[contextName].Configs = make(map[string]*V2Entry)
+	}
+
+	setEntryValues(changed.Context[contextName].Configs, configs)
+
+	if errValidate := changed.validateSchemaV2(); errValidate != nil {
+		return fmt.Errorf("not setting the config entries since they are not valid: %s", errValidate.Error())
+	}
+
+	v.schema = changed
+	return v.write()
+
+}
+
+func (v *V2Writer) canSetConfig(contextName string, configName string, force bool) error {
+
+	if contextName != config_const.DefaultContextName && !hasEntry(v.schema.Context[config_const.DefaultContextName].Configs, configName) {
+		return fmt.Errorf("you need to define config entry %s in the default context first", configName)
+	}
+
+	if hasEntry(v.schema.Context[contextName].Configs, configName) && force == false {
+		return fmt.Errorf("the config entry %s does already exist. Use --force to overwrite", configName)
+	}
+
+	return nil
+
+}
+
+func (v *V2Writer) AddContext(contextName string) error {
+
+	if v.schema.Context[contextName] != nil {
+		return fmt.Errorf("the context %s does already exist", contextName)
+	}
+
+	v.schema.Context[contextName] = &V2ContextAwareSecrets{
+		Secrets: make(map[string]*V2Entry),
+		Configs: make(map[string]*V2Entry),
+	}
+
+	return v.write()
+
+}
+
+func (v *V2Writer) AddFileToRender(targetName string, fileIn string, fileOut string) error {
+
+	if v.schema.RenderFiles == nil {
+		v.schema.RenderFiles = make(map[string]*V1RenderTarget)
+	}
+
+	if v.schema.RenderFiles[targetName] == nil {
+		v.schema.RenderFiles[targetName] = &V1RenderTarget{
+			Files: []*V1RenderTargetFileEntry{},
+		}
+	}
+
+	for _, fileToRender := range v.schema.RenderFiles[targetName].Files {
+		if fileToRender.FileIn == fileIn && fileToRender.FileOut == fileOut {
+			return fmt.Errorf("the combination %s / %s is already added to target %s", fileIn, fileOut, targetName)
+		}
+	}
+
+	v.schema.RenderFiles[targetName].Files = append(v.schema.RenderFiles[targetName].Files, &V1RenderTargetFileEntry{
+		FileIn:  fileIn,
+		FileOut: fileOut,
+	})
+
+	return v.write()
+
+}
+
+// RemoveSecret removes the secret from the context
+// if the secret is removed from the context defining it, it is also removed from all child contexts
+func (v *V2Writer) RemoveSecret(contextName string, secretName string) error {
+	return v.removeEntry(contextName, secretName, true)
+}
+
+// RemoveConfig removes the config entry from the context
+// if the entry is removed from the context defining it, it is also removed from all child contexts
+func (v *V2Writer) RemoveConfig(contextName string, configName string) error {
+	return v.removeEntry(contextName, configName, false)
+}
+
+func (v *V2Writer) removeEntry(contextName string, key string, isSecret bool) error {
+	if errRemove := removeSchemaEntry(&v.schema, contextName, key, isSecret); errRemove != nil {
+		return errRemove
+	}
+	return v.write()
+}
+
+// RenameSecret renames the secret in all contexts and render targets
+func (v *V2Writer) RenameSecret(oldName string, newName string) error {
+	return v.renameEntry(oldName, newName, true)
+}
+
+// RenameConfig renames the config entry in all contexts and render targets
+func (v *V2Writer) RenameConfig(oldName string, newName string) error {
+	return v.renameEntry(oldName, newName, false)
+}
+
+func (v *V2Writer) renameEntry(oldName string, newName string, isSecret bool) error {
+	if errRename := renameSchemaEntry(&v.schema, oldName, newName, isSecret); errRename != nil {
+		return errRename
+	}
+	return v.write()
+}
+
+// RemoveContext removes the context and all contexts extending it
+func (v *V2Writer) RemoveContext(contextName string) error {
+	if errRemove := removeSchemaContext(&v.schema, contextName); errRemove != nil {
+		return errRemove
+	}
+	return v.write()
+}
+
+// RenameContext renames the context and updates the contexts extending it
+func (v *V2Writer) RenameContext(oldName string, newName string) error {
+	if errRename := renameSchemaContext(&v.schema, oldName, newName); errRename != nil {
+		return errRename
+	}
+	return v.write()
+}
+
+// RemoveFileToRender removes the file from the render target
+func (v *V2Writer) RemoveFileToRender(targetName string, fileOut string) error {
+	if errRemove := removeFileToRender(v.schema.RenderFiles, targetName, fileOut); errRemove != nil {
+		return errRemove
+	}
+	return v.write()
+}
+
+// validateWrittenSchemaV2 validates the schema before it is written, the default context always holds the secrets
+func validateWrittenSchemaV2(schema *V2Schema) error {
+	for contextName, context := range schema.Context {
+		if context.Secrets == nil && contextName == config_const.DefaultContextName {
+			context.Secrets = make(map[string]*V2Entry)
+		}
+	}
+	return schema.validateSchemaV2()
+}
+
+// markRotated sets the rotation date of the secrets which need to be rotated, the rotation interval may be inherited from an ancestor
+func (v *V2Writer) markRotated(contextName string, secretNames []string) {
+	chain := append([]string{contextName}, contextAncestors(v.schema.contextExtends(), contextName)...)
+	for _, secretName := range secretNames {
+		for _, chainContext := range chain {
+			entry := v.schema.Context[chainContext].Secrets[secretName]
+			if entry != nil && entry.RotateAfter != "" {
+				v.schema.Context[contextName].Secrets[secretName].RotatedAt = metadataNow().UTC().Format(ExpiresAtLayout)
+				break
+			}
+		}
+	}
+}
+
+// setEntryValues sets the values of the entries and keeps the metadata of existing ones
+func setEntryValues(entries map[string]*V2Entry, values map[string]string) {
+	for key, value := range values {
+		if entries[key] == nil {
+			entries[key] = &V2Entry{}
+		}
+		entries[key].Value = value
+	}
+}
@@ -0,0 +1,203 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func NewWrappedV2Writer(t *testing.T, inputFileName string) (writer *V2Writer, getSchema func() V2Schema) {
+
+	fs := afero.NewMemMapFs()
+	configPath := ".git-secrets.json"
+
+	wantedConfig, errRead := testFiles.ReadFile(fmt.Sprintf("test_fs/%s", inputFileName))
+	assert.NoError(t, errRead)
+	assert.NoError(t, afero.WriteFile(fs, configPath, wantedConfig, 0664))
+
+	var wantedConfigParsed V2Schema
+	assert.NoError(t, json.Unmarshal(wantedConfig, &wantedConfigParsed))
+
+	return NewV2Writer(fs, wantedConfigParsed, configPath), func() V2Schema {
+		fileBytes, errReadWritten := afero.ReadFile(fs, configPath)
+		assert.NoError(t, errReadWritten)
+		var Parsed V2Schema
+		assert.NoError(t, json.Unmarshal(fileBytes, &Parsed))
+		return Parsed
+	}
+
+}
+
+func TestV2Writer_SetSecret(t *testing.T) {
+
+	t.Run("keep the metadata when overwriting a secret", func(t *testing.T) {
+		writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+		assert.NoError(t, writer.SetSecret("default", "databasePassword", "newValue", true))
+		entry := getSchema().Context["default"].Secrets["databasePassword"]
+		assert.Equal(t, "newValue", entry.Value)
+		assert.Equal(t, "team-backend", entry.Owner)
+		assert.Equal(t, "2030-01-01", entry.ExpiresAt)
+	})
+
+	t.Run("fail if the secret exists and force is not set", func(t *testing.T) {
+		writer, _ := NewWrappedV2Writer(t, TestFileV2)
+		assert.Error(t, writer.SetSecret("default", "databasePassword", "newValue", false))
+	})
+
+	t.Run("fail if the secret is missing in the default context", func(t *testing.T) {
+		writer, _ := NewWrappedV2Writer(t, TestFileV2)
+		assert.Error(t, writer.SetSecret("prod", "apiKey", "newValue", false))
+	})
+
+	t.Run("set the rotation date of secrets which need to be rotated", func(t *testing.T) {
+		metadataNow = func() time.Time {
+			return time.Date(2025, 3, 4, 23, 0, 0, 0, time.UTC)
+		}
+		defer func() {
+			metadataNow = time.Now
+		}()
+		writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+		assert.NoError(t, writer.SetSecret("default", "databasePassword", "newValue", true))
+		assert.NoError(t, writer.SetSecret("prod", "databasePassword", "newValue", true))
+		assert.NoError(t, writer.SetSecret("default", "apiKey", "newValue", false))
+		assert.Equal(t, "2025-03-04", getSchema().Context["default"].Secrets["databasePassword"].RotatedAt)
+		assert.Equal(t, "2025-03-04", getSchema().Context["prod"].Secrets["databasePassword"].RotatedAt)
+		assert.Equal(t, "", getSchema().Context["default"].Secrets["apiKey"].RotatedAt)
+	})
+
+	t.Run("add a new secret without metadata", func(t *testing.T) {
+		writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+		assert.NoError(t, writer.SetSecret("default", "apiKey", "newValue", false))
+		entry := getSchema().Context["default"].Secrets["apiKey"]
+		assert.Equal(t, "newValue", entry.Value)
+		assert.Equal(t, "", entry.Description)
+	})
+
+}
+
+func TestV2Writer_WriteConfigYaml(t *testing.T) {
+
+	fs := afero.NewMemMapFs()
+	configPath := ".git-secrets.yaml"
+	input := `# the secrets of the backend
+version: 2
+context:
+  default:
+    decryptSecret:
+      fromName: gitsecretspublic
+    secrets:
+      databasePassword:
+        value: encodedValue
+        expiresAt: 2030-01-01 # rotate before
+`
+	assert.NoError(t, afero.WriteFile(fs, configPath, []byte(input), 0664))
+
+	normalized, errNormalize := NormalizeDocument(FileFormatYaml, []byte(input))
+	assert.NoError(t, errNormalize)
+	var schema V2Schema
+	assert.NoError(t, json.Unmarshal(normalized, &schema))
+	assert.Equal(t, "2030-01-01", schema.Context["default"].Secrets["databasePassword"].ExpiresAt)
+
+	writer := NewV2Writer(fs, schema, configPath)
+	assert.NoError(t, writer.SetConfig("default", "databaseHost", "db.local", false))
+
+	written, errRead := afero.ReadFile(fs, configPath)
+	assert.NoError(t, errRead)
+	assert.Equal(t, `# the secrets of the backend
+version: 2
+context:
+  default:
+    decryptSecret:
+      fromName: gitsecretspublic
+    secrets:
+      databasePassword:
+        value: encodedValue
+        expiresAt: 2030-01-01 # rotate before
+    configs:
+      databaseHost:
+        value: db.local
+`, string(written))
+
+}
+
+func TestV2Writer_SetConfigs(t *testing.T) {
+
+	t.Run("fail if a value does not match the type", func(t *testing.T) {
+		writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+		assert.Error(t, writer.SetConfigs("prod", map[string]string{"databasePort": "not-a-port"}, true))
+		assert.Equal(t, "3307", getSchema().Context["prod"].Configs["databasePort"].Value)
+		assert.Equal(t, "3307", writer.schema.Context["prod"].Configs["databasePort"].Value)
+	})
+
+	t.Run("keep the schema unchanged if a value is not valid during a transaction", func(t *testing.T) {
+		writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+		assert.NoError(t, writer.Begin())
+		assert.Error(t, writer.SetConfigs("default", map[string]string{"databaseHost": "db.local", "databasePort": "not-a-port"}, true))
+		assert.Equal(t, "3306", writer.schema.Context["default"].Configs["databasePort"].Value)
+		assert.Equal(t, "database.svc.local", writer.schema.Context["default"].Configs["databaseHost"].Value)
+		assert.NoError(t, writer.SetConfigs("default", map[string]string{"databasePort": "5432"}, true))
+		assert.NoError(t, writer.Commit())
+		assert.Equal(t, "5432", getSchema().Context["default"].Configs["databasePort"].Value)
+		assert.Equal(t, "database.svc.local", getSchema().Context["default"].Configs["databaseHost"].Value)
+	})
+
+	t.Run("set multiple configs and keep the metadata", func(t *testing.T) {
+		writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+		assert.NoError(t, writer.SetConfigs("default", map[string]string{"databaseHost": "db.local", "databasePort": "5432"}, true))
+		configs := getSchema().Context["default"].Configs
+		assert.Equal(t, "db.local", configs["databaseHost"].Value)
+		assert.Equal(t, "hostname of the database", configs["databaseHost"].Description)
+		assert.Equal(t, "5432", configs["databasePort"].Value)
+		assert.Equal(t, "number", configs["databasePort"].Type)
+	})
+
+}
+
+func TestV2Writer_AddContext(t *testing.T) {
+	writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+	assert.NoError(t, writer.AddContext("staging"))
+	assert.NotNil(t, getSchema().Context["staging"])
+	assert.Error(t, writer.AddContext("staging"))
+}
+
+func TestV2Writer_AddFileToRender(t *testing.T) {
+	writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+	assert.NoError(t, writer.AddFileToRender("env", "fileIn", "fileOut"))
+	assert.Len(t, getSchema().RenderFiles["env"].Files, 2)
+	assert.Error(t, writer.AddFileToRender("env", "fileIn", "fileOut"))
+}
+
+func TestV2Writer_RemoveSecret(t *testing.T) {
+	writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+	assert.NoError(t, writer.RemoveSecret("prod", "databasePassword"))
+	assert.NotContains(t, getSchema().Context["prod"].Secrets, "databasePassword")
+	assert.Equal(t, "team-backend", getSchema().Context["default"].Secrets["databasePassword"].Owner)
+	assert.NoError(t, writer.RemoveSecret("default", "databasePassword"))
+	assert.NotContains(t, getSchema().Context["default"].Secrets, "databasePassword")
+}
+
+func TestV2Writer_RenameConfig(t *testing.T) {
+	writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+	assert.NoError(t, writer.RenameConfig("databasePort", "dbPort"))
+	assert.Equal(t, "number", string(getSchema().Context["default"].Configs["dbPort"].Type))
+	assert.Equal(t, "3307", getSchema().Context["prod"].Configs["dbPort"].Value)
+}
+
+func TestV2Writer_RemoveContext(t *testing.T) {
+	writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+	assert.NoError(t, writer.RemoveContext("prod"))
+	assert.Nil(t, getSchema().Context["prod"])
+}
+
+func TestV2Writer_Transaction(t *testing.T) {
+	writer, getSchema := NewWrappedV2Writer(t, TestFileV2)
+	assert.NoError(t, writer.Begin())
+	assert.NoError(t, writer.SetConfig("default", "databasePort", "3308", true))
+	assert.Equal(t, "3306", getSchema().Context["default"].Configs["databasePort"].Value)
+	assert.NoError(t, writer.Commit())
+	assert.Equal(t, "3308", getSchema().Context["default"].Configs["databasePort"].Value)
+	assert.Equal(t, "number", string(getSchema().Context["default"].Configs["databasePort"].Type))
+}
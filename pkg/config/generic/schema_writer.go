@@ -0,0 +1,211 @@
+package config_generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/config/writer"
+	"github.com/spf13/afero"
+)
+
+// documentWriter holds the transaction and write logic shared by the writers of all schema versions
+type documentWriter[S any] struct {
+	schema     S
+	configPath string
+	fs         afero.Fs
+
+	// snapshot holds the schema at the beginning of the running transaction, nil if no transaction is running
+	snapshot *S
+
+	// onWrite is called after the config has been written
+	onWrite func() error
+
+	// base holds the schema as it was read or last written, it is used to merge changes written by other processes
+	base S
+
+	// hooks are the parts depending on the schema version
+	hooks schemaHooks[S]
+
+	// owner is the writer embedding this one, it is set as config writer of the definitions
+	owner writer.ConfigWriter
+}
+
+// schemaHooks validate and parse the schema of a specific version
+type schemaHooks[S any] struct {
+	// validate validates the schema, missing default values may be set before
+	validate func(schema *S) error
+	// parse parses the json encoded schema into a definition
+	parse func(jsonInput []byte, configPath string) (*repositoryDefinition, error)
+}
+
+func newDocumentWriter[S any](fs afero.Fs, schema S, configPath string, hooks schemaHooks[S]) documentWriter[S] {
+	writer := documentWriter[S]{
+		fs:         fs,
+		schema:     schema,
+		configPath: configPath,
+		hooks:      hooks,
+	}
+	_ = copyDocument(schema, &writer.base)
+	return writer
+}
+
+// Begin starts a transaction, the changes are validated and written at once by Commit
+func (d *documentWriter[S]) Begin() error {
+
+	if d.snapshot != nil {
+		return fmt.Errorf("a transaction is already running")
+	}
+
+	var snapshot S
+	if errCopy := copyDocument(d.schema, &snapshot); errCopy != nil {
+		return fmt.Errorf("could not begin transaction: %s", errCopy.Error())
+	}
+
+	d.snapshot = &snapshot
+	return nil
+
+}
+
+// Commit validates and writes the changes of the running transaction, the changes are rolled back if they are not valid
+func (d *documentWriter[S]) Commit() error {
+
+	if d.snapshot == nil {
+		return fmt.Errorf("no transaction is running")
+	}
+
+	if errWrite := d.WriteConfig(); errWrite != nil {
+		d.schema = *d.snapshot
+		d.snapshot = nil
+		return errWrite
+	}
+
+	d.snapshot = nil
+	return d.notifyWrite()
+
+}
+
+// Rollback discards the changes of the running transaction
+func (d *documentWriter[S]) Rollback() error {
+
+	if d.snapshot == nil {
+		return fmt.Errorf("no transaction is running")
+	}
+
+	d.schema = *d.snapshot
+	d.snapshot = nil
+	return nil
+
+}
+
+// checkpoint returns a function restoring the config file and the schema as they were before the running transaction
+// MultiFileWriter uses it to restore the files written before another file of the same transaction failed
+func (d *documentWriter[S]) checkpoint() (func() error, error) {
+
+	if d.snapshot == nil {
+		return nil, fmt.Errorf("no transaction is running")
+	}
+
+	restoreFile, errCheckpoint := checkpointFile(d.fs, d.configPath)
+	if errCheckpoint != nil {
+		return nil, errCheckpoint
+	}
+
+	var schema, base S
+	if errCopy := copyDocument(d.snapshot, &schema); errCopy != nil {
+		return nil, fmt.Errorf("could not copy config: %s", errCopy.Error())
+	}
+	if errCopy := copyDocument(d.base, &base); errCopy != nil {
+		return nil, fmt.Errorf("could not copy config: %s", errCopy.Error())
+	}
+
+	return func() error {
+		d.schema = schema
+		d.base = base
+		return restoreFile()
+	}, nil
+
+}
+
+// write writes the config unless a transaction is running
+// the schema is restored to the last written one if the config can not be written, so the failed change is not part of the next write
+func (d *documentWriter[S]) write() error {
+	if d.snapshot != nil {
+		return nil
+	}
+	if errWrite := d.WriteConfig(); errWrite != nil {
+		var restored S
+		if errCopy := copyDocument(d.base, &restored); errCopy == nil {
+			d.schema = restored
+		}
+		return errWrite
+	}
+	return d.notifyWrite()
+}
+
+func (d *documentWriter[S]) notifyWrite() error {
+	if d.onWrite == nil {
+		return nil
+	}
+	return d.onWrite()
+}
+
+func (d *documentWriter[S]) setOnWrite(onWrite func() error) {
+	d.onWrite = onWrite
+}
+
+// validate validates the schema without writing it
+func (d *documentWriter[S]) validate() error {
+	return d.hooks.validate(&d.schema)
+}
+
+// definition returns the repository definition of the current schema
+func (d *documentWriter[S]) definition() (*repositoryDefinition, error) {
+
+	jsonInput, errMarshal := json.Marshal(d.schema)
+	if errMarshal != nil {
+		return nil, fmt.Errorf("could not encode config: %s", errMarshal.Error())
+	}
+
+	definition, errDefinition := d.hooks.parse(jsonInput, d.configPath)
+	if errDefinition != nil {
+		return nil, errDefinition
+	}
+
+	definition.configWriter = d.owner
+	return definition, nil
+
+}
+
+func (d *documentWriter[S]) WriteConfig() error {
+
+	unlock, errLock := lockConfig(d.fs, d.configPath)
+	if errLock != nil {
+		return errLock
+	}
+	defer unlock()
+
+	// another process may have written the config since it was read, its changes are merged with ours
+	var current S
+	changed, errChanged := readChangedSchema(d.fs, d.configPath, d.base, &current)
+	if errChanged != nil {
+		return errChanged
+	}
+	if changed {
+		var merged S
+		if errMerge := mergeSchema(d.base, d.schema, current, &merged); errMerge != nil {
+			return fmt.Errorf("the config file %s has been changed by another process: %s", d.configPath, errMerge.Error())
+		}
+		d.schema = merged
+	}
+
+	if errValidate := d.hooks.validate(&d.schema); errValidate != nil {
+		return fmt.Errorf("not writing config since it is not valid: %s", errValidate.Error())
+	}
+
+	var model S
+	if errWrite := writeSchema(d.fs, d.configPath, d.schema, &model); errWrite != nil {
+		return errWrite
+	}
+
+	return copyDocument(d.schema, &d.base)
+
+}
@@ -2,12 +2,42 @@ package global_config
 
 import (
 	"fmt"
+	"github.com/benammann/git-secrets/pkg/generator"
 	"regexp"
 	"sort"
 	"strings"
 )
 
 const SecretKeyPrefix = "secrets"
+const AuditKeyPrefix = "audit"
+const TrustedCommandsKey = "trustedCommands"
+
+// GeneratedSecretLength is the length of generated global secrets, the maximum aes key size of 32 bytes
+const GeneratedSecretLength = 32
+
+// AuditConfig configures the audit log, the audit log is disabled if no sink is configured
+type AuditConfig struct {
+
+	// Sink is the kind of the audit log: file, syslog or command
+	Sink string
+
+	// File is the path of the audit log file used by the file sink
+	File string
+
+	// Command is the command receiving each audit event on stdin used by the command sink
+	Command string
+}
+
+// TrustedCommand allows the config file to run the command of a fromCommand decryptSecret
+// config files are committed to the repository, so their commands only run once they have been trusted using git secrets trust
+type TrustedCommand struct {
+
+	// File is the abs path of the config file defining the command
+	File string
+
+	// Command is the command as it is written in the config file
+	Command string
+}
 
 type GlobalConfigProvider struct {
 	storageProvider StorageProvider
@@ -42,6 +72,15 @@ func (g *GlobalConfigProvider) SetSecret(secretKey string, secretValue string, f
 	return g.storageProvider.WriteConfig()
 }
 
+// GenerateSecret writes a random alphanumeric secret of 32 bytes which is generated using crypto/rand
+func (g *GlobalConfigProvider) GenerateSecret(secretKey string, force bool) error {
+	secretValue, errGenerate := generator.Generate(generator.CharsetAlnum, GeneratedSecretLength)
+	if errGenerate != nil {
+		return fmt.Errorf("could not generate secret: %s", errGenerate.Error())
+	}
+	return g.SetSecret(secretKey, secretValue, force)
+}
+
 func (g *GlobalConfigProvider) GetSecretKeys() []string {
 	var secretKeys []string
 	for _, key := range g.storageProvider.AllKeys() {
@@ -54,6 +93,73 @@ func (g *GlobalConfigProvider) GetSecretKeys() []string {
 	return secretKeys
 }
 
+// GetAuditConfig returns the audit log configuration: audit.sink, audit.file and audit.command
+func (g *GlobalConfigProvider) GetAuditConfig() *AuditConfig {
+	return &AuditConfig{
+		Sink:    g.storageProvider.GetString(fmt.Sprintf("%s.sink", AuditKeyPrefix)),
+		File:    g.storageProvider.GetString(fmt.Sprintf("%s.file", AuditKeyPrefix)),
+		Command: g.storageProvider.GetString(fmt.Sprintf("%s.command", AuditKeyPrefix)),
+	}
+}
+
+// GetTrustedCommands returns the commands which are allowed to run by their config files
+func (g *GlobalConfigProvider) GetTrustedCommands() []*TrustedCommand {
+	values, _ := g.storageProvider.Get(TrustedCommandsKey).([]interface{})
+	var trustedCommands []*TrustedCommand
+	for _, value := range values {
+		fields := stringFields(value)
+		if fields["file"] == "" || fields["command"] == "" {
+			continue
+		}
+		trustedCommands = append(trustedCommands, &TrustedCommand{File: fields["file"], Command: fields["command"]})
+	}
+	return trustedCommands
+}
+
+// IsCommandTrusted returns true if the config file is allowed to run the command
+func (g *GlobalConfigProvider) IsCommandTrusted(file string, command string) bool {
+	for _, trustedCommand := range g.GetTrustedCommands() {
+		if trustedCommand.File == file && trustedCommand.Command == command {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustCommand allows the config file to run the command, a changed command has to be trusted again
+func (g *GlobalConfigProvider) TrustCommand(file string, command string) error {
+
+	if g.IsCommandTrusted(file, command) {
+		return nil
+	}
+
+	var values []interface{}
+	for _, trustedCommand := range g.GetTrustedCommands() {
+		values = append(values, map[string]interface{}{"file": trustedCommand.File, "command": trustedCommand.Command})
+	}
+	values = append(values, map[string]interface{}{"file": file, "command": command})
+
+	g.storageProvider.Set(TrustedCommandsKey, values)
+
+	return g.storageProvider.WriteConfig()
+}
+
+// stringFields returns the string values of a map read from the config file, yaml maps may have interface keys
+func stringFields(value interface{}) map[string]string {
+	fields := make(map[string]string)
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range typed {
+			fields[strings.ToLower(key)], _ = fieldValue.(string)
+		}
+	case map[interface{}]interface{}:
+		for key, fieldValue := range typed {
+			fields[strings.ToLower(fmt.Sprint(key))], _ = fieldValue.(string)
+		}
+	}
+	return fields
+}
+
 func (g *GlobalConfigProvider) secretConfigKey(secretKey string) string {
 	return fmt.Sprintf("%s.%s", SecretKeyPrefix, strings.ToLower(secretKey))
 }
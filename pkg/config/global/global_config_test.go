@@ -66,6 +66,26 @@ func TestGlobalConfigProvider_SetSecret(t *testing.T) {
 
 }
 
+func TestGlobalConfigProvider_GenerateSecret(t *testing.T) {
+
+	globalCfg := NewGlobalConfigProvider(NewMemoryStorageProvider())
+
+	t.Run("it should generate a 32 byte secret", func(t *testing.T) {
+		assert.NoError(t, globalCfg.GenerateSecret("generated", false))
+		assert.Len(t, globalCfg.GetSecret("generated"), GeneratedSecretLength)
+		assert.NoError(t, validateAESSecret(globalCfg.GetSecret("generated")))
+	})
+
+	t.Run("it should not overwrite an existing secret without forcing", func(t *testing.T) {
+		generatedValue := globalCfg.GetSecret("generated")
+		assert.Error(t, globalCfg.GenerateSecret("generated", false))
+		assert.Equal(t, generatedValue, globalCfg.GetSecret("generated"))
+		assert.NoError(t, globalCfg.GenerateSecret("generated", true))
+		assert.NotEqual(t, generatedValue, globalCfg.GetSecret("generated"))
+	})
+
+}
+
 func TestGlobalConfigProvider_secretConfigKey(t *testing.T) {
 	globalCfg := NewGlobalConfigProvider(NewMemoryStorageProvider())
 
@@ -161,3 +181,43 @@ func Test_validateAESSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestGlobalConfigProvider_GetAuditConfig(t *testing.T) {
+
+	storage := NewMemoryStorageProvider()
+	globalCfg := NewGlobalConfigProvider(storage)
+
+	assert.Equal(t, &AuditConfig{}, globalCfg.GetAuditConfig())
+
+	storage.Set("audit.sink", "file")
+	storage.Set("audit.file", "/var/log/git-secrets.jsonl")
+	storage.Set("audit.command", "logger -t git-secrets")
+
+	assert.Equal(t, &AuditConfig{Sink: "file", File: "/var/log/git-secrets.jsonl", Command: "logger -t git-secrets"}, globalCfg.GetAuditConfig())
+
+}
+
+func TestGlobalConfigProvider_TrustCommand(t *testing.T) {
+
+	globalCfg := NewGlobalConfigProvider(NewMemoryStorageProvider())
+
+	t.Run("a command is not trusted by default", func(t *testing.T) {
+		assert.False(t, globalCfg.IsCommandTrusted("/repo/.git-secrets.json", "pass show app"))
+	})
+
+	t.Run("a trusted command is only trusted for its config file", func(t *testing.T) {
+		assert.NoError(t, globalCfg.TrustCommand("/repo/.git-secrets.json", "pass show app"))
+		assert.NoError(t, globalCfg.TrustCommand("/repo/.git-secrets.json", "pass show app"))
+		assert.True(t, globalCfg.IsCommandTrusted("/repo/.git-secrets.json", "pass show app"))
+		assert.False(t, globalCfg.IsCommandTrusted("/other/.git-secrets.json", "pass show app"))
+		assert.False(t, globalCfg.IsCommandTrusted("/repo/.git-secrets.json", "pass show other"))
+		assert.Len(t, globalCfg.GetTrustedCommands(), 1)
+	})
+
+	t.Run("it should read the trusted commands of a yaml file", func(t *testing.T) {
+		storage := NewMemoryStorageProvider()
+		storage.Set(TrustedCommandsKey, []interface{}{map[interface{}]interface{}{"file": "/repo/.git-secrets.json", "command": "pass show app"}})
+		assert.True(t, NewGlobalConfigProvider(storage).IsCommandTrusted("/repo/.git-secrets.json", "pass show app"))
+	})
+
+}
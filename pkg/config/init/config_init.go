@@ -4,6 +4,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
 	"github.com/spf13/afero"
 	"os"
 	"strings"
@@ -28,8 +29,14 @@ func WriteInitialConfig(fileSystem afero.Fs, fileName string, secretName string)
 
 	finalInitConfig := strings.ReplaceAll(string(initConfig), "{{secretName}}", secretName)
 
+	// convert the config to the format of the file extension
+	encodedConfig, errEncode := config_generic.EncodeDocument(config_generic.DetectFileFormat(fileName), []byte(finalInitConfig))
+	if errEncode != nil {
+		return fmt.Errorf("could not encode init config: %s", errEncode.Error())
+	}
+
 	// copy the file to its destination
-	errFsFile := afero.WriteFile(fileSystem, fileName, []byte(finalInitConfig), 0664)
+	errFsFile := afero.WriteFile(fileSystem, fileName, encodedConfig, 0664)
 	if errFsFile != nil {
 		return fmt.Errorf("could not write %s: %s", fileName, errFsFile.Error())
 	}
@@ -2,6 +2,7 @@ package config_init
 
 import (
 	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
@@ -34,4 +35,20 @@ func TestWriteInitialConfig(t *testing.T) {
 
 	})
 
+	t.Run("write the config in the format of the file extension", func(t *testing.T) {
+
+		for _, fileName := range []string{".git-secrets.yaml", ".git-secrets.toml"} {
+			assert.NoError(t, WriteInitialConfig(fs, fileName, DecryptSecretName))
+
+			fileBytes, errRead := afero.ReadFile(fs, fileName)
+			assert.NoError(t, errRead)
+			assert.False(t, strings.HasPrefix(string(fileBytes), "{"))
+
+			normalized, errNormalize := config_generic.NormalizeDocument(config_generic.DetectFileFormat(fileName), fileBytes)
+			assert.NoError(t, errNormalize)
+			assert.NoError(t, config_generic.ValidateDocument(normalized))
+		}
+
+	})
+
 }
@@ -2,8 +2,20 @@ package writer
 
 type ConfigWriter interface {
 	SetSecret(contextName string, secretName string, secretEncodedValue string, force bool) error
+	SetSecrets(contextName string, secrets map[string]string, force bool) error
 	SetConfig(contextName string, configName string, configValue string, force bool) error
+	SetConfigs(contextName string, configs map[string]string, force bool) error
 	AddContext(contextName string) error
 	AddFileToRender(targetName string, fileIn string, fileOut string) error
+	RemoveSecret(contextName string, secretName string) error
+	RemoveConfig(contextName string, configName string) error
+	RemoveContext(contextName string) error
+	RemoveFileToRender(targetName string, fileOut string) error
+	RenameSecret(oldName string, newName string) error
+	RenameConfig(oldName string, newName string) error
+	RenameContext(oldName string, newName string) error
 	WriteConfig() error
+	Begin() error
+	Commit() error
+	Rollback() error
 }
@@ -0,0 +1,294 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	config_const "github.com/benammann/git-secrets/pkg/config/const"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+const (
+	KindContext = "context"
+	KindConfig  = "config"
+	KindSecret  = "secret"
+	KindRender  = "render"
+)
+
+// Change describes an entry which differs between two repositories or contexts
+// the values of secrets are never included, they are compared by their fingerprints
+type Change struct {
+	Type ChangeType `json:"type"`
+
+	// Kind is the kind of the entry: context, config, secret or render
+	Kind string `json:"kind"`
+
+	// Context is the name of the compared context, before..after if different contexts are compared
+	Context string `json:"context,omitempty"`
+
+	// Name is the name of the entry, the context name or the render target name
+	Name string `json:"name"`
+
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Key returns the path of the changed entry, e.g. prod.secrets.databasePassword
+func (c *Change) Key() string {
+	switch c.Kind {
+	case KindConfig:
+		return fmt.Sprintf("%s.configs.%s", c.Context, c.Name)
+	case KindSecret:
+		return fmt.Sprintf("%s.secrets.%s", c.Context, c.Name)
+	default:
+		return fmt.Sprintf("%s %s", c.Kind, c.Name)
+	}
+}
+
+// entryKey identifies a config or secret of a context
+type entryKey struct {
+	kind string
+	name string
+}
+
+// CompareContexts compares the resolved configs and secrets of two contexts, including the values inherited from their ancestors
+func CompareContexts(before *config_generic.Repository, beforeContext string, after *config_generic.Repository, afterContext string) ([]*Change, error) {
+
+	beforeValues, errBefore := contextValues(before, beforeContext, true)
+	if errBefore != nil {
+		return nil, errBefore
+	}
+	afterValues, errAfter := contextValues(after, afterContext, true)
+	if errAfter != nil {
+		return nil, errAfter
+	}
+
+	contextName := afterContext
+	if beforeContext != afterContext {
+		contextName = fmt.Sprintf("%s..%s", beforeContext, afterContext)
+	}
+
+	return compareValues(contextName, beforeValues, afterValues), nil
+
+}
+
+// CompareRepositories compares the contexts, the configs and secrets defined by each context and the render targets of two repositories
+func CompareRepositories(before *config_generic.Repository, after *config_generic.Repository) []*Change {
+
+	var changes []*Change
+
+	for _, contextName := range contextNames(before, after) {
+
+		beforeContext, afterContext := before.GetContext(contextName), after.GetContext(contextName)
+		if beforeContext == nil {
+			changes = append(changes, &Change{Type: ChangeAdded, Kind: KindContext, Name: contextName, After: parentName(afterContext)})
+		} else if afterContext == nil {
+			changes = append(changes, &Change{Type: ChangeRemoved, Kind: KindContext, Name: contextName, Before: parentName(beforeContext)})
+		} else if parentName(beforeContext) != parentName(afterContext) {
+			changes = append(changes, &Change{Type: ChangeChanged, Kind: KindContext, Name: contextName, Before: parentName(beforeContext), After: parentName(afterContext)})
+		}
+
+		// the values of added and removed contexts are listed as well
+		beforeValues, _ := contextValues(before, contextName, false)
+		afterValues, _ := contextValues(after, contextName, false)
+		changes = append(changes, compareValues(contextName, beforeValues, afterValues)...)
+
+	}
+
+	beforeTargets, afterTargets := renderTargets(before), renderTargets(after)
+	for _, targetName := range sortedKeys(beforeTargets, afterTargets) {
+		beforeFiles, beforeExists := beforeTargets[targetName]
+		afterFiles, afterExists := afterTargets[targetName]
+		if !beforeExists {
+			changes = append(changes, &Change{Type: ChangeAdded, Kind: KindRender, Name: targetName, After: afterFiles})
+		} else if !afterExists {
+			changes = append(changes, &Change{Type: ChangeRemoved, Kind: KindRender, Name: targetName, Before: beforeFiles})
+		} else if beforeFiles != afterFiles {
+			changes = append(changes, &Change{Type: ChangeChanged, Kind: KindRender, Name: targetName, Before: beforeFiles, After: afterFiles})
+		}
+	}
+
+	return changes
+
+}
+
+// FormatText prints one line per change: + added, - removed and ~ changed
+func FormatText(changes []*Change) []byte {
+
+	if len(changes) == 0 {
+		return []byte("no differences\n")
+	}
+
+	var out bytes.Buffer
+	for _, change := range changes {
+		switch change.Type {
+		case ChangeAdded:
+			fmt.Fprintf(&out, "+ %s%s\n", change.Key(), formatValue(change.After))
+		case ChangeRemoved:
+			fmt.Fprintf(&out, "- %s%s\n", change.Key(), formatValue(change.Before))
+		default:
+			fmt.Fprintf(&out, "~ %s: %s -> %s\n", change.Key(), change.Before, change.After)
+		}
+	}
+	return out.Bytes()
+
+}
+
+// FormatJson prints the changes as json array
+func FormatJson(changes []*Change) ([]byte, error) {
+	if changes == nil {
+		changes = []*Change{}
+	}
+	encoded, errMarshal := json.MarshalIndent(changes, "", "  ")
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+	return append(encoded, '\n'), nil
+}
+
+func formatValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf(" = %s", value)
+}
+
+// contextValues returns the displayed values of the configs and secrets of the context
+// resolved also includes the values inherited from the ancestors, otherwise only the values defined by the context are returned
+func contextValues(repository *config_generic.Repository, contextName string, resolved bool) (map[entryKey]string, error) {
+
+	context := repository.GetContext(contextName)
+	if context == nil {
+		return nil, fmt.Errorf("the context %s does not exist", contextName)
+	}
+
+	chain := []*config_generic.Context{context}
+	if resolved {
+		chain = context.Chain()
+	}
+
+	values := make(map[entryKey]string)
+	for _, chainContext := range chain {
+		for _, config := range repository.GetConfigsByContext(chainContext.Name) {
+			if _, exists := values[entryKey{KindConfig, config.Name}]; !exists {
+				values[entryKey{KindConfig, config.Name}] = config.Value
+			}
+		}
+		for _, secret := range repository.GetSecretsByContext(chainContext.Name) {
+			if _, exists := values[entryKey{KindSecret, secret.Name}]; !exists {
+				values[entryKey{KindSecret, secret.Name}] = SecretValue(secret, false)
+			}
+		}
+	}
+
+	return values, nil
+
+}
+
+// compareValues compares the values of a context, configs are listed before secrets
+func compareValues(contextName string, before map[entryKey]string, after map[entryKey]string) (changes []*Change) {
+
+	keys := make(map[entryKey]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	var orderedKeys []entryKey
+	for key := range keys {
+		orderedKeys = append(orderedKeys, key)
+	}
+	sort.Slice(orderedKeys, func(i, j int) bool {
+		if orderedKeys[i].kind != orderedKeys[j].kind {
+			return orderedKeys[i].kind == KindConfig
+		}
+		return orderedKeys[i].name < orderedKeys[j].name
+	})
+
+	for _, key := range orderedKeys {
+		beforeValue, beforeExists := before[key]
+		afterValue, afterExists := after[key]
+		change := &Change{Kind: key.kind, Context: contextName, Name: key.name, Before: beforeValue, After: afterValue}
+		if !beforeExists {
+			change.Type = ChangeAdded
+		} else if !afterExists {
+			change.Type = ChangeRemoved
+		} else if beforeValue != afterValue {
+			change.Type = ChangeChanged
+		} else {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+
+}
+
+// contextNames returns the names of the contexts of both repositories, the default context first
+func contextNames(before *config_generic.Repository, after *config_generic.Repository) []string {
+	names := make(map[string]string)
+	for _, repository := range []*config_generic.Repository{before, after} {
+		for _, context := range repository.GetContexts() {
+			names[context.Name] = context.Name
+		}
+	}
+	sortedNames := sortedKeys(names)
+	sort.SliceStable(sortedNames, func(i, j int) bool {
+		return sortedNames[i] == config_const.DefaultContextName && sortedNames[j] != config_const.DefaultContextName
+	})
+	return sortedNames
+}
+
+func parentName(context *config_generic.Context) string {
+	if context.Parent == nil {
+		return ""
+	}
+	return fmt.Sprintf("extends %s", context.Parent.Name)
+}
+
+// renderTargets returns the files of every render target as a single line, paths are relative to the config file
+func renderTargets(repository *config_generic.Repository) map[string]string {
+	configDir := filepath.Dir(repository.GetConfigFileUsed())
+	targets := make(map[string]string)
+	for _, targetName := range repository.RenderTargetNames() {
+		var files []string
+		for _, fileToRender := range repository.GetRenderTarget(targetName).FilesToRender {
+			if fileToRender.Kubernetes != nil {
+				files = append(files, fmt.Sprintf("kubernetes -> %s", relativePath(configDir, fileToRender.FileOut)))
+				continue
+			}
+			files = append(files, fmt.Sprintf("%s -> %s", relativePath(configDir, fileToRender.FileIn), relativePath(configDir, fileToRender.FileOut)))
+		}
+		targets[targetName] = strings.Join(files, ", ")
+	}
+	return targets
+}
+
+func sortedKeys(maps ...map[string]string) []string {
+	unique := make(map[string]bool)
+	for _, values := range maps {
+		for key := range values {
+			unique[key] = true
+		}
+	}
+	var keys []string
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
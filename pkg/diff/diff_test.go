@@ -0,0 +1,192 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// changedTestDocument parses the test file after changing it by the function
+func changedTestDocument(t *testing.T, fileName string, change func(document map[string]interface{})) *config_generic.Repository {
+	contents, errRead := testFiles.ReadFile(fmt.Sprintf("test_fs/%s", fileName))
+	assert.NoError(t, errRead)
+	var document map[string]interface{}
+	assert.NoError(t, json.Unmarshal(contents, &document))
+	change(document)
+	changed, _ := json.Marshal(document)
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	_ = globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, false)
+	repository, errParse := config_generic.ParseDocument(fileName, changed, globalConfig, map[string]string{})
+	assert.NoError(t, errParse)
+	return repository
+}
+
+func testContexts(document map[string]interface{}) map[string]interface{} {
+	return document["context"].(map[string]interface{})
+}
+
+func testEntries(document map[string]interface{}, contextName string, kind string) map[string]interface{} {
+	return testContexts(document)[contextName].(map[string]interface{})[kind].(map[string]interface{})
+}
+
+func TestCompareContexts(t *testing.T) {
+
+	repository := parseTestDocument(t, TestFileV1, true)
+
+	t.Run("it should list the overwrites of a context", func(t *testing.T) {
+		changes, errCompare := CompareContexts(repository, "default", repository, "prod")
+		assert.NoError(t, errCompare)
+		assert.Equal(t, `~ default..prod.configs.databaseHost: database.svc.local -> database-prod.svc.cluster
+~ default..prod.configs.databasePort: 3306 -> 3307
+~ default..prod.secrets.databasePassword: ******** fingerprint:9f2d7199d579c109 -> ******** fingerprint:d9ef425d66c12bed
+`, string(FormatText(changes)))
+	})
+
+	t.Run("it should not list equal values", func(t *testing.T) {
+		changes, errCompare := CompareContexts(repository, "prod", repository, "prod")
+		assert.NoError(t, errCompare)
+		assert.Len(t, changes, 0)
+		assert.Equal(t, "no differences\n", string(FormatText(changes)))
+	})
+
+	t.Run("it should fail if the context does not exist", func(t *testing.T) {
+		_, errCompare := CompareContexts(repository, "default", repository, "missing")
+		assert.EqualError(t, errCompare, "the context missing does not exist")
+	})
+
+}
+
+func TestCompareRepositories(t *testing.T) {
+
+	before := parseTestDocument(t, TestFileV1, true)
+
+	t.Run("it should list added, removed and changed entries", func(t *testing.T) {
+		after := changedTestDocument(t, TestFileV1, func(document map[string]interface{}) {
+			testEntries(document, "default", "configs")["apiUrl"] = "https://api.local"
+			delete(testEntries(document, "prod", "configs"), "databasePort")
+			testEntries(document, "prod", "secrets")["databasePassword"] = testEntries(document, "default", "secrets")["databasePassword"]
+			delete(testContexts(document), "staging")
+			testContexts(document)["dev"] = map[string]interface{}{"configs": map[string]interface{}{"databaseHost": "localhost"}}
+			delete(document, "renderFiles")
+		})
+		changes := CompareRepositories(before, after)
+		assert.Equal(t, `+ default.configs.apiUrl = https://api.local
++ context dev = extends default
++ dev.configs.databaseHost = localhost
+- prod.configs.databasePort = 3307
+~ prod.secrets.databasePassword: ******** fingerprint:d9ef425d66c12bed -> ******** fingerprint:9f2d7199d579c109
+- context staging = extends default
+- staging.configs.databaseHost = database-stg.svc.cluster
+- staging.configs.databasePort = 3307
+- staging.secrets.databasePassword = ******** fingerprint:4877f24105bb23be
+- render env = templates/.env.dist -> templates/.env
+`, string(FormatText(changes)))
+	})
+
+	t.Run("it should ignore encrypting the same value again", func(t *testing.T) {
+		reEncoded, errEncode := before.GetContext("prod").EncodeValue("asonoce0ew5YooT5chieheyooch1sei1")
+		assert.NoError(t, errEncode)
+		after := changedTestDocument(t, TestFileV1, func(document map[string]interface{}) {
+			testEntries(document, "prod", "secrets")["databasePassword"] = reEncoded
+		})
+		assert.Len(t, CompareRepositories(before, after), 0)
+	})
+
+	t.Run("it should print the changes as json", func(t *testing.T) {
+		after := changedTestDocument(t, TestFileV1, func(document map[string]interface{}) {
+			testEntries(document, "prod", "configs")["databasePort"] = "3308"
+		})
+		encoded, errFormat := FormatJson(CompareRepositories(before, after))
+		assert.NoError(t, errFormat)
+		assert.Equal(t, `[
+  {
+    "type": "changed",
+    "kind": "config",
+    "context": "prod",
+    "name": "databasePort",
+    "before": "3307",
+    "after": "3308"
+  }
+]
+`, string(encoded))
+		empty, _ := FormatJson(nil)
+		assert.Equal(t, "[]\n", string(empty))
+	})
+
+}
+
+func TestLoadRevision(t *testing.T) {
+
+	if _, errGit := exec.LookPath("git"); errGit != nil {
+		t.Skip("git is not installed")
+	}
+
+	gitDir := t.TempDir()
+	runGit := func(args ...string) {
+		gitCommand := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@localhost"}, args...)...)
+		gitCommand.Dir = gitDir
+		output, errGit := gitCommand.CombinedOutput()
+		assert.NoError(t, errGit, string(output))
+	}
+
+	contents, _ := testFiles.ReadFile(fmt.Sprintf("test_fs/%s", TestFileV1))
+	configPath := filepath.Join(gitDir, "app", ".git-secrets.json")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	assert.NoError(t, os.WriteFile(configPath, contents, 0644))
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "initial")
+	workingTree := []byte(`{"version": 1, "context": {"default": {"decryptSecret": {"fromName": "gitSecretsTest"}}}}`)
+	assert.NoError(t, os.WriteFile(configPath, workingTree, 0644))
+
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	_ = globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, false)
+	keySource, errKeySource := config_generic.ParseDocument(configPath, workingTree, globalConfig, map[string]string{})
+	assert.NoError(t, errKeySource)
+
+	repository, errLoad := LoadRevision("HEAD", configPath, keySource)
+	assert.NoError(t, errLoad)
+	assert.NotNil(t, repository.GetContext("staging"))
+	assert.Equal(t, configPath, repository.GetConfigFileUsed())
+	_, errDecode := repository.GetSecretsByContext("staging")[0].Decode()
+	assert.NoError(t, errDecode)
+
+	t.Run("it should never use the decryptSecret of the revision", func(t *testing.T) {
+		markerFile := filepath.Join(t.TempDir(), "marker")
+		assert.NoError(t, os.WriteFile(configPath, []byte(`{"version": 1, "context": {"default": {"decryptSecret": {"fromCommand": "touch `+markerFile+`"}, "secrets": {"databasePassword": "prPy40oRzdeFelmL5xVhbadEWNV9puR3/aWTY+gTYXOrT2bksi5GS9lCTKi66A3ePYa0hbwMqXadlDZw"}}}}`), 0644))
+		runGit("commit", "-q", "-a", "-m", "command")
+		assert.NoError(t, os.WriteFile(configPath, workingTree, 0644))
+		commandRevision, errCommand := LoadRevision("HEAD", configPath, keySource)
+		assert.NoError(t, errCommand)
+		assert.Contains(t, SecretValue(commandRevision.GetSecretsByContext("default")[0], false), "fingerprint:")
+		assert.NoFileExists(t, markerFile)
+	})
+
+	t.Run("it should only load the included files", func(t *testing.T) {
+		sharedPath := filepath.Join(gitDir, "shared", "database.json")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(sharedPath), 0755))
+		assert.NoError(t, os.WriteFile(sharedPath, []byte(`{"version": 1, "context": {"default": {"decryptSecret": {"fromName": "gitSecretsTest"}, "configs": {"databaseHost": "database.svc"}}}}`), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(configPath), "package.json"), []byte(`not a config file`), 0644))
+		assert.NoError(t, os.WriteFile(configPath, []byte(`{"version": 1, "include": ["../shared/*.json"], "context": {"default": {"decryptSecret": {"fromName": "gitSecretsTest"}}}}`), 0644))
+		runGit("add", ".")
+		runGit("commit", "-q", "-m", "include")
+		assert.NoError(t, os.WriteFile(configPath, workingTree, 0644))
+		includeRevision, errInclude := LoadRevision("HEAD", configPath, keySource)
+		assert.NoError(t, errInclude)
+		assert.Equal(t, "database.svc", includeRevision.GetConfigsByContext("default")[0].Value)
+		assert.Equal(t, []string{sharedPath}, includeRevision.GetIncludedFiles())
+	})
+
+	_, errMissing := LoadRevision("HEAD", filepath.Join(gitDir, "other", ".git-secrets.json"), keySource)
+	assert.Error(t, errMissing)
+
+	_, errRevision := LoadRevision("unknown-revision", configPath, keySource)
+	assert.Error(t, errRevision)
+
+}
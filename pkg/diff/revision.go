@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/utility"
+	"github.com/spf13/afero"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LoadRevision parses the config file as it was committed in the git revision
+// only the config file and the files it includes are read from the revision, includes outside the config directory are resolved as well
+// the decryptSecret of the revision is never used, the keys are resolved by the contexts of keySource, the working tree config
+func LoadRevision(revision string, configFile string, keySource *config_generic.Repository) (*config_generic.Repository, error) {
+
+	configPath, _ := filepath.Abs(configFile)
+	configDir := filepath.Dir(configPath)
+
+	gitRoot, errRoot := utility.GetGitRoot(configDir)
+	if errRoot != nil {
+		return nil, errRoot
+	}
+	// the git root may be a symlinked path, so the config directory is resolved as well
+	resolvedConfigDir, errResolve := filepath.EvalSymlinks(configDir)
+	if errResolve != nil {
+		return nil, fmt.Errorf("could not resolve %s: %s", configDir, errResolve.Error())
+	}
+	relativeDir, errRel := filepath.Rel(gitRoot, resolvedConfigDir)
+	if errRel != nil {
+		return nil, fmt.Errorf("the config file %s is not part of the git repository %s", configPath, gitRoot)
+	}
+
+	loader := &revisionLoader{
+		gitRoot:     gitRoot,
+		revision:    revision,
+		configDir:   configDir,
+		relativeDir: filepath.ToSlash(relativeDir),
+		fs:          afero.NewMemMapFs(),
+		loaded:      make(map[string]bool),
+	}
+
+	exists, errExists := loader.exists(configPath)
+	if errExists != nil {
+		return nil, errExists
+	}
+	if !exists {
+		return nil, fmt.Errorf("the config file %s does not exist in revision %s", filepath.Base(configPath), revision)
+	}
+
+	if errLoad := loader.load(configPath); errLoad != nil {
+		return nil, errLoad
+	}
+
+	repository, errParse := config_generic.ParseUntrustedRepository(loader.fs, configPath, keySource)
+	if errParse != nil {
+		return nil, fmt.Errorf("could not parse revision %s: %s", revision, errParse.Error())
+	}
+
+	return repository, nil
+
+}
+
+// revisionLoader copies the config file and its includes from the git revision to fs, the files keep their working tree paths
+type revisionLoader struct {
+	gitRoot     string
+	revision    string
+	configDir   string
+	relativeDir string
+	fs          afero.Fs
+	loaded      map[string]bool
+}
+
+// load copies the file and the files it includes, includes which do not exist in the revision are reported by the parser
+func (l *revisionLoader) load(localPath string) error {
+
+	if l.loaded[localPath] {
+		return nil
+	}
+	l.loaded[localPath] = true
+
+	revisionPath, errPath := l.revisionPath(localPath)
+	if errPath != nil {
+		return errPath
+	}
+
+	contents, errRead := utility.GetRevisionFile(l.gitRoot, l.revision, revisionPath)
+	if errRead != nil {
+		return errRead
+	}
+	if errWrite := afero.WriteFile(l.fs, localPath, contents, 0644); errWrite != nil {
+		return errWrite
+	}
+
+	for _, includePattern := range documentIncludes(localPath, contents) {
+		matches, errMatch := l.match(filepath.Join(filepath.Dir(localPath), includePattern))
+		if errMatch != nil {
+			return errMatch
+		}
+		for _, includedFile := range matches {
+			if errLoad := l.load(includedFile); errLoad != nil {
+				return errLoad
+			}
+		}
+	}
+
+	return nil
+
+}
+
+// exists returns true if the file exists in the revision
+func (l *revisionLoader) exists(localPath string) (bool, error) {
+	matches, errMatch := l.match(localPath)
+	return len(matches) > 0, errMatch
+}
+
+// match returns the local paths of the revision files matching the pattern
+// only the files below the part of the pattern without wildcards are listed
+func (l *revisionLoader) match(localPattern string) ([]string, error) {
+
+	revisionPattern, errPath := l.revisionPath(localPattern)
+	if errPath != nil {
+		return nil, errPath
+	}
+
+	var prefix []string
+	for _, segment := range strings.Split(revisionPattern, "/") {
+		if strings.ContainsAny(segment, "*?[\\") {
+			break
+		}
+		prefix = append(prefix, segment)
+	}
+	listDir := "."
+	if len(prefix) > 0 {
+		listDir = strings.Join(prefix, "/")
+	}
+
+	files, errFiles := utility.GetRevisionFiles(l.gitRoot, l.revision, listDir)
+	if errFiles != nil {
+		return nil, errFiles
+	}
+
+	var matches []string
+	for _, file := range files {
+		isMatch, errPattern := path.Match(revisionPattern, file)
+		if errPattern != nil {
+			return nil, fmt.Errorf("invalid include %s: %s", localPattern, errPattern.Error())
+		}
+		if isMatch {
+			relativeFile, _ := filepath.Rel(filepath.FromSlash(l.relativeDir), filepath.FromSlash(file))
+			matches = append(matches, filepath.Join(l.configDir, relativeFile))
+		}
+	}
+
+	return matches, nil
+
+}
+
+// revisionPath returns the path of the local file relative to the git root
+func (l *revisionLoader) revisionPath(localPath string) (string, error) {
+	relativeFile, errRel := filepath.Rel(l.configDir, localPath)
+	if errRel != nil {
+		return "", fmt.Errorf("could not resolve %s: %s", localPath, errRel.Error())
+	}
+	revisionPath := path.Clean(path.Join(l.relativeDir, filepath.ToSlash(relativeFile)))
+	if revisionPath == ".." || strings.HasPrefix(revisionPath, "../") {
+		return "", fmt.Errorf("%s is not part of the git repository %s", localPath, l.gitRoot)
+	}
+	return revisionPath, nil
+}
+
+// documentIncludes returns the include patterns of the config file, invalid config files are reported by the parser
+func documentIncludes(fileName string, contents []byte) []string {
+	jsonContents, errNormalize := config_generic.NormalizeDocument(config_generic.DetectFileFormat(fileName), contents)
+	if errNormalize != nil {
+		return nil
+	}
+	var document struct {
+		Include []string `json:"include"`
+	}
+	if errParse := json.Unmarshal(jsonContents, &document); errParse != nil {
+		return nil
+	}
+	return document.Include
+}
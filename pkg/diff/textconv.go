@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MaskedValue replaces the value of secrets which are not revealed
+const MaskedValue = "********"
+
+// SecretValue returns the displayed value of the secret
+// the plain value is only returned if reveal is set, otherwise the masked value and the keyed fingerprint of the plain value
+// if the secret can not be decrypted the masked value and a hash of the encrypted value are returned
+func SecretValue(secret *config_generic.Secret, reveal bool) string {
+	if reveal {
+		if decodedValue, errDecode := secret.Decode(); errDecode == nil {
+			return decodedValue
+		}
+	}
+	if fingerprint, errFingerprint := secret.Fingerprint(); errFingerprint == nil {
+		return fmt.Sprintf("%s fingerprint:%s", MaskedValue, fingerprint)
+	}
+	encodedHash := sha256.Sum256([]byte(secret.EncodedValue))
+	return fmt.Sprintf("%s encrypted:%s", MaskedValue, hex.EncodeToString(encodedHash[:])[:16])
+}
+
+// TextConv returns a normalized view of the repository which is used as git textconv to diff config files
+// every context, config, secret and render file is printed on its own line, the values of secrets are masked unless reveal is set
+func TextConv(repository *config_generic.Repository, reveal bool) []byte {
+
+	var out bytes.Buffer
+
+	for _, context := range repository.GetContexts() {
+
+		if context.Parent == nil {
+			fmt.Fprintf(&out, "context %s\n", context.Name)
+		} else {
+			fmt.Fprintf(&out, "context %s extends %s\n", context.Name, context.Parent.Name)
+		}
+
+		for _, config := range repository.GetConfigsByContext(context.Name) {
+			fmt.Fprintf(&out, "%s.configs.%s = %s%s\n", context.Name, config.Name, config.Value, metadataSuffix(config.Metadata))
+		}
+
+		for _, secret := range repository.GetSecretsByContext(context.Name) {
+			fmt.Fprintf(&out, "%s.secrets.%s = %s%s\n", context.Name, secret.Name, SecretValue(secret, reveal), metadataSuffix(secret.Metadata))
+		}
+
+	}
+
+	targetNames := repository.RenderTargetNames()
+	sort.Strings(targetNames)
+	configDir := filepath.Dir(repository.GetConfigFileUsed())
+
+	for _, targetName := range targetNames {
+		for _, fileToRender := range repository.GetRenderTarget(targetName).FilesToRender {
+			fileOut := relativePath(configDir, fileToRender.FileOut)
+			if fileToRender.Kubernetes != nil {
+				fmt.Fprintf(&out, "render %s kubernetes -> %s\n", targetName, fileOut)
+				continue
+			}
+			fmt.Fprintf(&out, "render %s %s -> %s\n", targetName, relativePath(configDir, fileToRender.FileIn), fileOut)
+		}
+	}
+
+	return out.Bytes()
+
+}
+
+// metadataSuffix returns the metadata of an entry appended to its line, empty if there is no metadata
+func metadataSuffix(metadata config_generic.Metadata) string {
+	var fields []string
+	if metadata.Type != "" {
+		fields = append(fields, fmt.Sprintf("type=%s", metadata.Type))
+	}
+	if metadata.Owner != "" {
+		fields = append(fields, fmt.Sprintf("owner=%s", metadata.Owner))
+	}
+	if metadata.ExpiresAt != "" {
+		fields = append(fields, fmt.Sprintf("expiresAt=%s", metadata.ExpiresAt))
+	}
+	if metadata.RotateAfter != "" {
+		fields = append(fields, fmt.Sprintf("rotateAfter=%s", metadata.RotateAfter))
+	}
+	if metadata.RotatedAt != "" {
+		fields = append(fields, fmt.Sprintf("rotatedAt=%s", metadata.RotatedAt))
+	}
+	if metadata.Description != "" {
+		fields = append(fields, fmt.Sprintf("description=%q", metadata.Description))
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(fields, ", "))
+}
+
+// relativePath returns the path relative to the directory of the config file
+func relativePath(configDir string, path string) string {
+	relative, errRel := filepath.Rel(configDir, path)
+	if errRel != nil {
+		return path
+	}
+	return filepath.ToSlash(relative)
+}
@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"embed"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+const GlobalSecretKey = "gitSecretsTest"
+const GlobalSecretValue = "eeSaoghoh8oi9leed7hai4looK3jae1N"
+
+const TestFileV1 = "diff-test-v1.json"
+const TestFileV2 = "diff-test-v2.json"
+
+//go:embed test_fs
+var testFiles embed.FS
+
+func parseTestDocument(t *testing.T, fileName string, withSecret bool) *config_generic.Repository {
+	contents, errRead := testFiles.ReadFile(fmt.Sprintf("test_fs/%s", fileName))
+	assert.NoError(t, errRead)
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	if withSecret {
+		_ = globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, false)
+	}
+	repository, errParse := config_generic.ParseDocument(fileName, contents, globalConfig, map[string]string{})
+	assert.NoError(t, errParse)
+	return repository
+}
+
+func TestTextConv(t *testing.T) {
+
+	t.Run("it should mask the secrets and print their fingerprints", func(t *testing.T) {
+		assert.Equal(t, `context default
+default.configs.databaseHost = database.svc.local (description="hostname of the database")
+default.configs.databasePort = 3306 (type=number)
+default.secrets.databasePassword = ******** fingerprint:9f2d7199d579c109 (type=string, owner=team-backend, expiresAt=2030-01-01, description="password of the application database user")
+context prod extends default
+prod.configs.databasePort = 3307 (type=number)
+prod.secrets.databasePassword = ******** fingerprint:d9ef425d66c12bed (type=string, owner=team-backend, expiresAt=2031-01-01, description="password of the application database user")
+render env templates/.env.dist -> templates/.env
+`, string(TextConv(parseTestDocument(t, TestFileV2, true), false)))
+	})
+
+	t.Run("it should print the plain values if reveal is set", func(t *testing.T) {
+		textConv := string(TextConv(parseTestDocument(t, TestFileV2, true), true))
+		assert.Contains(t, textConv, "default.secrets.databasePassword = voo4ShaiPu4jai1TooTh1pheiKeiL4do (")
+		assert.Contains(t, textConv, "prod.secrets.databasePassword = asonoce0ew5YooT5chieheyooch1sei1 (")
+	})
+
+	t.Run("it should hash the encrypted values if no key is available", func(t *testing.T) {
+		textConv := string(TextConv(parseTestDocument(t, TestFileV1, false), true))
+		assert.Contains(t, textConv, "default.secrets.databasePassword = ******** encrypted:0d458b2c5cf39f40\n")
+		assert.Contains(t, textConv, "context staging extends default\n")
+	})
+
+	t.Run("it should keep the fingerprint if the value is encrypted again", func(t *testing.T) {
+		repository := parseTestDocument(t, TestFileV1, true)
+		secret := repository.GetSecretsByContext("prod")[0]
+		reEncoded, errEncode := secret.OriginContext.EncodeValue("asonoce0ew5YooT5chieheyooch1sei1")
+		assert.NoError(t, errEncode)
+		assert.NotEqual(t, secret.EncodedValue, reEncoded)
+		reEncrypted := *secret
+		reEncrypted.EncodedValue = reEncoded
+		assert.Equal(t, SecretValue(secret, false), SecretValue(&reEncrypted, false))
+	})
+
+	t.Run("it should resolve the keys of untrusted files by the working tree config only", func(t *testing.T) {
+		markerFile := filepath.Join(t.TempDir(), "marker")
+		contents := []byte(`{"version": 1, "context": {"default": {"decryptSecret": {"fromCommand": "touch ` + markerFile + `"}, "secrets": {"databasePassword": "prPy40oRzdeFelmL5xVhbadEWNV9puR3/aWTY+gTYXOrT2bksi5GS9lCTKi66A3ePYa0hbwMqXadlDZw"}}}}`)
+		repository, errParse := config_generic.ParseUntrustedDocument(TestFileV1, contents, parseTestDocument(t, TestFileV1, true))
+		assert.NoError(t, errParse)
+		assert.Contains(t, string(TextConv(repository, false)), "default.secrets.databasePassword = ******** fingerprint:")
+		assert.NoFileExists(t, markerFile)
+
+		withoutKeySource, errParse := config_generic.ParseUntrustedDocument(TestFileV1, contents, nil)
+		assert.NoError(t, errParse)
+		assert.Contains(t, string(TextConv(withoutKeySource, true)), "default.secrets.databasePassword = ******** encrypted:")
+		assert.NoFileExists(t, markerFile)
+	})
+
+}
@@ -0,0 +1,304 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Severity string
+
+const (
+	SeverityOk      Severity = "ok"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+const (
+	CheckSchema       = "schema"
+	CheckResolver     = "resolver"
+	CheckKeyLength    = "key-length"
+	CheckDecrypt      = "decrypt"
+	CheckExpiry       = "expiry"
+	CheckRotation     = "rotation"
+	CheckGitignore    = "gitignore"
+	CheckRenderTarget = "render-target"
+)
+
+// RecommendedKeyLength is the length of the raw keys in bytes which is needed for AES-256
+const RecommendedKeyLength = 32
+
+// Finding is the result of a check, checks without problems report a single ok finding
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+
+	// Context is the name of the context the finding relates to, empty if it is not related to a context
+	Context string `json:"context,omitempty"`
+
+	// Name is the name of the checked secret, config entry, render target or file
+	Name string `json:"name,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// Report holds the findings of all checks
+type Report struct {
+	Findings []*Finding `json:"findings"`
+	Errors   int        `json:"errors"`
+	Warnings int        `json:"warnings"`
+}
+
+// Options configures the checks
+type Options struct {
+
+	// Now is the time the expiry and rotation dates are compared with
+	Now time.Time
+
+	// WarnBefore is the time before the expiry or rotation date a warning is reported
+	WarnBefore time.Duration
+
+	// Fs is used to check if the templates of the render targets exist
+	Fs afero.Fs
+
+	// IsIgnored returns if a rendered file is ignored by git, the gitignore check is skipped if it is nil
+	IsIgnored func(path string) (bool, error)
+}
+
+// Check runs all checks against the repository, parseError is the error returned when parsing the config file
+// only the schema is checked if the config file could not be parsed
+func Check(repository *config_generic.Repository, parseError error, options *Options) *Report {
+
+	report := &Report{}
+
+	if parseError != nil {
+		report.add(&Finding{Check: CheckSchema, Severity: SeverityError, Message: fmt.Sprintf("the config file could not be parsed: %s", parseError.Error())})
+		return report
+	}
+
+	configFiles := append([]string{repository.GetConfigFileUsed()}, repository.GetIncludedFiles()...)
+	report.add(&Finding{Check: CheckSchema, Severity: SeverityOk, Message: fmt.Sprintf("%d config files are valid against the schema version %d", len(configFiles), repository.GetConfigVersion())})
+
+	resolveErrors := report.checkResolvers(repository)
+	report.checkSecrets(repository, resolveErrors)
+	report.checkDates(repository, options)
+	report.checkRenderTargets(repository, options)
+
+	return report
+
+}
+
+// HasProblems returns true if an error was found, warnings are included if strict is set
+func (r *Report) HasProblems(strict bool) bool {
+	return r.Errors > 0 || (strict && r.Warnings > 0)
+}
+
+// FormatText prints one line per finding followed by a summary
+func (r *Report) FormatText() []byte {
+	var out bytes.Buffer
+	for _, finding := range r.Findings {
+		subject := finding.Name
+		if finding.Context != "" && finding.Name != "" {
+			subject = fmt.Sprintf("%s.%s", finding.Context, finding.Name)
+		} else if finding.Context != "" {
+			subject = finding.Context
+		}
+		if subject != "" {
+			subject += ": "
+		}
+		fmt.Fprintf(&out, "%-7s %-13s %s%s\n", finding.Severity, finding.Check, subject, finding.Message)
+	}
+	fmt.Fprintf(&out, "%d errors, %d warnings\n", r.Errors, r.Warnings)
+	return out.Bytes()
+}
+
+// FormatJson prints the report as json
+func (r *Report) FormatJson() ([]byte, error) {
+	encoded, errMarshal := json.MarshalIndent(r, "", "  ")
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+	return append(encoded, '\n'), nil
+}
+
+func (r *Report) add(finding *Finding) {
+	switch finding.Severity {
+	case SeverityError:
+		r.Errors++
+	case SeverityWarning:
+		r.Warnings++
+	}
+	r.Findings = append(r.Findings, finding)
+}
+
+// addOk reports that the check found no problems if no finding has been added since the check started
+func (r *Report) addOk(check string, findingsBefore int, message string) {
+	if len(r.Findings) == findingsBefore {
+		r.add(&Finding{Check: check, Severity: SeverityOk, Message: message})
+	}
+}
+
+// checkResolvers resolves the raw key of each context and returns the errors by context name
+// contexts inheriting the resolver of their parent are not reported again
+func (r *Report) checkResolvers(repository *config_generic.Repository) map[string]error {
+
+	resolveErrors := make(map[string]error)
+	resolverFindings := len(r.Findings)
+
+	var keyLengthFindings []*Finding
+	for _, context := range repository.GetContexts() {
+
+		plainSecret, errResolve := context.SecretResolver.GetPlainSecret()
+		resolveErrors[context.Name] = errResolve
+
+		if context.Parent != nil && context.SecretResolver == context.Parent.SecretResolver {
+			continue
+		}
+
+		if errResolve != nil {
+			r.add(&Finding{Check: CheckResolver, Severity: SeverityError, Context: context.Name, Message: fmt.Sprintf("the encryption key can not be resolved: %s", errResolve.Error())})
+			continue
+		}
+
+		if len(plainSecret) < RecommendedKeyLength {
+			keyLengthFindings = append(keyLengthFindings, &Finding{Check: CheckKeyLength, Severity: SeverityWarning, Context: context.Name, Message: fmt.Sprintf("the encryption key has %d bytes, use a key of %d bytes for AES-256", len(plainSecret), RecommendedKeyLength)})
+		}
+
+	}
+	r.addOk(CheckResolver, resolverFindings, "the encryption keys of all contexts can be resolved")
+
+	keyFindings := len(r.Findings)
+	for _, finding := range keyLengthFindings {
+		r.add(finding)
+	}
+	r.addOk(CheckKeyLength, keyFindings, fmt.Sprintf("all resolved encryption keys have %d bytes", RecommendedKeyLength))
+
+	return resolveErrors
+
+}
+
+// checkSecrets decrypts all secrets of all contexts, secrets of contexts whose key can not be resolved are skipped
+func (r *Report) checkSecrets(repository *config_generic.Repository, resolveErrors map[string]error) {
+	findingsBefore := len(r.Findings)
+	for _, context := range repository.GetContexts() {
+		if resolveErrors[context.Name] != nil {
+			continue
+		}
+		for _, secret := range repository.GetSecretsByContext(context.Name) {
+			if _, errDecode := secret.Decode(); errDecode != nil {
+				r.add(&Finding{Check: CheckDecrypt, Severity: SeverityError, Context: context.Name, Name: secret.Name, Message: fmt.Sprintf("the secret can not be decrypted: %s", errDecode.Error())})
+			}
+		}
+	}
+	r.addOk(CheckDecrypt, findingsBefore, "all secrets with a resolvable key can be decrypted")
+}
+
+// checkDates reports expired entries and secrets which need to be rotated
+func (r *Report) checkDates(repository *config_generic.Repository, options *Options) {
+
+	expiryFindings := len(r.Findings)
+	for _, context := range repository.GetContexts() {
+		for _, secret := range repository.GetSecretsByContext(context.Name) {
+			r.checkExpiry(context.Name, secret.Name, secret.Metadata, options)
+		}
+		for _, config := range repository.GetConfigsByContext(context.Name) {
+			r.checkExpiry(context.Name, config.Name, config.Metadata, options)
+		}
+	}
+	r.addOk(CheckExpiry, expiryFindings, "no entry is expired or expires soon")
+
+	rotationFindings := len(r.Findings)
+	for _, context := range repository.GetContexts() {
+		for _, secret := range repository.GetSecretsByContext(context.Name) {
+			r.checkRotation(context.Name, secret.Name, secret.Metadata, options)
+		}
+	}
+	r.addOk(CheckRotation, rotationFindings, "no secret needs to be rotated")
+
+}
+
+func (r *Report) checkExpiry(contextName string, name string, metadata config_generic.Metadata, options *Options) {
+	expiresAt, _ := metadata.ExpiresAtTime()
+	if expiresAt == nil {
+		return
+	}
+	if options.Now.After(*expiresAt) {
+		r.add(&Finding{Check: CheckExpiry, Severity: SeverityError, Context: contextName, Name: name, Message: fmt.Sprintf("expired at %s", metadata.ExpiresAt)})
+	} else if options.Now.Add(options.WarnBefore).After(*expiresAt) {
+		r.add(&Finding{Check: CheckExpiry, Severity: SeverityWarning, Context: contextName, Name: name, Message: fmt.Sprintf("expires at %s", metadata.ExpiresAt)})
+	}
+}
+
+func (r *Report) checkRotation(contextName string, name string, metadata config_generic.Metadata, options *Options) {
+	if metadata.RotateAfter == "" {
+		return
+	}
+	dueAt, _ := metadata.RotationDueAt()
+	if dueAt == nil {
+		r.add(&Finding{Check: CheckRotation, Severity: SeverityWarning, Context: contextName, Name: name, Message: fmt.Sprintf("needs to be rotated every %s but the last rotation is unknown, set the secret to record it", metadata.RotateAfter)})
+		return
+	}
+	dueDate := dueAt.Format(config_generic.ExpiresAtLayout)
+	if options.Now.After(*dueAt) {
+		r.add(&Finding{Check: CheckRotation, Severity: SeverityError, Context: contextName, Name: name, Message: fmt.Sprintf("needed to be rotated at %s, rotated at %s every %s", dueDate, metadata.RotatedAt, metadata.RotateAfter)})
+	} else if options.Now.Add(options.WarnBefore).After(*dueAt) {
+		r.add(&Finding{Check: CheckRotation, Severity: SeverityWarning, Context: contextName, Name: name, Message: fmt.Sprintf("needs to be rotated at %s", dueDate)})
+	}
+}
+
+// checkRenderTargets reports render targets without files or templates and rendered files which are not ignored by git
+func (r *Report) checkRenderTargets(repository *config_generic.Repository, options *Options) {
+
+	configDir := filepath.Dir(repository.GetConfigFileUsed())
+
+	targetFindings := len(r.Findings)
+	for _, targetName := range repository.RenderTargetNames() {
+		filesToRender := repository.GetRenderTarget(targetName).FilesToRender
+		if len(filesToRender) == 0 {
+			r.add(&Finding{Check: CheckRenderTarget, Severity: SeverityWarning, Name: targetName, Message: "the render target has no files"})
+		}
+		for _, fileToRender := range filesToRender {
+			if fileToRender.Kubernetes != nil {
+				continue
+			}
+			if _, errStat := options.Fs.Stat(fileToRender.FileIn); os.IsNotExist(errStat) {
+				r.add(&Finding{Check: CheckRenderTarget, Severity: SeverityError, Name: targetName, Message: fmt.Sprintf("the template %s does not exist", relativePath(configDir, fileToRender.FileIn))})
+			}
+		}
+	}
+	r.addOk(CheckRenderTarget, targetFindings, "the templates of all render targets exist")
+
+	if options.IsIgnored == nil {
+		return
+	}
+
+	ignoreFindings := len(r.Findings)
+	for _, targetName := range repository.RenderTargetNames() {
+		for _, fileToRender := range repository.GetRenderTarget(targetName).FilesToRender {
+			isIgnored, errIgnored := options.IsIgnored(fileToRender.FileOut)
+			if errIgnored != nil {
+				r.add(&Finding{Check: CheckGitignore, Severity: SeverityWarning, Name: targetName, Message: fmt.Sprintf("skipped %s: %s", relativePath(configDir, fileToRender.FileOut), errIgnored.Error())})
+				continue
+			}
+			if !isIgnored {
+				r.add(&Finding{Check: CheckGitignore, Severity: SeverityError, Name: targetName, Message: fmt.Sprintf("the rendered file %s is not ignored by git", relativePath(configDir, fileToRender.FileOut))})
+			}
+		}
+	}
+	r.addOk(CheckGitignore, ignoreFindings, "all rendered files are ignored by git")
+
+}
+
+// relativePath returns the path relative to the directory of the config file
+func relativePath(configDir string, path string) string {
+	relative, errRel := filepath.Rel(configDir, path)
+	if errRel != nil {
+		return path
+	}
+	return filepath.ToSlash(relative)
+}
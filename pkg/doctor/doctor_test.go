@@ -0,0 +1,116 @@
+package doctor
+
+import (
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const GlobalSecretKey = "gitSecretsTest"
+const GlobalSecretValue = "eeSaoghoh8oi9leed7hai4looK3jae1N"
+
+const TestFile = "test_fs/doctor-test.json"
+
+func parseTestRepository(t *testing.T) *config_generic.Repository {
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	_ = globalConfig.SetSecret(GlobalSecretKey, GlobalSecretValue, false)
+	repository, errParse := config_generic.ParseRepository(afero.NewOsFs(), TestFile, globalConfig, map[string]string{"legacyKey": "0123456789abcdef"})
+	assert.NoError(t, errParse)
+	return repository
+}
+
+func testOptions(ignored map[string]bool) *Options {
+	return &Options{
+		Now:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		WarnBefore: 30 * 24 * time.Hour,
+		Fs:         afero.NewOsFs(),
+		IsIgnored: func(path string) (bool, error) {
+			return ignored[filepath.Base(path)], nil
+		},
+	}
+}
+
+// findingLines formats the findings as severity check subject: message
+func findingLines(report *Report) (lines []string) {
+	for _, finding := range report.Findings {
+		lines = append(lines, fmt.Sprintf("%s %s %s %s: %s", finding.Severity, finding.Check, finding.Context, finding.Name, finding.Message))
+	}
+	return lines
+}
+
+func TestCheck(t *testing.T) {
+
+	t.Run("it should report all problems", func(t *testing.T) {
+		report := Check(parseTestRepository(t), nil, testOptions(map[string]bool{".env": true}))
+		assert.Equal(t, []string{
+			"ok schema  : 1 config files are valid against the schema version 2",
+			"error resolver prod : the encryption key can not be resolved: env variable GIT_SECRETS_DOCTOR_TEST_MISSING is empty",
+			"warning key-length legacy : the encryption key has 16 bytes, use a key of 32 bytes for AES-256",
+			"error decrypt default apiKey: the secret can not be decrypted: encoded value is smaller than nonce size",
+			"error expiry default apiKey: expired at 2025-12-31",
+			"warning expiry default databaseHost: expires at 2026-01-15",
+			"warning rotation prod databasePassword: needs to be rotated every 90d but the last rotation is unknown, set the secret to record it",
+			"error rotation staging databasePassword: needed to be rotated at 2025-11-30, rotated at 2025-09-01 every 90d",
+			"error render-target  env: the template templates/missing.dist does not exist",
+			"error gitignore  env: the rendered file templates/missing is not ignored by git",
+		}, findingLines(report))
+		assert.Equal(t, 6, report.Errors)
+		assert.Equal(t, 3, report.Warnings)
+		assert.True(t, report.HasProblems(false))
+	})
+
+	t.Run("it should report ok findings for checks without problems", func(t *testing.T) {
+		options := testOptions(map[string]bool{".env": true, "missing": true})
+		options.Now = time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+		report := Check(parseTestRepository(t), nil, options)
+		assert.Contains(t, findingLines(report), "ok gitignore  : all rendered files are ignored by git")
+		assert.Contains(t, findingLines(report), "ok expiry  : no entry is expired or expires soon")
+	})
+
+	t.Run("it should check the remaining rendered files if one check fails", func(t *testing.T) {
+		options := testOptions(nil)
+		options.IsIgnored = func(path string) (bool, error) {
+			if filepath.Base(path) == ".env" {
+				return false, fmt.Errorf("git check-ignore failed")
+			}
+			return false, nil
+		}
+		lines := findingLines(Check(parseTestRepository(t), nil, options))
+		assert.Contains(t, lines, "warning gitignore  env: skipped templates/.env: git check-ignore failed")
+		assert.Contains(t, lines, "error gitignore  env: the rendered file templates/missing is not ignored by git")
+	})
+
+	t.Run("it should only report the schema if the config can not be parsed", func(t *testing.T) {
+		report := Check(nil, fmt.Errorf("invalid json passed"), testOptions(nil))
+		assert.Equal(t, []string{"error schema  : the config file could not be parsed: invalid json passed"}, findingLines(report))
+		assert.True(t, report.HasProblems(false))
+	})
+
+	t.Run("it should fail on warnings if strict is set", func(t *testing.T) {
+		report := &Report{}
+		report.add(&Finding{Check: CheckExpiry, Severity: SeverityWarning, Message: "expires soon"})
+		assert.False(t, report.HasProblems(false))
+		assert.True(t, report.HasProblems(true))
+	})
+
+}
+
+func TestReport_Format(t *testing.T) {
+
+	report := &Report{}
+	report.add(&Finding{Check: CheckExpiry, Severity: SeverityError, Context: "prod", Name: "apiKey", Message: "expired at 2025-12-31"})
+	report.add(&Finding{Check: CheckSchema, Severity: SeverityOk, Message: "valid"})
+
+	assert.Equal(t, "error   expiry        prod.apiKey: expired at 2025-12-31\nok      schema        valid\n1 errors, 0 warnings\n", string(report.FormatText()))
+
+	encoded, errFormat := report.FormatJson()
+	assert.NoError(t, errFormat)
+	assert.Contains(t, string(encoded), `"severity": "error"`)
+	assert.Contains(t, string(encoded), `"errors": 1`)
+
+}
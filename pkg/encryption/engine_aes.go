@@ -4,6 +4,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 )
@@ -21,15 +22,19 @@ func NewAesEngine(secretResolver SecretResolver) *AesEngine {
 func (a *AesEngine) newGcm() ([]byte, cipher.AEAD, error) {
 
 	// resolve the secret from the abstract secret resolver
+	// a key mismatch is returned as it is, it is more helpful than a generic resolve error
 	secret, errSecret := a.secretResolver.GetPlainSecret()
+	if errors.Is(errSecret, ErrKeyMismatch) {
+		return nil, nil, errSecret
+	}
 	if errSecret != nil {
-		return nil, nil, fmt.Errorf("could not resolve secret: %s", errSecret.Error())
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnresolvedSecret, errSecret.Error())
 	}
 
 	// create the new cipher
 	newCipher, errCipher := aes.NewCipher(secret)
 	if errCipher != nil {
-		return nil, nil, fmt.Errorf("could not create cipher instance from secret: %s", errCipher.Error())
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKey, errCipher.Error())
 	}
 
 	// create a gcm instance from cipher instance
@@ -70,14 +75,14 @@ func (a *AesEngine) DecodeValue(encodedValue string) (decodedValue string, err e
 	nonceSize := gcm.NonceSize()
 
 	encodedValueBytes := []byte(encodedValue)
-	if len(encodedValueBytes) < nonceSize {
-		return "", fmt.Errorf("encoded value is smaller than nonce size")
+	if len(encodedValueBytes) < nonceSize+gcm.Overhead() {
+		return "", ErrTruncatedPayload
 	}
 
 	nonce, cipherText := encodedValueBytes[:nonceSize], encodedValueBytes[nonceSize:]
 	plainBytes, errOpen := gcm.Open(nil, nonce, cipherText, nil)
 	if errOpen != nil {
-		return "", fmt.Errorf("could not open via gcm: %s", errOpen.Error())
+		return "", fmt.Errorf("%w: %s", ErrWrongKey, errOpen.Error())
 	}
 
 	return string(plainBytes), nil
@@ -18,6 +18,25 @@ func TestAesEngine_DecodeValue(t *testing.T) {
 		_, errDecode := engine.DecodeValue("abcdefg")
 		assert.Error(t, errDecode)
 	})
+	t.Run("return the reason why a value can not be decoded", func(t *testing.T) {
+		_, errDecode := engine.DecodeValue("abcdefg")
+		assert.ErrorIs(t, errDecode, ErrTruncatedPayload)
+
+		encodedValue, _ := NewAesEngine(NewMergedSecretResolver("other", nil, map[string]string{"other": "Oozahshai0eeTh4ohCeiD5eekiewie9o"})).EncodeValue("hello world")
+		_, errDecode = engine.DecodeValue(encodedValue)
+		assert.ErrorIs(t, errDecode, ErrWrongKey)
+
+		_, errDecode = NewAesEngine(NewEnvSecretResolver("SR_ENV_MISSING")).DecodeValue(encodedValue)
+		assert.ErrorIs(t, errDecode, ErrUnresolvedSecret)
+		assert.EqualError(t, errDecode, "could not resolve secret: env variable SR_ENV_MISSING is empty")
+
+		_, errDecode = NewAesEngine(NewMergedSecretResolver("short", nil, map[string]string{"short": "tooShort"})).DecodeValue(encodedValue)
+		assert.ErrorIs(t, errDecode, ErrInvalidKey)
+
+		_, errDecode = NewAesEngine(NewPinnedSecretResolver(NewEnvSecretResolver("SR_ENV"), "0000000000000000")).DecodeValue(encodedValue)
+		assert.ErrorIs(t, errDecode, ErrKeyMismatch)
+		assert.NotErrorIs(t, errDecode, ErrUnresolvedSecret)
+	})
 	t.Run("decode encrypted values", func(t *testing.T) {
 		str := "hello world"
 		encodedValue, errEncode := engine.EncodeValue(str)
@@ -60,3 +60,37 @@ func (rs *FromEnvSecretResolver) GetPlainSecret() (secret []byte, errResolve err
 	}
 	return []byte(envValue), nil
 }
+
+// PinnedSecretResolver verifies the secret of the wrapped resolver against the expected key fingerprint
+type PinnedSecretResolver struct {
+	secretResolver SecretResolver
+	keyFingerprint string
+}
+
+func NewPinnedSecretResolver(secretResolver SecretResolver, keyFingerprint string) *PinnedSecretResolver {
+	return &PinnedSecretResolver{
+		secretResolver: secretResolver,
+		keyFingerprint: keyFingerprint,
+	}
+}
+
+func (p *PinnedSecretResolver) GetPlainSecret() (secret []byte, errResolve error) {
+	secret, errResolve = p.secretResolver.GetPlainSecret()
+	if errResolve != nil {
+		return nil, errResolve
+	}
+	if actualFingerprint := KeyFingerprint(secret); actualFingerprint != p.keyFingerprint {
+		return nil, fmt.Errorf("%w: the secret has the fingerprint %s but the context expects %s", ErrKeyMismatch, actualFingerprint, p.keyFingerprint)
+	}
+	return secret, nil
+}
+
+// Unpinned returns the wrapped resolver which resolves the secret without verifying it
+func (p *PinnedSecretResolver) Unpinned() SecretResolver {
+	return p.secretResolver
+}
+
+// Pinned returns the expected key fingerprint
+func (p *PinnedSecretResolver) Pinned() string {
+	return p.keyFingerprint
+}
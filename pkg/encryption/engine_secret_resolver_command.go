@@ -0,0 +1,94 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/kballard/go-shellquote"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultCommandTimeout is the time a command has to print the secret, password managers may ask to unlock the vault first
+const DefaultCommandTimeout = 30 * time.Second
+
+// FromCommandSecretResolver runs a command and reads the secret from its stdout, e.g. pass or a password manager cli
+// the command is defined by a config file of the repository, so it only runs if the global config trusts it for this file
+type FromCommandSecretResolver struct {
+	command      string
+	file         string
+	globalConfig *global_config.GlobalConfigProvider
+	timeout      time.Duration
+}
+
+// NewCommandSecretResolver creates a resolver running the command of the config file, the arguments are split like a shell does
+func NewCommandSecretResolver(command string, file string, globalConfig *global_config.GlobalConfigProvider, timeout time.Duration) *FromCommandSecretResolver {
+	return &FromCommandSecretResolver{
+		command:      command,
+		file:         file,
+		globalConfig: globalConfig,
+		timeout:      timeout,
+	}
+}
+
+// Command returns the command as it is written in the config file
+func (c *FromCommandSecretResolver) Command() string {
+	return c.command
+}
+
+// File returns the abs path of the config file defining the command
+func (c *FromCommandSecretResolver) File() string {
+	return c.file
+}
+
+// IsTrusted returns true if the global config allows the config file to run the command
+func (c *FromCommandSecretResolver) IsTrusted() bool {
+	return c.globalConfig != nil && c.globalConfig.IsCommandTrusted(c.file, c.command)
+}
+
+func (c *FromCommandSecretResolver) GetPlainSecret() (secret []byte, errResolve error) {
+	if !c.IsTrusted() {
+		return nil, fmt.Errorf("%w: %s wants to run %s, review the command and run git secrets trust to allow it", ErrUntrustedCommand, c.file, c.command)
+	}
+	return memoize("command:"+c.command, c.run)
+}
+
+// run executes the command, stdin and stderr are passed through so the command is able to prompt
+func (c *FromCommandSecretResolver) run() ([]byte, error) {
+
+	args, errSplit := shellquote.Split(c.command)
+	if errSplit != nil {
+		return nil, fmt.Errorf("could not parse the command %s: %s", c.command, errSplit.Error())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("the command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	command := exec.CommandContext(ctx, args[0], args[1:]...)
+	command.Stdin = os.Stdin
+	command.Stdout = &stdout
+	command.Stderr = os.Stderr
+
+	errRun := command.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("the command %s did not finish within %s", args[0], c.timeout)
+	}
+	if errRun != nil {
+		return nil, fmt.Errorf("the command %s failed: %s", args[0], errRun.Error())
+	}
+
+	secret := strings.TrimRight(stdout.String(), "\r\n")
+	if secret == "" {
+		return nil, fmt.Errorf("the command %s did not print a secret", args[0])
+	}
+
+	return []byte(secret), nil
+}
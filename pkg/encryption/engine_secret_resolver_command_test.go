@@ -0,0 +1,82 @@
+package encryption
+
+import (
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFromCommandSecretResolver_GetPlainSecret(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands require a unix shell")
+	}
+
+	// the test commands are trusted for the test config file
+	const testFile = "/repo/.git-secrets.json"
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	newResolver := func(command string, timeout time.Duration) *FromCommandSecretResolver {
+		_ = globalConfig.TrustCommand(testFile, command)
+		return NewCommandSecretResolver(command, testFile, globalConfig, timeout)
+	}
+
+	t.Run("should not run a command which is not trusted", func(t *testing.T) {
+		markerFile := filepath.Join(t.TempDir(), "marker")
+		resolver := NewCommandSecretResolver(`touch `+markerFile, testFile, globalConfig, time.Second)
+		_, err := resolver.GetPlainSecret()
+		assert.ErrorIs(t, err, ErrUntrustedCommand)
+		assert.NoFileExists(t, markerFile)
+		_ = globalConfig.TrustCommand("/other/.git-secrets.json", `touch `+markerFile)
+		_, err = resolver.GetPlainSecret()
+		assert.ErrorIs(t, err, ErrUntrustedCommand)
+		_, err = NewCommandSecretResolver(`touch `+markerFile, testFile, nil, time.Second).GetPlainSecret()
+		assert.ErrorIs(t, err, ErrUntrustedCommand)
+		assert.NoFileExists(t, markerFile)
+	})
+
+	t.Run("should read the secret from stdout", func(t *testing.T) {
+		value, err := newResolver(`printf 'aju1ZieThohngii4eem4saeCh2fieral\n'`, time.Second).GetPlainSecret()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("aju1ZieThohngii4eem4saeCh2fieral"), value)
+	})
+
+	t.Run("should fail if the command fails", func(t *testing.T) {
+		_, err := newResolver(`sh -c "exit 3"`, time.Second).GetPlainSecret()
+		assert.EqualError(t, err, "the command sh failed: exit status 3")
+	})
+
+	t.Run("should fail if the command prints nothing", func(t *testing.T) {
+		_, err := newResolver(`true`, time.Second).GetPlainSecret()
+		assert.EqualError(t, err, "the command true did not print a secret")
+	})
+
+	t.Run("should fail if the command times out", func(t *testing.T) {
+		_, err := newResolver(`sleep 5`, 50*time.Millisecond).GetPlainSecret()
+		assert.EqualError(t, err, "the command sleep did not finish within 50ms")
+	})
+
+	t.Run("should run the command once per process", func(t *testing.T) {
+		counterFile := filepath.Join(t.TempDir(), "counter")
+		command := `sh -c "echo run >> ` + counterFile + `; echo aju1ZieThohngii4eem4saeCh2fieral"`
+		_ = globalConfig.TrustCommand(testFile, command)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := NewCommandSecretResolver(command, testFile, globalConfig, time.Second).GetPlainSecret()
+				assert.NoError(t, err)
+				assert.Equal(t, []byte("aju1ZieThohngii4eem4saeCh2fieral"), value)
+			}()
+		}
+		wg.Wait()
+		runs, _ := os.ReadFile(counterFile)
+		assert.Equal(t, "run\n", string(runs))
+	})
+
+}
@@ -0,0 +1,77 @@
+package encryption
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// FromFileSecretResolver reads the secret from a file, e.g. a key mounted by the ci
+type FromFileSecretResolver struct {
+	path    string
+	baseDir string
+}
+
+// NewFileSecretResolver creates a resolver reading the file at path
+// ~ and environment variables are expanded, relative paths are resolved from baseDir
+func NewFileSecretResolver(path string, baseDir string) *FromFileSecretResolver {
+	return &FromFileSecretResolver{
+		path:    path,
+		baseDir: baseDir,
+	}
+}
+
+// Path returns the expanded path of the file
+func (f *FromFileSecretResolver) Path() (string, error) {
+	path := os.ExpandEnv(f.path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, errHome := os.UserHomeDir()
+		if errHome != nil {
+			return "", fmt.Errorf("could not expand %s: %s", f.path, errHome.Error())
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.baseDir, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+func (f *FromFileSecretResolver) GetPlainSecret() (secret []byte, errResolve error) {
+	path, errPath := f.Path()
+	if errPath != nil {
+		return nil, errPath
+	}
+	return memoize("file:"+path, func() ([]byte, error) {
+		return readSecretFile(path)
+	})
+}
+
+// readSecretFile reads the secret and refuses files which are readable by everyone
+// the trailing line break most editors add is removed
+func readSecretFile(path string) ([]byte, error) {
+
+	fileInfo, errStat := os.Stat(path)
+	if errStat != nil {
+		return nil, fmt.Errorf("could not read secret file: %s", errStat.Error())
+	}
+
+	// windows does not support unix permissions
+	if runtime.GOOS != "windows" && fileInfo.Mode().Perm()&0004 != 0 {
+		return nil, fmt.Errorf("the secret file %s is readable by everyone (%s), restrict it using chmod o-rwx %s", path, fileInfo.Mode().Perm(), path)
+	}
+
+	contents, errRead := os.ReadFile(path)
+	if errRead != nil {
+		return nil, fmt.Errorf("could not read secret file: %s", errRead.Error())
+	}
+
+	secret := strings.TrimRight(string(contents), "\r\n")
+	if secret == "" {
+		return nil, fmt.Errorf("the secret file %s is empty", path)
+	}
+
+	return []byte(secret), nil
+}
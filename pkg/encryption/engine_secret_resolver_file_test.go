@@ -0,0 +1,66 @@
+package encryption
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFromFileSecretResolver_GetPlainSecret(t *testing.T) {
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "key.txt"), []byte("aju1ZieThohngii4eem4saeCh2fieral\n"), 0600))
+
+	t.Run("should read the secret without the trailing line break", func(t *testing.T) {
+		value, err := NewFileSecretResolver(filepath.Join(dir, "key.txt"), "").GetPlainSecret()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("aju1ZieThohngii4eem4saeCh2fieral"), value)
+	})
+
+	t.Run("should resolve relative paths from the base dir", func(t *testing.T) {
+		value, err := NewFileSecretResolver("key.txt", dir).GetPlainSecret()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("aju1ZieThohngii4eem4saeCh2fieral"), value)
+	})
+
+	t.Run("should expand env variables and the home dir", func(t *testing.T) {
+		t.Setenv("FILE_RESOLVER_DIR", dir)
+		path, errPath := NewFileSecretResolver("$FILE_RESOLVER_DIR/key.txt", "").Path()
+		assert.NoError(t, errPath)
+		assert.Equal(t, filepath.Join(dir, "key.txt"), path)
+
+		homeDir, _ := os.UserHomeDir()
+		path, errPath = NewFileSecretResolver("~/.keys/git-secrets", "").Path()
+		assert.NoError(t, errPath)
+		assert.Equal(t, filepath.Join(homeDir, ".keys/git-secrets"), path)
+	})
+
+	t.Run("should refuse files readable by everyone", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("windows does not support unix permissions")
+		}
+		publicFile := filepath.Join(dir, "public.txt")
+		assert.NoError(t, os.WriteFile(publicFile, []byte("aju1ZieThohngii4eem4saeCh2fieral"), 0600))
+		assert.NoError(t, os.Chmod(publicFile, 0644))
+		_, err := NewFileSecretResolver(publicFile, "").GetPlainSecret()
+		assert.EqualError(t, err, "the secret file "+publicFile+" is readable by everyone (-rw-r--r--), restrict it using chmod o-rwx "+publicFile)
+	})
+
+	t.Run("should fail if the file does not exist", func(t *testing.T) {
+		_, err := NewFileSecretResolver(filepath.Join(dir, "missing.txt"), "").GetPlainSecret()
+		assert.Error(t, err)
+	})
+
+	t.Run("should memoize the secret", func(t *testing.T) {
+		memoizedFile := filepath.Join(dir, "memoized.txt")
+		assert.NoError(t, os.WriteFile(memoizedFile, []byte("first"), 0600))
+		first, _ := NewFileSecretResolver(memoizedFile, "").GetPlainSecret()
+		assert.NoError(t, os.WriteFile(memoizedFile, []byte("second"), 0600))
+		second, _ := NewFileSecretResolver(memoizedFile, "").GetPlainSecret()
+		assert.Equal(t, []byte("first"), first)
+		assert.Equal(t, first, second)
+	})
+
+}
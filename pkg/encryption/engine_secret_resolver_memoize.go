@@ -0,0 +1,25 @@
+package encryption
+
+import "sync"
+
+// memoizedSecret holds the result of a single resolution
+type memoizedSecret struct {
+	once   sync.Once
+	secret []byte
+	err    error
+}
+
+// memoizedSecrets caches the secrets of the file and command resolvers per process
+// the repository is rebuilt after each write and secrets are decoded in parallel, so a command must not be run for every secret
+var memoizedSecrets sync.Map
+
+// memoize resolves the secret once per cache key, concurrent callers wait for the first resolution
+// errors are cached as well, so a failing command or a declined prompt is not repeated for every secret
+func memoize(cacheKey string, resolve func() ([]byte, error)) ([]byte, error) {
+	entry, _ := memoizedSecrets.LoadOrStore(cacheKey, &memoizedSecret{})
+	memoized := entry.(*memoizedSecret)
+	memoized.once.Do(func() {
+		memoized.secret, memoized.err = resolve()
+	})
+	return memoized.secret, memoized.err
+}
@@ -50,6 +50,25 @@ func TestMergedSecretResolver_GetPlainSecret(t *testing.T) {
 	})
 }
 
+func TestPinnedSecretResolver_GetPlainSecret(t *testing.T) {
+	resolver := NewMergedSecretResolver("pinned", nil, map[string]string{"pinned": "riz9ohg9IefeeG8sha0quoa6it6uan6b"})
+	fingerprint := KeyFingerprint([]byte("riz9ohg9IefeeG8sha0quoa6it6uan6b"))
+	t.Run("should return the secret if the fingerprint matches", func(t *testing.T) {
+		value, err := NewPinnedSecretResolver(resolver, fingerprint).GetPlainSecret()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("riz9ohg9IefeeG8sha0quoa6it6uan6b"), value)
+	})
+	t.Run("should fail on a key mismatch", func(t *testing.T) {
+		_, err := NewPinnedSecretResolver(resolver, "0000000000000000").GetPlainSecret()
+		assert.ErrorIs(t, err, ErrKeyMismatch)
+		assert.EqualError(t, err, "key mismatch: the secret has the fingerprint "+fingerprint+" but the context expects 0000000000000000")
+	})
+	t.Run("should pass resolve errors", func(t *testing.T) {
+		_, err := NewPinnedSecretResolver(NewEnvSecretResolver("MISSING"), fingerprint).GetPlainSecret()
+		assert.EqualError(t, err, "env variable MISSING is empty")
+	})
+}
+
 func TestNewEnvSecretResolver(t *testing.T) {
 	sr := NewEnvSecretResolver("ENV_NAME")
 	assert.NotNil(t, sr)
@@ -0,0 +1,27 @@
+package encryption
+
+import "errors"
+
+// the errors returned when decoding a value wrap one of these errors, use errors.Is to find out why a value could not be decoded
+var (
+	// ErrUnresolvedSecret is returned if the secret of the context can not be resolved, e.g. because the env variable is empty
+	ErrUnresolvedSecret = errors.New("could not resolve secret")
+
+	// ErrInvalidKey is returned if the resolved secret is no valid aes key, it must have 16, 24 or 32 bytes
+	ErrInvalidKey = errors.New("could not create cipher instance from secret")
+
+	// ErrInvalidBase64 is returned if the encoded value is no valid base64
+	ErrInvalidBase64 = errors.New("could not decode base64 value")
+
+	// ErrTruncatedPayload is returned if the encoded value is too short to hold the nonce and the authentication tag
+	ErrTruncatedPayload = errors.New("encoded value is smaller than nonce size")
+
+	// ErrWrongKey is returned if the value has been encrypted using another secret or has been modified
+	ErrWrongKey = errors.New("could not open via gcm")
+
+	// ErrUntrustedCommand is returned if the command of a fromCommand decryptSecret has not been trusted using git secrets trust
+	ErrUntrustedCommand = errors.New("untrusted command")
+
+	// ErrKeyMismatch is returned if the resolved secret does not match the key fingerprint pinned by the context
+	ErrKeyMismatch = errors.New("key mismatch")
+)
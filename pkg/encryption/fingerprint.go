@@ -0,0 +1,48 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// fingerprintLength is the number of hex characters of a fingerprint
+const fingerprintLength = 16
+
+// fingerprintLabel separates the fingerprint key from the encryption secret
+const fingerprintLabel = "git-secrets fingerprint"
+
+// Fingerprint returns a keyed fingerprint (hmac-sha256) of the plain value which can be compared without revealing the value
+// the hmac key is derived from the encryption secret, so only values encrypted with the same secret have comparable fingerprints
+func Fingerprint(secretResolver SecretResolver, plainValue string) (string, error) {
+
+	secret, errSecret := secretResolver.GetPlainSecret()
+	if errors.Is(errSecret, ErrKeyMismatch) {
+		return "", errSecret
+	}
+	if errSecret != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnresolvedSecret, errSecret.Error())
+	}
+
+	keyMac := hmac.New(sha256.New, secret)
+	keyMac.Write([]byte(fingerprintLabel))
+
+	valueMac := hmac.New(sha256.New, keyMac.Sum(nil))
+	valueMac.Write([]byte(plainValue))
+
+	return hex.EncodeToString(valueMac.Sum(nil))[:fingerprintLength], nil
+
+}
+
+// keyFingerprintLabel separates the key fingerprint from the value fingerprints
+const keyFingerprintLabel = "git-secrets key fingerprint"
+
+// KeyFingerprint returns a short id of the encryption secret itself (hmac-sha256 keyed by the secret)
+// two secrets with the same fingerprint are equal, the fingerprint does not reveal the secret
+func KeyFingerprint(secret []byte) string {
+	keyMac := hmac.New(sha256.New, secret)
+	keyMac.Write([]byte(keyFingerprintLabel))
+	return hex.EncodeToString(keyMac.Sum(nil))[:fingerprintLength]
+}
@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+
+	assert.NoError(t, os.Setenv("FP_FIRST", "aju1ZieThohngii4eem4saeCh2fieral"))
+	assert.NoError(t, os.Setenv("FP_SECOND", "riz9ohg9IefeeG8sha0quoa6it6uan6b"))
+
+	t.Run("should be stable for the same value and secret", func(t *testing.T) {
+		first, errFirst := Fingerprint(NewEnvSecretResolver("FP_FIRST"), "hello world")
+		assert.NoError(t, errFirst)
+		second, errSecond := Fingerprint(NewEnvSecretResolver("FP_FIRST"), "hello world")
+		assert.NoError(t, errSecond)
+		assert.Equal(t, first, second)
+		assert.Len(t, first, fingerprintLength)
+	})
+
+	t.Run("should differ for other values or secrets", func(t *testing.T) {
+		original, _ := Fingerprint(NewEnvSecretResolver("FP_FIRST"), "hello world")
+		otherValue, _ := Fingerprint(NewEnvSecretResolver("FP_FIRST"), "hello world!")
+		otherSecret, _ := Fingerprint(NewEnvSecretResolver("FP_SECOND"), "hello world")
+		assert.NotEqual(t, original, otherValue)
+		assert.NotEqual(t, original, otherSecret)
+	})
+
+	t.Run("should fail if the secret can not be resolved", func(t *testing.T) {
+		_, errFingerprint := Fingerprint(NewEnvSecretResolver("FP_MISSING"), "hello world")
+		assert.Error(t, errFingerprint)
+	})
+
+}
+
+func TestKeyFingerprint(t *testing.T) {
+	first := KeyFingerprint([]byte("aju1ZieThohngii4eem4saeCh2fieral"))
+	assert.Len(t, first, fingerprintLength)
+	assert.Equal(t, first, KeyFingerprint([]byte("aju1ZieThohngii4eem4saeCh2fieral")))
+	assert.NotEqual(t, first, KeyFingerprint([]byte("riz9ohg9IefeeG8sha0quoa6it6uan6b")))
+}
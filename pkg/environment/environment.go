@@ -0,0 +1,181 @@
+package environment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type CaseTransform string
+
+const (
+	// CaseUpperSnake transforms databasePassword to DATABASE_PASSWORD
+	CaseUpperSnake CaseTransform = "upper-snake"
+	// CaseSnake transforms databasePassword to database_password
+	CaseSnake CaseTransform = "snake"
+	// CaseUpper transforms databasePassword to DATABASEPASSWORD
+	CaseUpper CaseTransform = "upper"
+	// CaseLower transforms databasePassword to databasepassword
+	CaseLower CaseTransform = "lower"
+	// CaseNone keeps the key as it is
+	CaseNone CaseTransform = "none"
+)
+
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CaseTransforms returns all available case transforms
+func CaseTransforms() []CaseTransform {
+	return []CaseTransform{CaseUpperSnake, CaseSnake, CaseUpper, CaseLower, CaseNone}
+}
+
+// Mapper maps secret and config keys to environment variable names
+type Mapper struct {
+
+	// prefix is prepended to every transformed key
+	prefix string
+
+	// caseTransform is applied to every key which is not explicitly mapped
+	caseTransform CaseTransform
+
+	// mapping holds explicit environment variable names by key, prefix and case transform are not applied
+	mapping map[string]string
+}
+
+// NewMapper creates a new mapper, an empty case transform falls back to CaseUpperSnake
+func NewMapper(prefix string, caseTransform CaseTransform, mapping map[string]string) (*Mapper, error) {
+
+	if caseTransform == "" {
+		caseTransform = CaseUpperSnake
+	}
+
+	validCase := false
+	for _, availableCase := range CaseTransforms() {
+		if availableCase == caseTransform {
+			validCase = true
+			break
+		}
+	}
+	if !validCase {
+		return nil, fmt.Errorf("unknown case transform %s", caseTransform)
+	}
+
+	for key, envName := range mapping {
+		if !envNameRegex.MatchString(envName) {
+			return nil, fmt.Errorf("key %s is mapped to the invalid environment variable name %s", key, envName)
+		}
+	}
+
+	return &Mapper{
+		prefix:        prefix,
+		caseTransform: caseTransform,
+		mapping:       mapping,
+	}, nil
+}
+
+// EnvName returns the environment variable name of the given key
+func (m *Mapper) EnvName(key string) string {
+	if m.mapping[key] != "" {
+		return m.mapping[key]
+	}
+	return m.prefix + transformCase(key, m.caseTransform)
+}
+
+// Map maps the given secrets and configs to environment variables (NAME=value), sorted by name
+// fails if two keys resolve to the same variable name or if a name is not a valid variable name
+func (m *Mapper) Map(secrets map[string]string, configs map[string]string) ([]string, error) {
+
+	envValues := make(map[string]string)
+	envOrigins := make(map[string]string)
+
+	addValues := func(values map[string]string, kind string) error {
+		for key, value := range values {
+			envName := m.EnvName(key)
+			if !envNameRegex.MatchString(envName) {
+				return fmt.Errorf("%s %s resolves to the invalid environment variable name %s", kind, key, envName)
+			}
+			if envOrigins[envName] != "" {
+				return fmt.Errorf("%s %s and %s both resolve to the environment variable %s", kind, key, envOrigins[envName], envName)
+			}
+			envOrigins[envName] = fmt.Sprintf("%s %s", kind, key)
+			envValues[envName] = value
+		}
+		return nil
+	}
+
+	if errConfigs := addValues(configs, "config"); errConfigs != nil {
+		return nil, errConfigs
+	}
+
+	if errSecrets := addValues(secrets, "secret"); errSecrets != nil {
+		return nil, errSecrets
+	}
+
+	var envNames []string
+	for envName := range envValues {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	var env []string
+	for _, envName := range envNames {
+		env = append(env, fmt.Sprintf("%s=%s", envName, envValues[envName]))
+	}
+
+	return env, nil
+}
+
+// MergeEnv overwrites the variables of base (NAME=value) with the ones of overwrites
+func MergeEnv(base []string, overwrites []string) []string {
+
+	overwritten := make(map[string]bool)
+	for _, envEntry := range overwrites {
+		overwritten[strings.SplitN(envEntry, "=", 2)[0]] = true
+	}
+
+	var merged []string
+	for _, envEntry := range base {
+		if !overwritten[strings.SplitN(envEntry, "=", 2)[0]] {
+			merged = append(merged, envEntry)
+		}
+	}
+
+	return append(merged, overwrites...)
+}
+
+// transformCase applies the case transform to the key
+func transformCase(key string, caseTransform CaseTransform) string {
+	switch caseTransform {
+	case CaseUpperSnake:
+		return strings.ToUpper(toSnakeCase(key))
+	case CaseSnake:
+		return strings.ToLower(toSnakeCase(key))
+	case CaseUpper:
+		return strings.ToUpper(key)
+	case CaseLower:
+		return strings.ToLower(key)
+	default:
+		return key
+	}
+}
+
+// toSnakeCase splits camelCase, kebab-case and dotted keys by underscores
+func toSnakeCase(key string) string {
+	runes := []rune(key)
+	var out []rune
+	for i, r := range runes {
+		if r == '-' || r == '.' || r == ' ' {
+			r = '_'
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				out = append(out, '_')
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
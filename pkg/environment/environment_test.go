@@ -0,0 +1,78 @@
+package environment
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewMapper(t *testing.T) {
+	t.Run("fall back to upper snake case", func(t *testing.T) {
+		mapper, err := NewMapper("", "", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, CaseUpperSnake, mapper.caseTransform)
+	})
+	t.Run("fail on unknown case transforms", func(t *testing.T) {
+		_, err := NewMapper("", "camel", nil)
+		assert.Error(t, err)
+	})
+	t.Run("fail on invalid mapped names", func(t *testing.T) {
+		_, err := NewMapper("", CaseNone, map[string]string{"databasePassword": "DB PASSWORD"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMapper_EnvName(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefix        string
+		caseTransform CaseTransform
+		key           string
+		want          string
+	}{
+		{name: "upper snake", caseTransform: CaseUpperSnake, key: "databasePassword", want: "DATABASE_PASSWORD"},
+		{name: "upper snake with acronym", caseTransform: CaseUpperSnake, key: "apiURLValue", want: "API_URL_VALUE"},
+		{name: "upper snake with kebab case", caseTransform: CaseUpperSnake, key: "database-password", want: "DATABASE_PASSWORD"},
+		{name: "snake", caseTransform: CaseSnake, key: "databasePassword", want: "database_password"},
+		{name: "upper", caseTransform: CaseUpper, key: "databasePassword", want: "DATABASEPASSWORD"},
+		{name: "lower", caseTransform: CaseLower, key: "databasePassword", want: "databasepassword"},
+		{name: "none", caseTransform: CaseNone, key: "databasePassword", want: "databasePassword"},
+		{name: "prefix", prefix: "APP_", caseTransform: CaseUpperSnake, key: "databasePassword", want: "APP_DATABASE_PASSWORD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := NewMapper(tt.prefix, tt.caseTransform, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, mapper.EnvName(tt.key))
+		})
+	}
+	t.Run("explicit mapping ignores prefix and case", func(t *testing.T) {
+		mapper, err := NewMapper("APP_", CaseUpperSnake, map[string]string{"databasePassword": "DB_PASS"})
+		assert.NoError(t, err)
+		assert.Equal(t, "DB_PASS", mapper.EnvName("databasePassword"))
+		assert.Equal(t, "APP_DATABASE_HOST", mapper.EnvName("databaseHost"))
+	})
+}
+
+func TestMapper_Map(t *testing.T) {
+	t.Run("map secrets and configs sorted by name", func(t *testing.T) {
+		mapper, _ := NewMapper("", CaseUpperSnake, nil)
+		env, err := mapper.Map(map[string]string{"databasePassword": "secret=value"}, map[string]string{"databaseHost": "localhost"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"DATABASE_HOST=localhost", "DATABASE_PASSWORD=secret=value"}, env)
+	})
+	t.Run("fail if two keys resolve to the same name", func(t *testing.T) {
+		mapper, _ := NewMapper("", CaseUpperSnake, nil)
+		_, err := mapper.Map(map[string]string{"databaseHost": "a"}, map[string]string{"database-host": "b"})
+		assert.Error(t, err)
+	})
+	t.Run("fail if a key resolves to an invalid name", func(t *testing.T) {
+		mapper, _ := NewMapper("", CaseNone, nil)
+		_, err := mapper.Map(map[string]string{"database password": "a"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestMergeEnv(t *testing.T) {
+	merged := MergeEnv([]string{"PATH=/bin", "HOME=/root", "INVALID"}, []string{"HOME=/home/app", "DATABASE_HOST=localhost"})
+	assert.Equal(t, []string{"PATH=/bin", "INVALID", "HOME=/home/app", "DATABASE_HOST=localhost"}, merged)
+}
@@ -0,0 +1,73 @@
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// Run executes the command with the given environment and waits for it to exit
+// signals received by this process are forwarded to the child process, except the ones the terminal sends to both
+// returns the exit code of the child process
+func Run(command []string, env []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, error) {
+
+	if len(command) == 0 {
+		return 1, fmt.Errorf("no command given")
+	}
+
+	binary, errLookPath := exec.LookPath(command[0])
+	if errLookPath != nil {
+		return 127, fmt.Errorf("could not find %s: %s", command[0], errLookPath.Error())
+	}
+
+	childCmd := exec.Command(binary, command[1:]...)
+	childCmd.Env = env
+	childCmd.Stdin = stdin
+	childCmd.Stdout = stdout
+	childCmd.Stderr = stderr
+
+	// register the signal handler before starting so that no signal gets lost
+	// Notify without signals would catch all of them, so it is only called if signals are forwarded
+	signals := make(chan os.Signal, 1)
+	if len(forwardedSignals) > 0 {
+		signal.Notify(signals, forwardedSignals...)
+		defer signal.Stop(signals)
+	}
+
+	// terminal signals are only caught so this process keeps running until the child exits
+	terminal := make(chan os.Signal, 1)
+	signal.Notify(terminal, terminalSignals...)
+	defer signal.Stop(terminal)
+
+	if errStart := childCmd.Start(); errStart != nil {
+		return 1, fmt.Errorf("could not start %s: %s", command[0], errStart.Error())
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = childCmd.Process.Signal(sig)
+			case <-terminal:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	errWait := childCmd.Wait()
+	if errWait != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(errWait, &exitErr) {
+			return 1, fmt.Errorf("could not wait for %s: %s", command[0], errWait.Error())
+		}
+	}
+
+	return exitCode(childCmd.ProcessState), nil
+}
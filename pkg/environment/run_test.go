@@ -0,0 +1,58 @@
+//go:build !windows
+
+package environment
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("pass the environment to the child", func(t *testing.T) {
+		var stdout bytes.Buffer
+		code, err := Run([]string{"sh", "-c", "printf %s \"$DATABASE_PASSWORD\""}, []string{"DATABASE_PASSWORD=my secret"}, nil, &stdout, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "my secret", stdout.String())
+	})
+	t.Run("pass the exit code through", func(t *testing.T) {
+		code, err := Run([]string{"sh", "-c", "exit 42"}, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, code)
+	})
+	t.Run("fail if the command does not exist", func(t *testing.T) {
+		code, err := Run([]string{"git-secrets-missing-binary"}, nil, nil, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 127, code)
+	})
+	t.Run("fail if no command is given", func(t *testing.T) {
+		_, err := Run(nil, nil, nil, nil, nil)
+		assert.Error(t, err)
+	})
+	t.Run("forward signals to the child", func(t *testing.T) {
+		var stdout bytes.Buffer
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		}()
+		code, err := Run([]string{"sh", "-c", "trap 'echo forwarded; exit 3' USR1; for i in $(seq 1 50); do sleep 0.1; done; exit 9"}, nil, nil, &stdout, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, code)
+		assert.Equal(t, "forwarded\n", stdout.String())
+	})
+	t.Run("not forward signals the terminal sends to the child itself", func(t *testing.T) {
+		var stdout bytes.Buffer
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			_ = syscall.Kill(os.Getpid(), syscall.SIGINT)
+		}()
+		code, err := Run([]string{"sh", "-c", "trap 'echo forwarded; exit 3' INT; for i in $(seq 1 10); do sleep 0.1; done; exit 9"}, nil, nil, &stdout, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, code)
+		assert.Equal(t, "", stdout.String())
+	})
+}
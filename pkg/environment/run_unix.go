@@ -0,0 +1,21 @@
+//go:build !windows
+
+package environment
+
+import (
+	"os"
+	"syscall"
+)
+
+var forwardedSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2}
+
+// terminalSignals are sent by the terminal to the whole foreground process group, so the child receives them already
+var terminalSignals = []os.Signal{syscall.SIGINT, syscall.SIGQUIT}
+
+// exitCode returns the exit code of the process, 128 + signal number if it was terminated by a signal
+func exitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
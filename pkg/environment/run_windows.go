@@ -0,0 +1,17 @@
+//go:build windows
+
+package environment
+
+import (
+	"os"
+)
+
+var forwardedSignals []os.Signal
+
+// terminalSignals are sent by the console to all attached processes, so the child receives them already
+var terminalSignals = []os.Signal{os.Interrupt}
+
+// exitCode returns the exit code of the process
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatDotenv    Format = "dotenv"
+	FormatJson      Format = "json"
+	FormatYaml      Format = "yaml"
+	FormatShell     Format = "shell"
+	FormatK8sSecret Format = "k8s-secret"
+)
+
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Formats returns all the available export formats
+func Formats() []Format {
+	return []Format{FormatDotenv, FormatJson, FormatYaml, FormatShell, FormatK8sSecret}
+}
+
+// Options are passed to the formatters
+type Options struct {
+
+	// Name is used as metadata.name by the k8s-secret format
+	Name string
+
+	// Namespace is used as metadata.namespace by the k8s-secret format
+	Namespace string
+}
+
+// Export formats the values in the given format
+func Export(format Format, values map[string]string, options *Options) ([]byte, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	switch format {
+	case FormatDotenv:
+		return exportDotenv(values)
+	case FormatJson:
+		return exportJson(values)
+	case FormatYaml:
+		return exportYaml(values)
+	case FormatShell:
+		return exportShell(values)
+	case FormatK8sSecret:
+		return exportK8sSecret(values, options)
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+}
+
+// sortedKeys returns the keys of the map sorted alphabetically
+func sortedKeys(values map[string]string) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateVariableNames checks that all keys can be used as shell or dotenv variable names
+func validateVariableNames(values map[string]string, format Format) error {
+	for _, key := range sortedKeys(values) {
+		if !variableNameRegex.MatchString(key) {
+			return fmt.Errorf("key %s is not a valid variable name for format %s", key, format)
+		}
+	}
+	return nil
+}
+
+func exportDotenv(values map[string]string) ([]byte, error) {
+	if errValidate := validateVariableNames(values, FormatDotenv); errValidate != nil {
+		return nil, errValidate
+	}
+	var out bytes.Buffer
+	for _, key := range sortedKeys(values) {
+		out.WriteString(fmt.Sprintf("%s=%s\n", key, quoteDotenv(values[key])))
+	}
+	return out.Bytes(), nil
+}
+
+// quoteDotenv uses single quotes which are taken literally by dotenv parsers
+// values containing single quotes or line breaks are double-quoted and escaped
+func quoteDotenv(value string) string {
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		`$`, `\$`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func exportShell(values map[string]string) ([]byte, error) {
+	if errValidate := validateVariableNames(values, FormatShell); errValidate != nil {
+		return nil, errValidate
+	}
+	var out bytes.Buffer
+	for _, key := range sortedKeys(values) {
+		out.WriteString(fmt.Sprintf("export %s=%s\n", key, quoteShell(values[key])))
+	}
+	return out.Bytes(), nil
+}
+
+// quoteShell wraps the value in single quotes, embedded single quotes are closed, escaped and reopened
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func exportJson(values map[string]string) ([]byte, error) {
+	if values == nil {
+		values = make(map[string]string)
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if errEncode := encoder.Encode(values); errEncode != nil {
+		return nil, fmt.Errorf("could not encode json: %s", errEncode.Error())
+	}
+	return out.Bytes(), nil
+}
+
+func exportYaml(values map[string]string) ([]byte, error) {
+	if values == nil {
+		values = make(map[string]string)
+	}
+	return marshalYaml(values)
+}
+
+// marshalYaml marshals the value using two spaces of indentation
+func marshalYaml(value interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if errEncode := encoder.Encode(value); errEncode != nil {
+		return nil, fmt.Errorf("could not encode yaml: %s", errEncode.Error())
+	}
+	if errClose := encoder.Close(); errClose != nil {
+		return nil, fmt.Errorf("could not encode yaml: %s", errClose.Error())
+	}
+	return out.Bytes(), nil
+}
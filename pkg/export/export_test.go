@@ -0,0 +1,123 @@
+package export
+
+import (
+	"encoding/json"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func trickyValues() map[string]string {
+	return map[string]string{
+		"simple":      "value",
+		"empty":       "",
+		"withSpaces":  "  leading and trailing  ",
+		"singleQuote": "it's",
+		"doubleQuote": `say "hello"`,
+		"multiLine":   "line one\nline two",
+		"dollar":      "$HOME and ${HOME}",
+		"backslash":   `C:\path\to\n`,
+		"hash":        "value # no comment",
+		"equals":      "a=b=c",
+		"unicode":     "grüezi 🔐",
+		"yamlLike":    "key: value",
+		"mixed":       "it's \"$mixed\"\n\\n",
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Run("fail on unsupported formats", func(t *testing.T) {
+		_, err := Export("xml", trickyValues(), nil)
+		assert.Error(t, err)
+	})
+	t.Run("fail on invalid variable names", func(t *testing.T) {
+		for _, format := range []Format{FormatDotenv, FormatShell} {
+			_, err := Export(format, map[string]string{"my-key": "value"}, nil)
+			assert.Error(t, err)
+		}
+	})
+	t.Run("export values sorted by key", func(t *testing.T) {
+		out, err := Export(FormatDotenv, map[string]string{"b": "2", "a": "1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "a='1'\nb='2'\n", string(out))
+	})
+}
+
+func TestExport_Dotenv(t *testing.T) {
+	out, err := Export(FormatDotenv, trickyValues(), nil)
+	assert.NoError(t, err)
+	parsed, errParse := godotenv.Unmarshal(string(out))
+	assert.NoError(t, errParse)
+	assert.Equal(t, trickyValues(), parsed)
+}
+
+func TestExport_Json(t *testing.T) {
+	out, err := Export(FormatJson, trickyValues(), nil)
+	assert.NoError(t, err)
+	var parsed map[string]string
+	assert.NoError(t, json.Unmarshal(out, &parsed))
+	assert.Equal(t, trickyValues(), parsed)
+}
+
+func TestExport_Yaml(t *testing.T) {
+	out, err := Export(FormatYaml, trickyValues(), nil)
+	assert.NoError(t, err)
+	var parsed map[string]string
+	assert.NoError(t, yaml.Unmarshal(out, &parsed))
+	assert.Equal(t, trickyValues(), parsed)
+}
+
+func TestExport_Shell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix shell")
+	}
+	out, err := Export(FormatShell, trickyValues(), nil)
+	assert.NoError(t, err)
+
+	// source the export and print every variable null separated
+	keys := sortedKeys(trickyValues())
+	script := string(out)
+	for _, key := range keys {
+		script += "printf '%s\\0' \"$" + key + "\"\n"
+	}
+	shellOut, errShell := exec.Command("sh", "-c", script).Output()
+	assert.NoError(t, errShell)
+
+	printed := strings.Split(string(shellOut), "\x00")
+	assert.Len(t, printed, len(keys)+1)
+	for i, key := range keys {
+		assert.Equal(t, trickyValues()[key], printed[i], key)
+	}
+}
+
+func TestExport_K8sSecret(t *testing.T) {
+	t.Run("fail without a name", func(t *testing.T) {
+		_, err := Export(FormatK8sSecret, trickyValues(), nil)
+		assert.Error(t, err)
+	})
+	t.Run("export a valid secret manifest", func(t *testing.T) {
+		out, err := Export(FormatK8sSecret, trickyValues(), &Options{Name: "my-secret", Namespace: "my-namespace"})
+		assert.NoError(t, err)
+
+		var parsed KubernetesSecret
+		assert.NoError(t, yaml.Unmarshal(out, &parsed))
+		assert.Equal(t, "v1", parsed.ApiVersion)
+		assert.Equal(t, "Secret", parsed.Kind)
+		assert.Equal(t, "my-secret", parsed.Metadata.Name)
+		assert.Equal(t, "my-namespace", parsed.Metadata.Namespace)
+		assert.Equal(t, "Opaque", parsed.Type)
+
+		var keys []string
+		for key := range parsed.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		assert.Equal(t, sortedKeys(trickyValues()), keys)
+		assertKubernetesData(t, trickyValues(), parsed.Data)
+	})
+}
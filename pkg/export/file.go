@@ -0,0 +1,50 @@
+package export
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+)
+
+// FileMode is the mode of exported files, they contain plain secrets
+const FileMode os.FileMode = 0600
+
+// WriteFile writes the exported contents to the file and restricts its permissions to the current user
+// the contents are written to a restricted temporary file which replaces the file, so an existing file is never
+// readable by others while it contains the new contents, not even through a file descriptor opened before
+func WriteFile(fs afero.Fs, fileName string, contents []byte) error {
+
+	f, errCreate := afero.TempFile(fs, filepath.Dir(fileName), fmt.Sprintf(".%s.*.tmp", filepath.Base(fileName)))
+	if errCreate != nil {
+		return fmt.Errorf("could not create a temporary file for %s: %s", fileName, errCreate.Error())
+	}
+	tmpName := f.Name()
+
+	if errWrite := writeRestricted(fs, f, contents); errWrite != nil {
+		_ = f.Close()
+		_ = fs.Remove(tmpName)
+		return fmt.Errorf("could not write %s: %s", fileName, errWrite.Error())
+	}
+
+	if errClose := f.Close(); errClose != nil {
+		_ = fs.Remove(tmpName)
+		return fmt.Errorf("could not write %s: %s", fileName, errClose.Error())
+	}
+
+	if errRename := fs.Rename(tmpName, fileName); errRename != nil {
+		_ = fs.Remove(tmpName)
+		return fmt.Errorf("could not write %s: %s", fileName, errRename.Error())
+	}
+
+	return nil
+}
+
+// writeRestricted restricts the permissions of the file before the contents are written
+func writeRestricted(fs afero.Fs, f afero.File, contents []byte) error {
+	if errChmod := fs.Chmod(f.Name(), FileMode); errChmod != nil {
+		return fmt.Errorf("could not restrict permissions: %s", errChmod.Error())
+	}
+	_, errWrite := f.Write(contents)
+	return errWrite
+}
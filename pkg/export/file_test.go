@@ -0,0 +1,42 @@
+package export
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported")
+	}
+
+	fs := afero.NewOsFs()
+	fileName := filepath.Join(t.TempDir(), ".env")
+
+	t.Run("create the file with restrictive permissions", func(t *testing.T) {
+		assert.NoError(t, WriteFile(fs, fileName, []byte("a='1'\n")))
+		stat, errStat := fs.Stat(fileName)
+		assert.NoError(t, errStat)
+		assert.Equal(t, FileMode, stat.Mode().Perm())
+	})
+
+	t.Run("restrict the permissions of existing files", func(t *testing.T) {
+		assert.NoError(t, fs.Chmod(fileName, 0644))
+		assert.NoError(t, WriteFile(fs, fileName, []byte("b='2'\n")))
+		stat, errStat := fs.Stat(fileName)
+		assert.NoError(t, errStat)
+		assert.Equal(t, FileMode, stat.Mode().Perm())
+		contents, errRead := afero.ReadFile(fs, fileName)
+		assert.NoError(t, errRead)
+		assert.Equal(t, "b='2'\n", string(contents))
+	})
+
+	t.Run("leave no temporary file behind", func(t *testing.T) {
+		files, errRead := afero.ReadDir(fs, filepath.Dir(fileName))
+		assert.NoError(t, errRead)
+		assert.Len(t, files, 1)
+	})
+}
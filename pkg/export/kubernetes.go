@@ -0,0 +1,110 @@
+package export
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+)
+
+var kubernetesKeyRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+type KubernetesMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// KubernetesSecret describes a v1/Secret manifest
+type KubernetesSecret struct {
+	ApiVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   KubernetesMetadata `yaml:"metadata"`
+	Type       string             `yaml:"type"`
+	Data       map[string]string  `yaml:"data,omitempty"`
+	StringData map[string]string  `yaml:"stringData,omitempty"`
+}
+
+// KubernetesConfigMap describes a v1/ConfigMap manifest
+type KubernetesConfigMap struct {
+	ApiVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   KubernetesMetadata `yaml:"metadata"`
+	Data       map[string]string  `yaml:"data,omitempty"`
+}
+
+// NewKubernetesSecret creates an Opaque secret
+// the values are base64 encoded into data or put as plain values into stringData
+func NewKubernetesSecret(metadata KubernetesMetadata, values map[string]string, stringData bool) (*KubernetesSecret, error) {
+	if errValidate := validateKubernetesManifest(metadata, values); errValidate != nil {
+		return nil, errValidate
+	}
+	secret := &KubernetesSecret{
+		ApiVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   metadata,
+		Type:       "Opaque",
+	}
+	if stringData {
+		secret.StringData = values
+		return secret, nil
+	}
+	secret.Data = make(map[string]string)
+	for key, value := range values {
+		secret.Data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	return secret, nil
+}
+
+// NewKubernetesConfigMap creates a config map containing the plain values
+func NewKubernetesConfigMap(metadata KubernetesMetadata, values map[string]string) (*KubernetesConfigMap, error) {
+	if errValidate := validateKubernetesManifest(metadata, values); errValidate != nil {
+		return nil, errValidate
+	}
+	return &KubernetesConfigMap{
+		ApiVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata:   metadata,
+		Data:       values,
+	}, nil
+}
+
+// MarshalKubernetesManifests marshals the manifests into a multi document yaml
+func MarshalKubernetesManifests(manifests ...interface{}) ([]byte, error) {
+	var out []byte
+	for i, manifest := range manifests {
+		manifestYaml, errMarshal := marshalYaml(manifest)
+		if errMarshal != nil {
+			return nil, errMarshal
+		}
+		if i > 0 {
+			out = append(out, []byte("---\n")...)
+		}
+		out = append(out, manifestYaml...)
+	}
+	return out, nil
+}
+
+// validateKubernetesManifest checks the name and the keys of a manifest
+func validateKubernetesManifest(metadata KubernetesMetadata, values map[string]string) error {
+	if metadata.Name == "" {
+		return fmt.Errorf("a kubernetes manifest requires a name")
+	}
+	for _, key := range sortedKeys(values) {
+		if !kubernetesKeyRegex.MatchString(key) {
+			return fmt.Errorf("key %s is not a valid kubernetes data key", key)
+		}
+	}
+	return nil
+}
+
+func exportK8sSecret(values map[string]string, options *Options) ([]byte, error) {
+	secret, errSecret := NewKubernetesSecret(KubernetesMetadata{
+		Name:      options.Name,
+		Namespace: options.Namespace,
+	}, values, false)
+	if errSecret != nil {
+		return nil, errSecret
+	}
+	return marshalYaml(secret)
+}
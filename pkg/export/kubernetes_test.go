@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"io"
+	"testing"
+)
+
+func assertKubernetesData(t *testing.T, expected map[string]string, data map[string]string) {
+	assert.Len(t, data, len(expected))
+	for key, value := range expected {
+		decoded, errDecode := base64.StdEncoding.DecodeString(data[key])
+		assert.NoError(t, errDecode)
+		assert.Equal(t, value, string(decoded), key)
+	}
+}
+
+func TestNewKubernetesSecret(t *testing.T) {
+	t.Run("fail on invalid keys", func(t *testing.T) {
+		_, err := NewKubernetesSecret(KubernetesMetadata{Name: "test"}, map[string]string{"my key": "value"}, false)
+		assert.Error(t, err)
+	})
+	t.Run("encode the values as base64", func(t *testing.T) {
+		secret, err := NewKubernetesSecret(KubernetesMetadata{Name: "test"}, map[string]string{"apiPassword": "secret"}, false)
+		assert.NoError(t, err)
+		assertKubernetesData(t, map[string]string{"apiPassword": "secret"}, secret.Data)
+		assert.Nil(t, secret.StringData)
+	})
+	t.Run("put plain values into string data", func(t *testing.T) {
+		secret, err := NewKubernetesSecret(KubernetesMetadata{Name: "test"}, map[string]string{"apiPassword": "secret"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"apiPassword": "secret"}, secret.StringData)
+		assert.Nil(t, secret.Data)
+	})
+}
+
+func TestNewKubernetesConfigMap(t *testing.T) {
+	t.Run("fail without a name", func(t *testing.T) {
+		_, err := NewKubernetesConfigMap(KubernetesMetadata{}, map[string]string{"namespace": "value"})
+		assert.Error(t, err)
+	})
+	t.Run("put plain values into data", func(t *testing.T) {
+		configMap, err := NewKubernetesConfigMap(KubernetesMetadata{Name: "test"}, map[string]string{"namespace": "value"})
+		assert.NoError(t, err)
+		assert.Equal(t, "ConfigMap", configMap.Kind)
+		assert.Equal(t, map[string]string{"namespace": "value"}, configMap.Data)
+	})
+}
+
+func TestMarshalKubernetesManifests(t *testing.T) {
+	secret, _ := NewKubernetesSecret(KubernetesMetadata{Name: "test"}, map[string]string{"apiPassword": "secret"}, true)
+	configMap, _ := NewKubernetesConfigMap(KubernetesMetadata{Name: "test"}, map[string]string{"namespace": "value"})
+	out, err := MarshalKubernetesManifests(secret, configMap)
+	assert.NoError(t, err)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(out))
+	var kinds []string
+	for {
+		var manifest map[string]interface{}
+		if errDecode := decoder.Decode(&manifest); errDecode != nil {
+			assert.ErrorIs(t, errDecode, io.EOF)
+			break
+		}
+		kinds = append(kinds, manifest["kind"].(string))
+	}
+	assert.Equal(t, []string{"Secret", "ConfigMap"}, kinds)
+}
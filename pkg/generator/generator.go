@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+type Charset string
+
+const (
+	CharsetAlnum  Charset = "alnum"
+	CharsetHex    Charset = "hex"
+	CharsetBase64 Charset = "base64"
+	CharsetWords  Charset = "words"
+)
+
+// DefaultLength is the number of generated characters if no length is passed
+const DefaultLength = 32
+
+// DefaultWordCount is the number of generated words if no length is passed
+const DefaultWordCount = 10
+
+// WordSeparator joins the generated words
+const WordSeparator = "-"
+
+//go:embed words.txt
+var wordList string
+
+var words = strings.Fields(wordList)
+
+// alphabets holds the characters of the charsets, words are picked from the word list instead
+var alphabets = map[Charset]string{
+	CharsetAlnum:  "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	CharsetHex:    "0123456789abcdef",
+	CharsetBase64: "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/",
+}
+
+// patterns match values consisting of the charset only
+var patterns = map[Charset]*regexp.Regexp{
+	CharsetAlnum:  regexp.MustCompile(`^[A-Za-z0-9]*$`),
+	CharsetHex:    regexp.MustCompile(`^[0-9a-fA-F]*$`),
+	CharsetBase64: regexp.MustCompile(`^[A-Za-z0-9+/]*={0,2}$`),
+	CharsetWords:  regexp.MustCompile(`^([a-z]+(-[a-z]+)*)?$`),
+}
+
+// Charsets returns all available charsets
+func Charsets() []Charset {
+	return []Charset{CharsetAlnum, CharsetHex, CharsetBase64, CharsetWords}
+}
+
+// IsCharset returns true if the charset is known
+func IsCharset(charset string) bool {
+	_, exists := patterns[Charset(charset)]
+	return exists
+}
+
+// DefaultLengthOf returns the default length of the charset, the number of words for the words charset
+func DefaultLengthOf(charset Charset) int {
+	if charset == CharsetWords {
+		return DefaultWordCount
+	}
+	return DefaultLength
+}
+
+// Generate returns a random value using crypto/rand
+// length is the number of characters, for the words charset it is the number of words joined by a dash
+func Generate(charset Charset, length int) (string, error) {
+
+	if length < 1 {
+		return "", fmt.Errorf("the length must be at least 1")
+	}
+
+	if charset == CharsetWords {
+		picked := make([]string, length)
+		for i := range picked {
+			index, errRand := randomIndex(len(words))
+			if errRand != nil {
+				return "", errRand
+			}
+			picked[i] = words[index]
+		}
+		return strings.Join(picked, WordSeparator), nil
+	}
+
+	alphabet, exists := alphabets[charset]
+	if !exists {
+		return "", fmt.Errorf("unknown charset %s", charset)
+	}
+
+	value := make([]byte, length)
+	for i := range value {
+		index, errRand := randomIndex(len(alphabet))
+		if errRand != nil {
+			return "", errRand
+		}
+		value[i] = alphabet[index]
+	}
+	return string(value), nil
+
+}
+
+// Matches returns an error if the value contains characters outside of the charset
+func Matches(charset Charset, value string) error {
+	pattern, exists := patterns[charset]
+	if !exists {
+		return fmt.Errorf("unknown charset %s", charset)
+	}
+	if !pattern.MatchString(value) {
+		return fmt.Errorf("the value contains characters outside of the charset %s", charset)
+	}
+	return nil
+}
+
+// randomIndex returns a uniformly distributed random number in [0, max)
+func randomIndex(max int) (int, error) {
+	index, errRand := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if errRand != nil {
+		return 0, fmt.Errorf("could not generate a random value: %s", errRand.Error())
+	}
+	return int(index.Int64()), nil
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+
+	t.Run("it should generate values of the charset and length", func(t *testing.T) {
+		for _, charset := range []Charset{CharsetAlnum, CharsetHex, CharsetBase64} {
+			value, errGenerate := Generate(charset, 48)
+			assert.NoError(t, errGenerate)
+			assert.Len(t, value, 48)
+			assert.NoError(t, Matches(charset, value), charset)
+		}
+	})
+
+	t.Run("it should generate words", func(t *testing.T) {
+		value, errGenerate := Generate(CharsetWords, 6)
+		assert.NoError(t, errGenerate)
+		assert.Len(t, strings.Split(value, WordSeparator), 6)
+		assert.NoError(t, Matches(CharsetWords, value))
+	})
+
+	t.Run("it should generate different values", func(t *testing.T) {
+		first, _ := Generate(CharsetAlnum, DefaultLength)
+		second, _ := Generate(CharsetAlnum, DefaultLength)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("it should fail on invalid arguments", func(t *testing.T) {
+		_, errGenerate := Generate(CharsetHex, 0)
+		assert.Error(t, errGenerate)
+		_, errGenerate = Generate("emoji", 12)
+		assert.EqualError(t, errGenerate, "unknown charset emoji")
+	})
+
+	t.Run("it should use a word list without duplicates", func(t *testing.T) {
+		unique := make(map[string]bool)
+		for _, word := range words {
+			assert.False(t, unique[word], word)
+			unique[word] = true
+		}
+		assert.Greater(t, len(words), 512)
+	})
+
+}
+
+func TestMatches(t *testing.T) {
+	assert.NoError(t, Matches(CharsetHex, "00ffAB"))
+	assert.Error(t, Matches(CharsetHex, "00fg"))
+	assert.NoError(t, Matches(CharsetBase64, "aGVsbG8="))
+	assert.Error(t, Matches(CharsetBase64, "a=b"))
+	assert.NoError(t, Matches(CharsetWords, "correct-horse-battery"))
+	assert.Error(t, Matches(CharsetWords, "correct horse"))
+	assert.EqualError(t, Matches(CharsetAlnum, "abc-def"), "the value contains characters outside of the charset alnum")
+	assert.True(t, IsCharset("words"))
+	assert.False(t, IsCharset("emoji"))
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatDotenv Format = "dotenv"
+	FormatJson   Format = "json"
+	FormatYaml   Format = "yaml"
+)
+
+// Formats returns all the available import formats
+func Formats() []Format {
+	return []Format{FormatDotenv, FormatJson, FormatYaml}
+}
+
+// DetectFormat guesses the format by the file name
+func DetectFormat(fileName string) (Format, error) {
+	baseName := strings.ToLower(filepath.Base(fileName))
+	switch {
+	case strings.HasSuffix(baseName, ".json"):
+		return FormatJson, nil
+	case strings.HasSuffix(baseName, ".yaml"), strings.HasSuffix(baseName, ".yml"):
+		return FormatYaml, nil
+	case strings.HasPrefix(baseName, ".env"), strings.HasSuffix(baseName, ".env"):
+		return FormatDotenv, nil
+	default:
+		return "", fmt.Errorf("could not detect the format of %s, use --format %s", fileName, strings.Join(formatNames(), "|"))
+	}
+}
+
+func formatNames() []string {
+	var names []string
+	for _, format := range Formats() {
+		names = append(names, string(format))
+	}
+	return names
+}
+
+// Parse parses the file contents into a flat key value map
+// json and yaml documents must be flat objects of scalar values
+func Parse(format Format, contents []byte) (map[string]string, error) {
+	switch format {
+	case FormatDotenv:
+		values, errParse := godotenv.Parse(bytes.NewReader(contents))
+		if errParse != nil {
+			return nil, fmt.Errorf("could not parse dotenv: %s", errParse.Error())
+		}
+		return values, nil
+	case FormatJson:
+		var document map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+		if errParse := decoder.Decode(&document); errParse != nil {
+			return nil, fmt.Errorf("could not parse json: %s", errParse.Error())
+		}
+		return flatValues(document)
+	case FormatYaml:
+		var document map[string]interface{}
+		if errParse := yaml.Unmarshal(contents, &document); errParse != nil {
+			return nil, fmt.Errorf("could not parse yaml: %s", errParse.Error())
+		}
+		return flatValues(document)
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+}
+
+// flatValues converts the scalar values of the document to strings
+func flatValues(document map[string]interface{}) (map[string]string, error) {
+	values := make(map[string]string)
+	for key, value := range document {
+		switch typedValue := value.(type) {
+		case string:
+			values[key] = typedValue
+		case nil:
+			values[key] = ""
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("the value of %s is not a scalar value, only flat documents can be imported", key)
+		default:
+			values[key] = fmt.Sprintf("%v", typedValue)
+		}
+	}
+	return values, nil
+}
+
+// StripPrefix only keeps the keys starting with the prefix and removes it from the key
+func StripPrefix(values map[string]string, prefix string) map[string]string {
+	if prefix == "" {
+		return values
+	}
+	stripped := make(map[string]string)
+	for key, value := range values {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			stripped[strings.TrimPrefix(key, prefix)] = value
+		}
+	}
+	return stripped
+}
+
+func sortedKeys(values map[string]string) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
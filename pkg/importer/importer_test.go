@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     Format
+		wantErr  bool
+	}{
+		{fileName: ".env", want: FormatDotenv},
+		{fileName: "config/.env.local", want: FormatDotenv},
+		{fileName: "prod.env", want: FormatDotenv},
+		{fileName: "secrets.json", want: FormatJson},
+		{fileName: "secrets.yaml", want: FormatYaml},
+		{fileName: "secrets.YML", want: FormatYaml},
+		{fileName: "secrets.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			format, err := DetectFormat(tt.fileName)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, format)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	expected := map[string]string{"databaseHost": "localhost", "databasePort": "3306", "debug": "true", "password": "it's \"secret\""}
+	t.Run("parse dotenv", func(t *testing.T) {
+		values, err := Parse(FormatDotenv, []byte("# comment\ndatabaseHost=localhost\ndatabasePort=3306\nexport debug=true\npassword=\"it's \\\"secret\\\"\"\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+	t.Run("parse json", func(t *testing.T) {
+		values, err := Parse(FormatJson, []byte(`{"databaseHost": "localhost", "databasePort": 3306, "debug": true, "password": "it's \"secret\""}`))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+	t.Run("parse yaml", func(t *testing.T) {
+		values, err := Parse(FormatYaml, []byte("databaseHost: localhost\ndatabasePort: 3306\ndebug: true\npassword: it's \"secret\"\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+	t.Run("fail on nested documents", func(t *testing.T) {
+		_, err := Parse(FormatJson, []byte(`{"database": {"host": "localhost"}}`))
+		assert.Error(t, err)
+		_, err = Parse(FormatYaml, []byte("hosts:\n  - localhost\n"))
+		assert.Error(t, err)
+	})
+	t.Run("fail on invalid documents", func(t *testing.T) {
+		_, err := Parse(FormatJson, []byte(`{`))
+		assert.Error(t, err)
+		_, err = Parse(FormatYaml, []byte("- a\n- b\n"))
+		assert.Error(t, err)
+	})
+	t.Run("fail on unsupported formats", func(t *testing.T) {
+		_, err := Parse("xml", []byte(""))
+		assert.Error(t, err)
+	})
+}
+
+func TestStripPrefix(t *testing.T) {
+	values := map[string]string{"APP_DATABASE_HOST": "localhost", "APP_": "empty", "HOME": "/root"}
+	assert.Equal(t, map[string]string{"DATABASE_HOST": "localhost"}, StripPrefix(values, "APP_"))
+	assert.Equal(t, values, StripPrefix(values, ""))
+}
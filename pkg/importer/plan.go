@@ -0,0 +1,95 @@
+package importer
+
+import "fmt"
+
+type Action string
+
+const (
+	ActionAdd    Action = "added"
+	ActionChange Action = "changed"
+	ActionSkip   Action = "skipped"
+)
+
+// Change describes what happens to a single imported key
+type Change struct {
+	Key    string
+	Action Action
+	Reason string
+}
+
+// Plan holds the changes of an import
+type Plan struct {
+	Changes []*Change
+
+	// values holds the values which are written
+	values map[string]string
+}
+
+// ExistingValue returns the current plain value of a key and whether it exists
+type ExistingValue func(key string) (value string, exists bool, err error)
+
+// Allowed returns an error if a key can not be written with the given value
+type Allowed func(key string, value string) error
+
+// NewPlan compares the imported values with the existing ones
+// existing keys are only changed when force is set, unchanged values are skipped
+func NewPlan(values map[string]string, existing ExistingValue, allowed Allowed, force bool) (*Plan, error) {
+
+	plan := &Plan{
+		values: make(map[string]string),
+	}
+
+	for _, key := range sortedKeys(values) {
+
+		value := values[key]
+
+		if allowed != nil {
+			if errAllowed := allowed(key, value); errAllowed != nil {
+				plan.Changes = append(plan.Changes, &Change{Key: key, Action: ActionSkip, Reason: errAllowed.Error()})
+				continue
+			}
+		}
+
+		existingValue, exists, errExisting := existing(key)
+		if errExisting != nil {
+			return nil, fmt.Errorf("could not resolve the existing value of %s: %s", key, errExisting.Error())
+		}
+
+		switch {
+		case !exists:
+			plan.Changes = append(plan.Changes, &Change{Key: key, Action: ActionAdd})
+			plan.values[key] = value
+		case existingValue == value:
+			plan.Changes = append(plan.Changes, &Change{Key: key, Action: ActionSkip, Reason: "unchanged"})
+		case !force:
+			plan.Changes = append(plan.Changes, &Change{Key: key, Action: ActionSkip, Reason: "already exists, use --force to overwrite"})
+		default:
+			plan.Changes = append(plan.Changes, &Change{Key: key, Action: ActionChange})
+			plan.values[key] = value
+		}
+
+	}
+
+	return plan, nil
+}
+
+// Values returns the values which need to be written
+func (p *Plan) Values() map[string]string {
+	return p.values
+}
+
+// Count returns the amount of changes with the given action
+func (p *Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// HasWrites returns true if at least one key is added or changed
+func (p *Plan) HasWrites() bool {
+	return len(p.values) > 0
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewPlan(t *testing.T) {
+
+	existingValues := map[string]string{"unchanged": "a", "changed": "b"}
+	existing := func(key string) (string, bool, error) {
+		value, exists := existingValues[key]
+		return value, exists, nil
+	}
+	allowed := func(key string, value string) error {
+		if key == "forbidden" {
+			return fmt.Errorf("not defined in the default context")
+		}
+		if value == "invalid" {
+			return fmt.Errorf("the value does not match the pattern ^[a-z]$")
+		}
+		return nil
+	}
+	values := map[string]string{"unchanged": "a", "changed": "c", "new": "d", "forbidden": "e", "rule": "invalid"}
+
+	t.Run("skip existing keys without force", func(t *testing.T) {
+		plan, err := NewPlan(values, existing, allowed, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []*Change{
+			{Key: "changed", Action: ActionSkip, Reason: "already exists, use --force to overwrite"},
+			{Key: "forbidden", Action: ActionSkip, Reason: "not defined in the default context"},
+			{Key: "new", Action: ActionAdd},
+			{Key: "rule", Action: ActionSkip, Reason: "the value does not match the pattern ^[a-z]$"},
+			{Key: "unchanged", Action: ActionSkip, Reason: "unchanged"},
+		}, plan.Changes)
+		assert.Equal(t, map[string]string{"new": "d"}, plan.Values())
+		assert.Equal(t, 1, plan.Count(ActionAdd))
+		assert.Equal(t, 4, plan.Count(ActionSkip))
+		assert.True(t, plan.HasWrites())
+	})
+
+	t.Run("change existing keys with force", func(t *testing.T) {
+		plan, err := NewPlan(values, existing, allowed, true)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"new": "d", "changed": "c"}, plan.Values())
+		assert.Equal(t, 1, plan.Count(ActionChange))
+	})
+
+	t.Run("fail if the existing value can not be resolved", func(t *testing.T) {
+		_, err := NewPlan(values, func(key string) (string, bool, error) {
+			return "", false, fmt.Errorf("could not decode")
+		}, nil, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("no writes if nothing changed", func(t *testing.T) {
+		plan, err := NewPlan(map[string]string{"unchanged": "a"}, existing, nil, true)
+		assert.NoError(t, err)
+		assert.False(t, plan.HasWrites())
+	})
+}
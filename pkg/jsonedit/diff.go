@@ -0,0 +1,86 @@
+package jsonedit
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Editor edits a document by the path of the values, it is implemented by Document and the yaml documents of yamledit
+type Editor interface {
+	Set(path []string, value interface{}) error
+	Append(path []string, value interface{}) error
+	Delete(path []string) error
+}
+
+// ApplyChanges compares two decoded json values and applies the differences to the document
+// keys only present in before are deleted, arrays which only got new items are appended
+func (d *Document) ApplyChanges(before interface{}, after interface{}) error {
+	return ApplyChanges(d, before, after)
+}
+
+// ApplyChanges compares two decoded json values and applies the differences to the editor
+func ApplyChanges(editor Editor, before interface{}, after interface{}) error {
+	return applyChanges(editor, nil, before, after)
+}
+
+func applyChanges(d Editor, path []string, before interface{}, after interface{}) error {
+
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	beforeObject, beforeIsObject := before.(map[string]interface{})
+	afterObject, afterIsObject := after.(map[string]interface{})
+	if beforeIsObject && afterIsObject {
+		for _, key := range sortedKeys(beforeObject) {
+			if _, exists := afterObject[key]; !exists {
+				if err := d.Delete(childPath(path, key)); err != nil {
+					return err
+				}
+			}
+		}
+		for _, key := range sortedKeys(afterObject) {
+			beforeValue, exists := beforeObject[key]
+			if !exists {
+				if err := d.Set(childPath(path, key), afterObject[key]); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := applyChanges(d, childPath(path, key), beforeValue, afterObject[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	beforeArray, beforeIsArray := before.([]interface{})
+	afterArray, afterIsArray := after.([]interface{})
+	if beforeIsArray && afterIsArray && len(afterArray) > len(beforeArray) && reflect.DeepEqual(beforeArray, afterArray[:len(beforeArray)]) {
+		for _, item := range afterArray[len(beforeArray):] {
+			if err := d.Append(path, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return d.Set(path, after)
+
+}
+
+// childPath returns a copy of the path with the key appended
+func childPath(path []string, key string) []string {
+	child := make([]string, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, key)
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
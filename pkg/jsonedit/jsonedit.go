@@ -0,0 +1,230 @@
+package jsonedit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Document edits a json document in place
+// only the touched values are rewritten, the order of the keys, the indentation and unknown keys are kept
+type Document struct {
+	source []byte
+	indent string
+}
+
+// NewDocument creates a new editable document from the json source
+func NewDocument(source []byte) (*Document, error) {
+	if _, errParse := parse(source); errParse != nil {
+		return nil, errParse
+	}
+	return &Document{
+		source: append([]byte{}, source...),
+		indent: detectIndent(source),
+	}, nil
+}
+
+// Bytes returns the edited document
+func (d *Document) Bytes() []byte {
+	return d.source
+}
+
+// Set sets the value at the given path, missing parent objects are created
+func (d *Document) Set(path []string, value interface{}) error {
+
+	root, errParse := parse(d.source)
+	if errParse != nil {
+		return errParse
+	}
+
+	if len(path) == 0 {
+		d.replace(root, value)
+		return nil
+	}
+
+	current := root
+	for i, key := range path {
+		if current.kind != kindObject {
+			return fmt.Errorf("%s is not an object", strings.Join(path[:i], "."))
+		}
+		existing := current.member(key)
+		if existing == nil {
+			d.insertMember(current, key, nestedValue(path[i+1:], value))
+			return nil
+		}
+		current = existing.value
+	}
+
+	d.replace(current, value)
+	return nil
+
+}
+
+// Append appends the value to the array at the given path, the array is created if it is missing
+func (d *Document) Append(path []string, value interface{}) error {
+
+	root, errParse := parse(d.source)
+	if errParse != nil {
+		return errParse
+	}
+
+	array := root.lookup(path)
+	if array == nil {
+		return d.Set(path, []interface{}{value})
+	}
+	if array.kind != kindArray {
+		return fmt.Errorf("%s is not an array", strings.Join(path, "."))
+	}
+
+	if len(array.items) == 0 {
+		d.splice(array.start, array.end, d.wrap("[", "]", "", value, d.lineIndent(array.start)))
+	} else {
+		last := array.items[len(array.items)-1]
+		d.splice(last.end, last.end, d.separator(d.lineIndent(last.start), "", value))
+	}
+
+	return nil
+
+}
+
+// Delete removes the key at the given path, nothing happens if the key does not exist
+func (d *Document) Delete(path []string) error {
+
+	if len(path) == 0 {
+		return fmt.Errorf("can not delete the document root")
+	}
+
+	root, errParse := parse(d.source)
+	if errParse != nil {
+		return errParse
+	}
+
+	parent := root.lookup(path[:len(path)-1])
+	if parent == nil || parent.kind != kindObject {
+		return nil
+	}
+
+	for i, m := range parent.members {
+		if m.key != path[len(path)-1] {
+			continue
+		}
+		switch {
+		case len(parent.members) == 1:
+			d.splice(parent.start, parent.end, []byte("{}"))
+		case i < len(parent.members)-1:
+			d.splice(m.keyStart, parent.members[i+1].keyStart, nil)
+		default:
+			d.splice(parent.members[i-1].value.end, m.value.end, nil)
+		}
+		break
+	}
+
+	return nil
+
+}
+
+// lookup returns the node at the path or nil if it does not exist
+func (n *node) lookup(path []string) *node {
+	current := n
+	for _, key := range path {
+		if current.kind != kindObject {
+			return nil
+		}
+		existing := current.member(key)
+		if existing == nil {
+			return nil
+		}
+		current = existing.value
+	}
+	return current
+}
+
+func (d *Document) replace(target *node, value interface{}) {
+	d.splice(target.start, target.end, d.encode(value, d.lineIndent(target.start)))
+}
+
+func (d *Document) insertMember(object *node, key string, value interface{}) {
+	encodedKey, _ := json.Marshal(key)
+	if len(object.members) == 0 {
+		d.splice(object.start, object.end, d.wrap("{", "}", string(encodedKey), value, d.lineIndent(object.start)))
+		return
+	}
+	last := object.members[len(object.members)-1]
+	d.splice(last.value.end, last.value.end, d.separator(d.lineIndent(last.keyStart), string(encodedKey), value))
+}
+
+// separator returns the encoded value which is inserted after an existing sibling
+func (d *Document) separator(indent string, encodedKey string, value interface{}) []byte {
+	if d.indent == "" {
+		return append([]byte(","+keyPrefix(encodedKey, ":")), d.encode(value, "")...)
+	}
+	return append([]byte(",\n"+indent+keyPrefix(encodedKey, ": ")), d.encode(value, indent)...)
+}
+
+// wrap returns an object or array holding a single value
+func (d *Document) wrap(open string, close string, encodedKey string, value interface{}, indent string) []byte {
+	if d.indent == "" {
+		return []byte(open + keyPrefix(encodedKey, ":") + string(d.encode(value, "")) + close)
+	}
+	childIndent := indent + d.indent
+	return []byte(open + "\n" + childIndent + keyPrefix(encodedKey, ": ") + string(d.encode(value, childIndent)) + "\n" + indent + close)
+}
+
+func (d *Document) encode(value interface{}, indent string) []byte {
+	if d.indent == "" {
+		encoded, _ := json.Marshal(value)
+		return encoded
+	}
+	encoded, _ := json.MarshalIndent(value, indent, d.indent)
+	return encoded
+}
+
+func (d *Document) splice(start int, end int, replacement []byte) {
+	edited := make([]byte, 0, len(d.source)-(end-start)+len(replacement))
+	edited = append(edited, d.source[:start]...)
+	edited = append(edited, replacement...)
+	edited = append(edited, d.source[end:]...)
+	d.source = edited
+}
+
+// lineIndent returns the leading whitespace of the line containing pos
+func (d *Document) lineIndent(pos int) string {
+	lineStart := pos
+	for lineStart > 0 && d.source[lineStart-1] != '\n' {
+		lineStart--
+	}
+	lineEnd := lineStart
+	for lineEnd < len(d.source) && (d.source[lineEnd] == ' ' || d.source[lineEnd] == '\t') {
+		lineEnd++
+	}
+	return string(d.source[lineStart:lineEnd])
+}
+
+// detectIndent returns the indentation of the first indented line, empty for compact documents
+func detectIndent(source []byte) string {
+	for _, line := range strings.Split(string(source), "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	if strings.Contains(strings.TrimSpace(string(source)), "\n") {
+		return "  "
+	}
+	return ""
+}
+
+func keyPrefix(encodedKey string, colon string) string {
+	if encodedKey == "" {
+		return ""
+	}
+	return encodedKey + colon
+}
+
+// nestedValue wraps the value into objects for each key of the path
+func nestedValue(path []string, value interface{}) interface{} {
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+	return value
+}
@@ -0,0 +1,212 @@
+package jsonedit
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testDocument = `{
+    "b": "keep",
+    "a": {
+        "x": 1
+    },
+    "empty": {},
+    "list": [
+        "one"
+    ],
+    "unknown": true
+}
+`
+
+func newTestDocument(t *testing.T, source string) *Document {
+	document, errParse := NewDocument([]byte(source))
+	assert.NoError(t, errParse)
+	return document
+}
+
+func TestNewDocument(t *testing.T) {
+	_, errParse := NewDocument([]byte(`{"a": `))
+	assert.Error(t, errParse)
+	document := newTestDocument(t, testDocument)
+	assert.Equal(t, "    ", document.indent)
+	assert.Equal(t, testDocument, string(document.Bytes()))
+}
+
+func TestDocument_Set(t *testing.T) {
+
+	t.Run("replace an existing value", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"a", "x"}, 2))
+		assert.Equal(t, `{
+    "b": "keep",
+    "a": {
+        "x": 2
+    },
+    "empty": {},
+    "list": [
+        "one"
+    ],
+    "unknown": true
+}
+`, string(document.Bytes()))
+	})
+
+	t.Run("insert a key after the last member", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"a", "y"}, "new"))
+		assert.Contains(t, string(document.Bytes()), "        \"x\": 1,\n        \"y\": \"new\"\n    },")
+	})
+
+	t.Run("insert a key into an empty object", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"empty", "key"}, "value"))
+		assert.Contains(t, string(document.Bytes()), "    \"empty\": {\n        \"key\": \"value\"\n    },")
+	})
+
+	t.Run("create missing parents", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"c", "d"}, "value"))
+		assert.Contains(t, string(document.Bytes()), "    \"unknown\": true,\n    \"c\": {\n        \"d\": \"value\"\n    }\n}\n")
+	})
+
+	t.Run("fail if a parent is not an object", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.Error(t, document.Set([]string{"b", "c"}, "value"))
+	})
+
+	t.Run("edit compact documents", func(t *testing.T) {
+		document := newTestDocument(t, `{"a":{"x":1}}`)
+		assert.NoError(t, document.Set([]string{"a", "y"}, map[string]interface{}{"z": true}))
+		assert.Equal(t, `{"a":{"x":1,"y":{"z":true}}}`, string(document.Bytes()))
+	})
+
+}
+
+func TestDocument_Append(t *testing.T) {
+	document := newTestDocument(t, testDocument)
+	assert.NoError(t, document.Append([]string{"list"}, "two"))
+	assert.Contains(t, string(document.Bytes()), "    \"list\": [\n        \"one\",\n        \"two\"\n    ],")
+	assert.NoError(t, document.Append([]string{"new"}, "first"))
+	assert.Contains(t, string(document.Bytes()), "    \"new\": [\n        \"first\"\n    ]\n}")
+	assert.Error(t, document.Append([]string{"b"}, "value"))
+}
+
+func TestDocument_Delete(t *testing.T) {
+
+	t.Run("delete a member in the middle", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Delete([]string{"empty"}))
+		assert.Contains(t, string(document.Bytes()), "    },\n    \"list\": [")
+	})
+
+	t.Run("delete the last member", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Delete([]string{"unknown"}))
+		assert.Contains(t, string(document.Bytes()), "        \"one\"\n    ]\n}\n")
+	})
+
+	t.Run("delete the only member", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Delete([]string{"a", "x"}))
+		assert.Contains(t, string(document.Bytes()), "    \"a\": {},\n")
+	})
+
+	t.Run("ignore missing keys", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Delete([]string{"missing", "key"}))
+		assert.Equal(t, testDocument, string(document.Bytes()))
+	})
+
+}
+
+func TestDocument_ApplyChanges(t *testing.T) {
+
+	decode := func(source string) interface{} {
+		var decoded interface{}
+		assert.NoError(t, json.Unmarshal([]byte(source), &decoded))
+		return decoded
+	}
+
+	document := newTestDocument(t, testDocument)
+	before := decode(`{"b": "keep", "a": {"x": 1}, "list": ["one"]}`)
+	after := decode(`{"b": "changed", "a": {"x": 1, "y": 2}, "list": ["one", "two"]}`)
+
+	assert.NoError(t, document.ApplyChanges(before, after))
+	assert.Equal(t, `{
+    "b": "changed",
+    "a": {
+        "x": 1,
+        "y": 2
+    },
+    "empty": {},
+    "list": [
+        "one",
+        "two"
+    ],
+    "unknown": true
+}
+`, string(document.Bytes()))
+
+	assert.NoError(t, document.ApplyChanges(after, decode(`{"b": "changed", "list": ["two"]}`)))
+	assert.Equal(t, `{
+    "b": "changed",
+    "empty": {},
+    "list": [
+        "two"
+    ],
+    "unknown": true
+}
+`, string(document.Bytes()))
+
+}
+
+func TestMerge(t *testing.T) {
+
+	decode := func(source string) interface{} {
+		var decoded interface{}
+		assert.NoError(t, json.Unmarshal([]byte(source), &decoded))
+		return decoded
+	}
+
+	base := decode(`{"a": {"x": 1}, "b": "keep", "list": ["one"], "removed": true}`)
+
+	t.Run("merge independent changes", func(t *testing.T) {
+		ours := decode(`{"a": {"x": 1, "y": 2}, "b": "keep", "list": ["one"]}`)
+		theirs := decode(`{"a": {"x": 1, "z": 3}, "b": "changed", "list": ["one"], "removed": true, "c": {"new": 1}}`)
+		merged, conflicts := Merge(base, ours, theirs)
+		assert.Len(t, conflicts, 0)
+		assert.Equal(t, decode(`{"a": {"x": 1, "y": 2, "z": 3}, "b": "changed", "list": ["one"], "c": {"new": 1}}`), merged)
+	})
+
+	t.Run("accept equal changes", func(t *testing.T) {
+		changed := decode(`{"a": {"x": 2}, "b": "keep", "list": ["one", "two"]}`)
+		merged, conflicts := Merge(base, changed, changed)
+		assert.Len(t, conflicts, 0)
+		assert.Equal(t, changed, merged)
+	})
+
+	t.Run("report conflicting changes", func(t *testing.T) {
+		ours := decode(`{"a": {"x": 2}, "b": "keep", "list": ["one"], "removed": false}`)
+		theirs := decode(`{"a": {"x": 3}, "b": "keep", "list": ["one"]}`)
+		_, conflicts := Merge(base, ours, theirs)
+		assert.Equal(t, [][]string{{"a", "x"}, {"removed"}}, conflicts)
+	})
+
+	t.Run("merge arrays as sets", func(t *testing.T) {
+		ours := decode(`{"a": {"x": 1}, "b": "keep", "list": ["one", "two", "both"], "removed": true}`)
+		theirs := decode(`{"a": {"x": 1}, "b": "keep", "list": ["three", "both"], "removed": true}`)
+		merged, conflicts := Merge(base, ours, theirs)
+		assert.Len(t, conflicts, 0)
+		assert.Equal(t, decode(`{"a": {"x": 1}, "b": "keep", "list": ["two", "both", "three"], "removed": true}`), merged)
+	})
+
+	t.Run("merge objects added on both sides", func(t *testing.T) {
+		ours := decode(`{"a": {"x": 1}, "c": {"y": 1}}`)
+		theirs := decode(`{"a": {"x": 1}, "c": {"z": 1}}`)
+		merged, conflicts := Merge(decode(`{"a": {"x": 1}}`), ours, theirs)
+		assert.Len(t, conflicts, 0)
+		assert.Equal(t, decode(`{"a": {"x": 1}, "c": {"y": 1, "z": 1}}`), merged)
+	})
+
+}
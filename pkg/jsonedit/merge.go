@@ -0,0 +1,108 @@
+package jsonedit
+
+import (
+	"reflect"
+	"sort"
+)
+
+// mergeValue is a decoded json value which may not exist in the document
+type mergeValue struct {
+	value  interface{}
+	exists bool
+}
+
+// Merge merges the changes of ours and theirs, both made on top of base, into one decoded json value
+// objects are merged key by key, arrays are merged as sets, all other values conflict if both sides changed them differently
+// the paths of the conflicts are returned, the conflicting values keep our value
+func Merge(base interface{}, ours interface{}, theirs interface{}) (interface{}, [][]string) {
+	merged, conflicts := mergeValues(nil, mergeValue{base, true}, mergeValue{ours, true}, mergeValue{theirs, true})
+	return merged.value, conflicts
+}
+
+func mergeValues(path []string, base mergeValue, ours mergeValue, theirs mergeValue) (mergeValue, [][]string) {
+
+	if reflect.DeepEqual(ours, base) {
+		return theirs, nil
+	}
+	if reflect.DeepEqual(theirs, base) || reflect.DeepEqual(ours, theirs) {
+		return ours, nil
+	}
+
+	baseObject, baseIsObject := base.value.(map[string]interface{})
+	oursObject, oursIsObject := ours.value.(map[string]interface{})
+	theirsObject, theirsIsObject := theirs.value.(map[string]interface{})
+	if oursIsObject && theirsIsObject && (baseIsObject || !base.exists) {
+
+		var conflicts [][]string
+		merged := make(map[string]interface{})
+
+		for _, key := range mergeKeys(baseObject, oursObject, theirsObject) {
+			value, keyConflicts := mergeValues(childPath(path, key), objectValue(baseObject, key), objectValue(oursObject, key), objectValue(theirsObject, key))
+			conflicts = append(conflicts, keyConflicts...)
+			if value.exists {
+				merged[key] = value.value
+			}
+		}
+
+		return mergeValue{merged, true}, conflicts
+
+	}
+
+	baseArray, baseIsArray := base.value.([]interface{})
+	oursArray, oursIsArray := ours.value.([]interface{})
+	theirsArray, theirsIsArray := theirs.value.([]interface{})
+	if oursIsArray && theirsIsArray && (baseIsArray || !base.exists) {
+		return mergeValue{mergeArrays(baseArray, oursArray, theirsArray), true}, nil
+	}
+
+	return ours, [][]string{path}
+
+}
+
+// mergeArrays keeps the items of ours which theirs did not remove and appends the items added by theirs
+func mergeArrays(base []interface{}, ours []interface{}, theirs []interface{}) []interface{} {
+	merged := []interface{}{}
+	for _, item := range ours {
+		if containsValue(base, item) && !containsValue(theirs, item) {
+			continue
+		}
+		merged = append(merged, item)
+	}
+	for _, item := range theirs {
+		if containsValue(base, item) || containsValue(merged, item) {
+			continue
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, existing := range values {
+		if reflect.DeepEqual(existing, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func objectValue(object map[string]interface{}, key string) mergeValue {
+	value, exists := object[key]
+	return mergeValue{value, exists}
+}
+
+// mergeKeys returns the sorted keys of all objects
+func mergeKeys(objects ...map[string]interface{}) []string {
+	unique := make(map[string]bool)
+	for _, object := range objects {
+		for key := range object {
+			unique[key] = true
+		}
+	}
+	var keys []string
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
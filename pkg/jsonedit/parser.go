@@ -0,0 +1,167 @@
+package jsonedit
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type nodeKind int
+
+const (
+	kindObject nodeKind = iota
+	kindArray
+	kindScalar
+)
+
+// node is a json value with its position in the source
+type node struct {
+	kind    nodeKind
+	start   int
+	end     int
+	members []*member
+	items   []*node
+}
+
+// member is a key value pair of an object
+type member struct {
+	key      string
+	keyStart int
+	value    *node
+}
+
+// parser reads the positions of all values, the values itself are validated by encoding/json
+type parser struct {
+	source []byte
+	pos    int
+}
+
+func parse(source []byte) (*node, error) {
+	if !json.Valid(source) {
+		return nil, fmt.Errorf("the document is not valid json")
+	}
+	p := &parser{source: source}
+	return p.parseValue()
+}
+
+func (p *parser) skipWhitespace() {
+	for p.pos < len(p.source) {
+		switch p.source[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) parseValue() (*node, error) {
+	p.skipWhitespace()
+	if p.pos >= len(p.source) {
+		return nil, fmt.Errorf("unexpected end of document")
+	}
+	switch p.source[p.pos] {
+	case '{':
+		return p.parseObject()
+	case '[':
+		return p.parseArray()
+	case '"':
+		start := p.pos
+		if _, err := p.parseString(); err != nil {
+			return nil, err
+		}
+		return &node{kind: kindScalar, start: start, end: p.pos}, nil
+	default:
+		start := p.pos
+		for p.pos < len(p.source) {
+			c := p.source[p.pos]
+			if c == ',' || c == '}' || c == ']' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+				break
+			}
+			p.pos++
+		}
+		return &node{kind: kindScalar, start: start, end: p.pos}, nil
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.source) {
+		switch p.source[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			var value string
+			if err := json.Unmarshal(p.source[start:p.pos], &value); err != nil {
+				return "", fmt.Errorf("invalid string at %d: %s", start, err.Error())
+			}
+			return value, nil
+		default:
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string at %d", start)
+}
+
+func (p *parser) parseObject() (*node, error) {
+	object := &node{kind: kindObject, start: p.pos}
+	p.pos++
+	for {
+		p.skipWhitespace()
+		if p.source[p.pos] == '}' {
+			p.pos++
+			object.end = p.pos
+			return object, nil
+		}
+		if p.source[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		keyStart := p.pos
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		// skip the colon
+		p.pos++
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		object.members = append(object.members, &member{key: key, keyStart: keyStart, value: value})
+	}
+}
+
+func (p *parser) parseArray() (*node, error) {
+	array := &node{kind: kindArray, start: p.pos}
+	p.pos++
+	for {
+		p.skipWhitespace()
+		if p.source[p.pos] == ']' {
+			p.pos++
+			array.end = p.pos
+			return array, nil
+		}
+		if p.source[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array.items = append(array.items, item)
+	}
+}
+
+// member returns the member of the object with the given key or nil
+func (n *node) member(key string) *member {
+	for _, m := range n.members {
+		if m.key == key {
+			return m
+		}
+	}
+	return nil
+}
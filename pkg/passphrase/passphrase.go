@@ -0,0 +1,150 @@
+package passphrase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/armor"
+	"golang.org/x/crypto/scrypt"
+	"io"
+)
+
+// BlockType is the armor type of the token
+const BlockType = "GIT SECRETS GLOBAL SECRET"
+
+const headerName = "Name"
+
+// tokenVersion is the first byte of the payload, it allows to change the format later on
+const tokenVersion = 1
+
+const saltSize = 16
+const keySize = 32
+
+// MinLength is the minimum number of characters of a passphrase
+const MinLength = 12
+
+// Params configures the scrypt key derivation, the params are stored in the token
+type Params struct {
+	LogN uint8
+	R    uint32
+	P    uint32
+}
+
+// DefaultParams are the recommended scrypt params for interactive logins
+var DefaultParams = Params{LogN: 15, R: 8, P: 1}
+
+var (
+	// ErrDamaged is returned if the token has been changed while it was copied, e.g. a line is missing
+	ErrDamaged = armor.ErrDamaged
+
+	// ErrWrongPassphrase is returned if the token can not be decrypted using the passphrase
+	ErrWrongPassphrase = errors.New("the passphrase is wrong")
+)
+
+// Wrap encrypts the global secret using a key derived from the passphrase and returns an armored token
+// the name is stored in plain text but authenticated, so it can not be changed without breaking the token
+func Wrap(name string, secret string, passphrase string, params Params) (string, error) {
+
+	if len(passphrase) < MinLength {
+		return "", fmt.Errorf("the passphrase must have at least %d characters", MinLength)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, errSalt := io.ReadFull(rand.Reader, salt); errSalt != nil {
+		return "", fmt.Errorf("could not create salt: %s", errSalt.Error())
+	}
+
+	gcm, errGcm := newGcm(passphrase, salt, params)
+	if errGcm != nil {
+		return "", errGcm
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, errNonce := io.ReadFull(rand.Reader, nonce); errNonce != nil {
+		return "", fmt.Errorf("could not create nonce: %s", errNonce.Error())
+	}
+
+	// version | logN | r | p | salt | nonce | sealed secret
+	payload := []byte{tokenVersion, params.LogN}
+	payload = binary.BigEndian.AppendUint32(payload, params.R)
+	payload = binary.BigEndian.AppendUint32(payload, params.P)
+	payload = append(payload, salt...)
+	payload = append(payload, nonce...)
+	payload = gcm.Seal(payload, nonce, []byte(secret), []byte(name))
+
+	return armor.Encode(&armor.Block{
+		Type:    BlockType,
+		Headers: []armor.Header{{Key: headerName, Value: name}},
+		Payload: payload,
+	}), nil
+}
+
+// Unwrap verifies the checksum of the armored token and decrypts the global secret using the passphrase
+// leading whitespace and text around the armor are ignored, so the token can be pasted from a chat or an email
+func Unwrap(token string, passphrase string) (name string, secret string, err error) {
+
+	blocks, errDecode := armor.Decode(token, BlockType)
+	if errDecode != nil {
+		return "", "", errDecode
+	}
+
+	name = blocks[0].Get(headerName)
+	if name == "" {
+		return "", "", fmt.Errorf("%w: the %s header is missing", ErrDamaged, headerName)
+	}
+	payload := blocks[0].Payload
+
+	// version (1) + logN (1) + r (4) + p (4) + salt
+	headerSize := 10 + saltSize
+	if len(payload) < headerSize || payload[0] != tokenVersion {
+		return "", "", fmt.Errorf("%w: unsupported token version", ErrDamaged)
+	}
+
+	params := Params{
+		LogN: payload[1],
+		R:    binary.BigEndian.Uint32(payload[2:6]),
+		P:    binary.BigEndian.Uint32(payload[6:10]),
+	}
+	salt := payload[10:headerSize]
+
+	gcm, errGcm := newGcm(passphrase, salt, params)
+	if errGcm != nil {
+		return "", "", errGcm
+	}
+
+	sealed := payload[headerSize:]
+	if len(sealed) < gcm.NonceSize()+gcm.Overhead() {
+		return "", "", fmt.Errorf("%w: the payload is too short", ErrDamaged)
+	}
+
+	plain, errOpen := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(name))
+	if errOpen != nil {
+		return "", "", ErrWrongPassphrase
+	}
+
+	return name, string(plain), nil
+}
+
+// newGcm derives the key from the passphrase using scrypt
+func newGcm(passphrase string, salt []byte, params Params) (cipher.AEAD, error) {
+
+	// the params are read from the token, large values would allocate gigabytes of memory
+	if params.LogN < 1 || params.LogN > 20 || params.R < 1 || params.R > 32 || params.P < 1 || params.P > 16 {
+		return nil, fmt.Errorf("%w: invalid scrypt params", ErrDamaged)
+	}
+
+	key, errKey := scrypt.Key([]byte(passphrase), salt, 1<<params.LogN, int(params.R), int(params.P), keySize)
+	if errKey != nil {
+		return nil, fmt.Errorf("could not derive the key from the passphrase: %s", errKey.Error())
+	}
+
+	block, errCipher := aes.NewCipher(key)
+	if errCipher != nil {
+		return nil, fmt.Errorf("could not create cipher instance: %s", errCipher.Error())
+	}
+
+	return cipher.NewGCM(block)
+}
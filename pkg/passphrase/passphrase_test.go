@@ -0,0 +1,89 @@
+package passphrase
+
+import (
+	"github.com/benammann/git-secrets/pkg/armor"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// testParams keeps the tests fast, the cli uses DefaultParams
+var testParams = Params{LogN: 10, R: 8, P: 1}
+
+const testSecret = "eeSaoghoh8oi9leed7hai4looK3jae1N"
+const testPassphrase = "correct horse battery staple"
+
+func TestWrap(t *testing.T) {
+
+	t.Run("it should be unwrapped using the same passphrase", func(t *testing.T) {
+		token, errWrap := Wrap("gitSecretsTest", testSecret, testPassphrase, testParams)
+		assert.NoError(t, errWrap)
+		assert.True(t, strings.HasPrefix(token, armor.Begin(BlockType)+"\nName: gitSecretsTest\nChecksum: "))
+		assert.True(t, strings.HasSuffix(token, armor.End(BlockType)+"\n"))
+		assert.NotContains(t, token, testSecret)
+
+		name, secret, errUnwrap := Unwrap(token, testPassphrase)
+		assert.NoError(t, errUnwrap)
+		assert.Equal(t, "gitSecretsTest", name)
+		assert.Equal(t, testSecret, secret)
+	})
+
+	t.Run("it should use a new salt for every token", func(t *testing.T) {
+		first, _ := Wrap("gitSecretsTest", testSecret, testPassphrase, testParams)
+		second, _ := Wrap("gitSecretsTest", testSecret, testPassphrase, testParams)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("it should reject short passphrases", func(t *testing.T) {
+		_, errWrap := Wrap("gitSecretsTest", testSecret, "short", testParams)
+		assert.EqualError(t, errWrap, "the passphrase must have at least 12 characters")
+	})
+
+}
+
+func TestUnwrap(t *testing.T) {
+
+	token, errWrap := Wrap("gitSecretsTest", testSecret, testPassphrase, testParams)
+	assert.NoError(t, errWrap)
+	lines := strings.Split(token, "\n")
+
+	t.Run("it should ignore indentation and text around the token", func(t *testing.T) {
+		pasted := "here is the key:\r\n"
+		for _, line := range lines {
+			pasted += "  " + line + "\r\n"
+		}
+		pasted += "see you tomorrow"
+		name, secret, errUnwrap := Unwrap(pasted, testPassphrase)
+		assert.NoError(t, errUnwrap)
+		assert.Equal(t, "gitSecretsTest", name)
+		assert.Equal(t, testSecret, secret)
+	})
+
+	t.Run("it should fail on a wrong passphrase", func(t *testing.T) {
+		_, _, errUnwrap := Unwrap(token, "wrong horse battery staple")
+		assert.ErrorIs(t, errUnwrap, ErrWrongPassphrase)
+	})
+
+	t.Run("it should detect a missing line", func(t *testing.T) {
+		damaged := append(append([]string{}, lines[:4]...), lines[5:]...)
+		_, _, errUnwrap := Unwrap(strings.Join(damaged, "\n"), testPassphrase)
+		assert.ErrorIs(t, errUnwrap, ErrDamaged)
+	})
+
+	t.Run("it should detect a changed name", func(t *testing.T) {
+		renamed := strings.Replace(token, "Name: gitSecretsTest", "Name: otherSecret", 1)
+		_, _, errUnwrap := Unwrap(renamed, testPassphrase)
+		assert.EqualError(t, errUnwrap, "the token is damaged: the checksum does not match")
+	})
+
+	t.Run("it should detect a missing end", func(t *testing.T) {
+		_, _, errUnwrap := Unwrap(strings.Replace(token, armor.End(BlockType), "", 1), testPassphrase)
+		assert.ErrorIs(t, errUnwrap, ErrDamaged)
+	})
+
+	t.Run("it should fail if there is no token", func(t *testing.T) {
+		_, _, errUnwrap := Unwrap("eeSaoghoh8oi9leed7hai4looK3jae1N", testPassphrase)
+		assert.EqualError(t, errUnwrap, "no token found, it starts with "+armor.Begin(BlockType))
+	})
+
+}
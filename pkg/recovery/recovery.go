@@ -0,0 +1,168 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/armor"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/benammann/git-secrets/pkg/shamir"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BlockType is the armor type of a recovery share
+const BlockType = "GIT SECRETS RECOVERY SHARE"
+
+const (
+	headerName        = "Name"
+	headerShare       = "Share"
+	headerThreshold   = "Threshold"
+	headerSplit       = "Split"
+	headerFingerprint = "Fingerprint"
+)
+
+// splitIdSize is the number of random bytes identifying the shares of one split
+const splitIdSize = 4
+
+// Share is a single decoded recovery share
+type Share struct {
+
+	// Name is the name of the global secret
+	Name string
+
+	// Index is the number of the share starting at 1, Shares is the number of created shares
+	Index  int
+	Shares int
+
+	// Threshold is the number of shares needed to rebuild the secret
+	Threshold int
+
+	// SplitId identifies the shares which have been created together
+	SplitId string
+
+	// Fingerprint is the key fingerprint of the secret, it is used to verify the rebuilt secret
+	Fingerprint string
+
+	// data holds the shamir share
+	data []byte
+}
+
+// Split splits the global secret into armored shares, any threshold of them can rebuild the secret
+func Split(name string, secret string, shares int, threshold int) ([]string, error) {
+
+	parts, errSplit := shamir.Split([]byte(secret), shares, threshold)
+	if errSplit != nil {
+		return nil, errSplit
+	}
+
+	splitId := make([]byte, splitIdSize)
+	if _, errRand := rand.Read(splitId); errRand != nil {
+		return nil, fmt.Errorf("could not create split id: %s", errRand.Error())
+	}
+
+	fingerprint := encryption.KeyFingerprint([]byte(secret))
+
+	var armored []string
+	for i, part := range parts {
+		armored = append(armored, armor.Encode(&armor.Block{
+			Type: BlockType,
+			Headers: []armor.Header{
+				{Key: headerName, Value: name},
+				{Key: headerShare, Value: fmt.Sprintf("%d of %d", i+1, shares)},
+				{Key: headerThreshold, Value: strconv.Itoa(threshold)},
+				{Key: headerSplit, Value: hex.EncodeToString(splitId)},
+				{Key: headerFingerprint, Value: fingerprint},
+			},
+			Payload: part,
+		}))
+	}
+
+	return armored, nil
+}
+
+// Parse returns all shares found in the text, the checksum of each share is verified
+func Parse(text string) ([]*Share, error) {
+
+	blocks, errDecode := armor.Decode(text, BlockType)
+	if errDecode != nil {
+		return nil, errDecode
+	}
+
+	var shares []*Share
+	for _, block := range blocks {
+		share := &Share{
+			Name:        block.Get(headerName),
+			SplitId:     block.Get(headerSplit),
+			Fingerprint: block.Get(headerFingerprint),
+			data:        block.Payload,
+		}
+		if _, errScan := fmt.Sscanf(block.Get(headerShare), "%d of %d", &share.Index, &share.Shares); errScan != nil {
+			return nil, fmt.Errorf("%w: invalid %s header %s", armor.ErrDamaged, headerShare, block.Get(headerShare))
+		}
+		threshold, errThreshold := strconv.Atoi(block.Get(headerThreshold))
+		if errThreshold != nil {
+			return nil, fmt.Errorf("%w: invalid %s header %s", armor.ErrDamaged, headerThreshold, block.Get(headerThreshold))
+		}
+		share.Threshold = threshold
+		if share.Name == "" || share.SplitId == "" || share.Fingerprint == "" {
+			return nil, fmt.Errorf("%w: share %d misses a header", armor.ErrDamaged, share.Index)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// Combine rebuilds the global secret from the shares
+// shares of different splits, duplicates and too few shares are rejected, the rebuilt secret is verified using the key fingerprint
+func Combine(shares []*Share) (name string, secret string, err error) {
+
+	if len(shares) == 0 {
+		return "", "", fmt.Errorf("no shares passed")
+	}
+
+	first := shares[0]
+	byIndex := make(map[int]*Share)
+	for _, share := range shares {
+		if share.SplitId != first.SplitId || share.Name != first.Name || share.Fingerprint != first.Fingerprint || share.Threshold != first.Threshold {
+			return "", "", fmt.Errorf("the shares belong to different splits: share %d of %s (split %s) and share %d of %s (split %s)", first.Index, first.Name, first.SplitId, share.Index, share.Name, share.SplitId)
+		}
+		byIndex[share.Index] = share
+	}
+
+	if len(byIndex) < first.Threshold {
+		return "", "", fmt.Errorf("%d of %d required shares passed (%s)", len(byIndex), first.Threshold, strings.Join(indexNames(byIndex), ", "))
+	}
+
+	var parts [][]byte
+	for _, share := range byIndex {
+		parts = append(parts, share.data)
+	}
+
+	combined, errCombine := shamir.Combine(parts)
+	if errCombine != nil {
+		return "", "", fmt.Errorf("could not combine the shares: %s", errCombine.Error())
+	}
+
+	if encryption.KeyFingerprint(combined) != first.Fingerprint {
+		return "", "", fmt.Errorf("the combined secret does not match the fingerprint %s, at least one share is invalid", first.Fingerprint)
+	}
+
+	return first.Name, string(combined), nil
+}
+
+// indexNames returns the sorted share numbers
+func indexNames(byIndex map[int]*Share) []string {
+	var indexes []int
+	for index := range byIndex {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	var names []string
+	for _, index := range indexes {
+		names = append(names, fmt.Sprintf("share %d", index))
+	}
+	return names
+}
@@ -0,0 +1,62 @@
+package recovery
+
+import (
+	"github.com/benammann/git-secrets/pkg/armor"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testSecret = "eeSaoghoh8oi9leed7hai4looK3jae1N"
+
+func TestSplit(t *testing.T) {
+
+	armored, errSplit := Split("gitSecretsTest", testSecret, 5, 3)
+	assert.NoError(t, errSplit)
+	assert.Len(t, armored, 5)
+	assert.Contains(t, armored[1], "Name: gitSecretsTest\nShare: 2 of 5\nThreshold: 3\nSplit: ")
+	for _, share := range armored {
+		assert.NotContains(t, share, testSecret)
+	}
+
+	t.Run("any threshold of shares should rebuild the secret", func(t *testing.T) {
+		shares, errParse := Parse(armored[4] + "\n" + armored[0] + "\n" + armored[2])
+		assert.NoError(t, errParse)
+		assert.Len(t, shares, 3)
+		assert.Equal(t, 5, shares[0].Index)
+		assert.Equal(t, 5, shares[0].Shares)
+		assert.Equal(t, 3, shares[0].Threshold)
+		name, secret, errCombine := Combine(shares)
+		assert.NoError(t, errCombine)
+		assert.Equal(t, "gitSecretsTest", name)
+		assert.Equal(t, testSecret, secret)
+	})
+
+	t.Run("it should fail if less than threshold shares are passed", func(t *testing.T) {
+		shares, _ := Parse(armored[3] + armored[1] + armored[1])
+		_, _, errCombine := Combine(shares)
+		assert.EqualError(t, errCombine, "2 of 3 required shares passed (share 2, share 4)")
+	})
+
+	t.Run("it should reject shares of different splits", func(t *testing.T) {
+		other, _ := Split("gitSecretsTest", testSecret, 5, 3)
+		shares, _ := Parse(armored[0] + armored[1] + other[2])
+		_, _, errCombine := Combine(shares)
+		assert.Error(t, errCombine)
+		assert.Contains(t, errCombine.Error(), "the shares belong to different splits")
+	})
+
+	t.Run("it should detect a damaged share", func(t *testing.T) {
+		lines := strings.Split(armored[0], "\n")
+		_, errParse := Parse(strings.Join(append(lines[:7], lines[8:]...), "\n"))
+		assert.ErrorIs(t, errParse, armor.ErrDamaged)
+	})
+
+	t.Run("it should verify the combined secret using the fingerprint", func(t *testing.T) {
+		shares, _ := Parse(armored[0] + armored[1] + armored[2])
+		shares[1].data[0] ^= 1
+		_, _, errCombine := Combine(shares)
+		assert.EqualError(t, errCombine, "the combined secret does not match the fingerprint "+shares[0].Fingerprint+", at least one share is invalid")
+	})
+
+}
@@ -32,8 +32,8 @@ func NewRenderingEngine(repository *config_generic.Repository, fsIn afero.Fs, fs
 	}
 }
 
-func (e *RenderingEngine) createTemplate(fileIn string) (*template.Template, error)  {
-	return createTemplate(e.fsIn, fileIn)
+func (e *RenderingEngine) createTemplate(fileToRender *config_generic.FileToRender) (*template.Template, error)  {
+	return createTemplate(e.fsIn, fileToRender.FileIn, fileToRender.GetPartials())
 }
 
 // CreateRenderingContext creates the context which is used in the templates
@@ -101,8 +101,13 @@ func (e *RenderingEngine) ExecuteTemplate(fileToRender *config_generic.FileToRen
 		return nil, fmt.Errorf("could not create rendering context: %s", err.Error())
 	}
 
+	// kubernetes manifests are rendered natively without a template
+	if fileToRender.Kubernetes != nil {
+		return usedContext, executeKubernetesManifest(fileToRender.Kubernetes, usedContext, writer)
+	}
+
 	// create the template and execute
-	tpl, errTpl := e.createTemplate(fileToRender.FileIn)
+	tpl, errTpl := e.createTemplate(fileToRender)
 	if errTpl != nil {
 		return nil, fmt.Errorf("error while reading template %s: %s", fileToRender.FileIn, errTpl.Error())
 	}
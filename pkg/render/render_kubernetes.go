@@ -0,0 +1,122 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/export"
+	"io"
+	"text/template"
+)
+
+// executeKubernetesManifest renders the v1/Secret and v1/ConfigMap of the manifest without a template file
+func executeKubernetesManifest(manifest *config_generic.KubernetesManifest, renderingContext *RenderingContext, writer io.Writer) error {
+
+	metadata, errMetadata := renderKubernetesMetadata(manifest, renderingContext)
+	if errMetadata != nil {
+		return errMetadata
+	}
+
+	var manifests []interface{}
+
+	if manifest.RendersKind(config_generic.KubernetesKindSecret) {
+		secrets, errFilter := filterValues(renderingContext.Secrets, manifest.SecretKeys, "secret")
+		if errFilter != nil {
+			return errFilter
+		}
+		secret, errSecret := export.NewKubernetesSecret(metadata, secrets, manifest.StringData)
+		if errSecret != nil {
+			return fmt.Errorf("could not create secret: %s", errSecret.Error())
+		}
+		manifests = append(manifests, secret)
+	}
+
+	if manifest.RendersKind(config_generic.KubernetesKindConfigMap) {
+		configs, errFilter := filterValues(renderingContext.Configs, manifest.ConfigKeys, "config")
+		if errFilter != nil {
+			return errFilter
+		}
+		configMap, errConfigMap := export.NewKubernetesConfigMap(metadata, configs)
+		if errConfigMap != nil {
+			return fmt.Errorf("could not create config map: %s", errConfigMap.Error())
+		}
+		manifests = append(manifests, configMap)
+	}
+
+	manifestsYaml, errMarshal := export.MarshalKubernetesManifests(manifests...)
+	if errMarshal != nil {
+		return errMarshal
+	}
+
+	_, errWrite := writer.Write(manifestsYaml)
+	return errWrite
+}
+
+// renderKubernetesMetadata renders name, namespace, labels and annotations as templates
+func renderKubernetesMetadata(manifest *config_generic.KubernetesManifest, renderingContext *RenderingContext) (export.KubernetesMetadata, error) {
+
+	var metadata export.KubernetesMetadata
+	var errRender error
+
+	if metadata.Name, errRender = renderMetadataValue("name", manifest.Name, renderingContext); errRender != nil {
+		return metadata, errRender
+	}
+
+	if metadata.Namespace, errRender = renderMetadataValue("namespace", manifest.Namespace, renderingContext); errRender != nil {
+		return metadata, errRender
+	}
+
+	renderMap := func(field string, values map[string]string) (map[string]string, error) {
+		if len(values) == 0 {
+			return nil, nil
+		}
+		rendered := make(map[string]string)
+		for key, value := range values {
+			renderedValue, errValue := renderMetadataValue(fmt.Sprintf("%s.%s", field, key), value, renderingContext)
+			if errValue != nil {
+				return nil, errValue
+			}
+			rendered[key] = renderedValue
+		}
+		return rendered, nil
+	}
+
+	if metadata.Labels, errRender = renderMap("labels", manifest.Labels); errRender != nil {
+		return metadata, errRender
+	}
+
+	if metadata.Annotations, errRender = renderMap("annotations", manifest.Annotations); errRender != nil {
+		return metadata, errRender
+	}
+
+	return metadata, nil
+}
+
+// renderMetadataValue renders a single metadata value as text template
+func renderMetadataValue(field string, value string, renderingContext *RenderingContext) (string, error) {
+	tpl, errParse := template.New(field).Option("missingkey=error").Funcs(template.FuncMap(getTemplateFunctions())).Parse(value)
+	if errParse != nil {
+		return "", fmt.Errorf("could not parse metadata.%s: %s", field, errParse.Error())
+	}
+	var out bytes.Buffer
+	if errExecute := tpl.Execute(&out, renderingContext); errExecute != nil {
+		return "", fmt.Errorf("could not render metadata.%s: %s", field, errExecute.Error())
+	}
+	return out.String(), nil
+}
+
+// filterValues only returns the requested keys, all values if keys is empty
+func filterValues(values map[string]string, keys []string, kind string) (map[string]string, error) {
+	if len(keys) == 0 {
+		return values, nil
+	}
+	filtered := make(map[string]string)
+	for _, key := range keys {
+		value, exists := values[key]
+		if !exists {
+			return nil, fmt.Errorf("the %s %s does not exist", kind, key)
+		}
+		filtered[key] = value
+	}
+	return filtered, nil
+}
@@ -0,0 +1,153 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"io"
+	"testing"
+)
+
+type testKubernetesManifest struct {
+	ApiVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name        string            `yaml:"name"`
+		Namespace   string            `yaml:"namespace"`
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+func decodeKubernetesManifests(t *testing.T, manifestsYaml []byte) (manifests []*testKubernetesManifest) {
+	decoder := yaml.NewDecoder(bytes.NewReader(manifestsYaml))
+	decoder.KnownFields(true)
+	for {
+		var manifest testKubernetesManifest
+		if errDecode := decoder.Decode(&manifest); errDecode != nil {
+			assert.ErrorIs(t, errDecode, io.EOF)
+			return manifests
+		}
+		manifests = append(manifests, &manifest)
+	}
+}
+
+func newTestRenderingContext() *RenderingContext {
+	return &RenderingContext{
+		ContextName: "prod",
+		Secrets:     config_generic.SecretsMap{"apiPassword": "secret: value", "apiToken": "token"},
+		Configs:     config_generic.ConfigMap{"namespace": "prod-namespace", "logLevel": "debug"},
+	}
+}
+
+func TestExecuteKubernetesManifest(t *testing.T) {
+
+	t.Run("render a secret and a config map", func(t *testing.T) {
+		var out bytes.Buffer
+		err := executeKubernetesManifest(&config_generic.KubernetesManifest{
+			Name:        "api",
+			Namespace:   "{{.Configs.namespace}}",
+			Labels:      map[string]string{"app": "api", "context": "{{.ContextName}}"},
+			Annotations: map[string]string{"managed-by": "git-secrets"},
+		}, newTestRenderingContext(), &out)
+		assert.NoError(t, err)
+
+		manifests := decodeKubernetesManifests(t, out.Bytes())
+		assert.Len(t, manifests, 2)
+
+		secret, configMap := manifests[0], manifests[1]
+		assert.Equal(t, "v1", secret.ApiVersion)
+		assert.Equal(t, "Secret", secret.Kind)
+		assert.Equal(t, "Opaque", secret.Type)
+		assert.Equal(t, "api", secret.Metadata.Name)
+		assert.Equal(t, "prod-namespace", secret.Metadata.Namespace)
+		assert.Equal(t, map[string]string{"app": "api", "context": "prod"}, secret.Metadata.Labels)
+		assert.Equal(t, map[string]string{"managed-by": "git-secrets"}, secret.Metadata.Annotations)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("secret: value")), secret.Data["apiPassword"])
+		assert.Nil(t, secret.StringData)
+
+		assert.Equal(t, "v1", configMap.ApiVersion)
+		assert.Equal(t, "ConfigMap", configMap.Kind)
+		assert.Equal(t, secret.Metadata, configMap.Metadata)
+		assert.Equal(t, map[string]string{"namespace": "prod-namespace", "logLevel": "debug"}, configMap.Data)
+	})
+
+	t.Run("render string data and filter keys", func(t *testing.T) {
+		var out bytes.Buffer
+		err := executeKubernetesManifest(&config_generic.KubernetesManifest{
+			Name:       "api",
+			StringData: true,
+			SecretKeys: []string{"apiPassword"},
+			ConfigKeys: []string{"logLevel"},
+		}, newTestRenderingContext(), &out)
+		assert.NoError(t, err)
+
+		manifests := decodeKubernetesManifests(t, out.Bytes())
+		assert.Len(t, manifests, 2)
+		assert.Equal(t, map[string]string{"apiPassword": "secret: value"}, manifests[0].StringData)
+		assert.Nil(t, manifests[0].Data)
+		assert.Equal(t, map[string]string{"logLevel": "debug"}, manifests[1].Data)
+	})
+
+	t.Run("only render the requested kinds", func(t *testing.T) {
+		var out bytes.Buffer
+		err := executeKubernetesManifest(&config_generic.KubernetesManifest{
+			Name:  "api",
+			Kinds: []string{config_generic.KubernetesKindConfigMap},
+		}, newTestRenderingContext(), &out)
+		assert.NoError(t, err)
+
+		manifests := decodeKubernetesManifests(t, out.Bytes())
+		assert.Len(t, manifests, 1)
+		assert.Equal(t, "ConfigMap", manifests[0].Kind)
+	})
+
+	t.Run("fail if a filtered key does not exist", func(t *testing.T) {
+		var out bytes.Buffer
+		err := executeKubernetesManifest(&config_generic.KubernetesManifest{
+			Name:       "api",
+			SecretKeys: []string{"missing"},
+		}, newTestRenderingContext(), &out)
+		assert.Error(t, err)
+	})
+
+	t.Run("fail on invalid metadata templates", func(t *testing.T) {
+		var out bytes.Buffer
+		err := executeKubernetesManifest(&config_generic.KubernetesManifest{
+			Name: "{{.Configs.namespace",
+		}, newTestRenderingContext(), &out)
+		assert.Error(t, err)
+	})
+
+	t.Run("fail if the name renders empty", func(t *testing.T) {
+		var out bytes.Buffer
+		err := executeKubernetesManifest(&config_generic.KubernetesManifest{
+			Name: "{{.Configs.missing}}",
+		}, newTestRenderingContext(), &out)
+		assert.Error(t, err)
+	})
+}
+
+func TestRenderingEngine_ExecuteTemplateKubernetes(t *testing.T) {
+	_, engine := initRepository(t, FileRenderTestDefault, "default")
+	fileToRender := &config_generic.FileToRender{
+		FileOut: "out/k8s.yaml",
+		Kubernetes: &config_generic.KubernetesManifest{
+			Name: "database",
+		},
+	}
+
+	var bytesOut bytes.Buffer
+	_, errExecute := engine.ExecuteTemplate(fileToRender, &bytesOut)
+	assert.NoError(t, errExecute)
+
+	manifests := decodeKubernetesManifests(t, bytesOut.Bytes())
+	assert.Len(t, manifests, 2)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("em8toheGhieh0Thu1ahz9Lou2ucheeh6")), manifests[0].Data["databasePassword"])
+	assert.Equal(t, "3306", manifests[1].Data["databasePort"])
+}
@@ -2,10 +2,12 @@ package render
 
 import (
 	"encoding/base64"
+	"fmt"
 	"github.com/spf13/afero"
 	"github.com/tcnksm/go-gitconfig"
 	"html/template"
 	"io/fs"
+	"sort"
 )
 
 type AferoConvFs struct {
@@ -43,7 +45,7 @@ func templateFunctionGitConfig(args ...interface{}) interface{} {
 	return val
 }
 
-func createTemplate(fs afero.Fs, pathToFile string) (*template.Template, error) {
+func createTemplate(fs afero.Fs, pathToFile string, partials []string) (*template.Template, error) {
 
 	// create the new engine with file base name
 	tpl := template.New("")
@@ -51,11 +53,32 @@ func createTemplate(fs afero.Fs, pathToFile string) (*template.Template, error)
 	// add the template functions
 	tpl.Funcs(getTemplateFunctions())
 
-	tpl, err := tpl.ParseFS(AferoConvFs{aferoFs: fs}, pathToFile)
+	// parse the partials first so that the file can use the defined blocks
+	for _, partialPattern := range partials {
+		partialFiles, errGlob := afero.Glob(fs, partialPattern)
+		if errGlob != nil {
+			return nil, fmt.Errorf("invalid partial pattern %s: %s", partialPattern, errGlob.Error())
+		}
+		if len(partialFiles) == 0 {
+			return nil, fmt.Errorf("partial pattern %s does not match any file", partialPattern)
+		}
+		sort.Strings(partialFiles)
+		for _, partialFile := range partialFiles {
+			partialContents, errRead := afero.ReadFile(fs, partialFile)
+			if errRead != nil {
+				return nil, fmt.Errorf("could not read partial %s: %s", partialFile, errRead.Error())
+			}
+			// the template name is the path to the partial, parse errors therefore point at file and line
+			if _, errParse := tpl.New(partialFile).Parse(string(partialContents)); errParse != nil {
+				return nil, fmt.Errorf("could not parse partial %s: %s", partialFile, errParse.Error())
+			}
+		}
+	}
 
+	tpl, err := tpl.ParseFS(AferoConvFs{aferoFs: fs}, pathToFile)
 	if err != nil {
 		return nil, err
 	}
 
 	return tpl, err
-}
\ No newline at end of file
+}
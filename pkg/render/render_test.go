@@ -110,6 +110,19 @@ func TestRenderingEngine_ExecuteTemplate(t *testing.T) {
 
 }
 
+func TestRenderingEngine_ExecuteTemplateWithPartials(t *testing.T) {
+	_, engine := initRepository(t, FileRenderTestDefault, "default")
+	fileToRender := &config_generic.FileToRender{
+		FileIn:   "test_fs/templates/with-partials.env",
+		Partials: []string{"test_fs/templates/partials/*.tpl"},
+	}
+
+	var bytesOut bytes.Buffer
+	_, errExecute := engine.ExecuteTemplate(fileToRender, &bytesOut)
+	assert.NoError(t, errExecute)
+	assert.Equal(t, "# Created by git-secrets (default)\nDATABASE_PORT=3306\nDATABASE_PASSWORD=em8toheGhieh0Thu1ahz9Lou2ucheeh6\n", bytesOut.String())
+}
+
 func TestRenderingEngine_RenderFile(t *testing.T) {
 	_, engine := initRepository(t, FileRenderTestDefault, "default")
 	fileToRender := &config_generic.FileToRender{
@@ -140,16 +153,36 @@ func TestRenderingEngine_createTemplate(t *testing.T) {
 	fs := afero.FromIOFS{FS: testFiles}
 
 	t.Run("create template for existing file", func(t *testing.T) {
-		tpl, err := createTemplate(fs, "test_fs/templates/render-context.json")
+		tpl, err := createTemplate(fs, "test_fs/templates/render-context.json", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tpl)
 	})
 
 	t.Run("fail if file not exists", func(t *testing.T) {
-		tpl, err := createTemplate(fs, "test_fs/templates/missing-file")
+		tpl, err := createTemplate(fs, "test_fs/templates/missing-file", nil)
 		assert.Error(t, err)
 		assert.Nil(t, tpl)
 	})
 
+	t.Run("parse partials into the template", func(t *testing.T) {
+		tpl, err := createTemplate(fs, "test_fs/templates/with-partials.env", []string{"test_fs/templates/partials/*.tpl"})
+		assert.NoError(t, err)
+		assert.NotNil(t, tpl.Lookup("dbBlock"))
+		assert.NotNil(t, tpl.Lookup("header"))
+	})
+
+	t.Run("fail if partial pattern does not match", func(t *testing.T) {
+		tpl, err := createTemplate(fs, "test_fs/templates/with-partials.env", []string{"test_fs/templates/missing/*.tpl"})
+		assert.Error(t, err)
+		assert.Nil(t, tpl)
+	})
+
+	t.Run("point at the partial file and line on parse errors", func(t *testing.T) {
+		tpl, err := createTemplate(fs, "test_fs/templates/with-partials.env", []string{"test_fs/templates/partials-broken/*.tpl"})
+		assert.Nil(t, tpl)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "test_fs/templates/partials-broken/broken.tpl:2")
+	})
+
 
 }
@@ -0,0 +1,3 @@
+{{define "broken"}}
+{{if}}broken{{end}}
+{{end}}
@@ -0,0 +1,2 @@
+{{define "dbBlock"}}DATABASE_PORT={{.Configs.databasePort}}
+DATABASE_PASSWORD={{.Secrets.databasePassword}}{{end}}
@@ -0,0 +1 @@
+{{define "header"}}# Created by git-secrets ({{.ContextName}}){{end}}
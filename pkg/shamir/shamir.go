@@ -0,0 +1,135 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// MaxShares is the maximum number of shares, the x coordinate of a share is a single non zero byte
+const MaxShares = 255
+
+// expTable and logTable implement the multiplication in GF(2^8) using the generator 3 and the aes polynomial x^8 + x^4 + x^3 + x + 1
+var expTable, logTable = buildTables()
+
+func buildTables() (exp [510]byte, log [256]byte) {
+	value := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = value, value
+		log[value] = byte(i)
+		// multiply by the generator 3: value * 2 + value
+		doubled := value << 1
+		if value&0x80 != 0 {
+			doubled ^= 0x1b
+		}
+		value ^= doubled
+	}
+	return exp, log
+}
+
+func mul(a byte, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+func div(a byte, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
+
+// Split splits the secret into shares, any threshold of them can rebuild the secret
+// each share holds one byte per byte of the secret followed by its x coordinate
+func Split(secret []byte, shares int, threshold int) ([][]byte, error) {
+
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("the secret must not be empty")
+	}
+	if threshold < 2 {
+		return nil, fmt.Errorf("the threshold must be at least 2")
+	}
+	if shares < threshold {
+		return nil, fmt.Errorf("the number of shares must be at least the threshold %d", threshold)
+	}
+	if shares > MaxShares {
+		return nil, fmt.Errorf("the number of shares must be at most %d", MaxShares)
+	}
+
+	result := make([][]byte, shares)
+	for i := range result {
+		result[i] = make([]byte, len(secret)+1)
+		result[i][len(secret)] = byte(i + 1)
+	}
+
+	// every byte of the secret is the constant term of its own random polynomial of degree threshold - 1
+	coefficients := make([]byte, threshold)
+	for byteIndex, secretByte := range secret {
+		coefficients[0] = secretByte
+		if _, errRand := io.ReadFull(rand.Reader, coefficients[1:]); errRand != nil {
+			return nil, fmt.Errorf("could not create random coefficients: %s", errRand.Error())
+		}
+		for _, share := range result {
+			x := share[len(secret)]
+			// horner's method
+			y := byte(0)
+			for c := threshold - 1; c >= 0; c-- {
+				y = mul(y, x) ^ coefficients[c]
+			}
+			share[byteIndex] = y
+		}
+	}
+
+	return result, nil
+}
+
+// Combine rebuilds the secret from the shares using lagrange interpolation
+// combining less shares than the threshold or shares of different splits returns a wrong secret without an error
+func Combine(shares [][]byte) ([]byte, error) {
+
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("at least 2 shares are required")
+	}
+
+	shareLength := len(shares[0])
+	if shareLength < 2 {
+		return nil, fmt.Errorf("the shares are too short")
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]bool)
+	for i, share := range shares {
+		if len(share) != shareLength {
+			return nil, fmt.Errorf("the shares have different lengths")
+		}
+		x := share[shareLength-1]
+		if x == 0 {
+			return nil, fmt.Errorf("share %d is invalid", i+1)
+		}
+		if seen[x] {
+			return nil, fmt.Errorf("the share %d has been passed twice", x)
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+
+	secret := make([]byte, shareLength-1)
+	for byteIndex := range secret {
+		value := byte(0)
+		for i, share := range shares {
+			// the lagrange basis polynomial of share i evaluated at x = 0
+			basis := byte(1)
+			for j := range shares {
+				if i != j {
+					basis = mul(basis, div(xs[j], xs[i]^xs[j]))
+				}
+			}
+			value ^= mul(share[byteIndex], basis)
+		}
+		secret[byteIndex] = value
+	}
+
+	return secret, nil
+}
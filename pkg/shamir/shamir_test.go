@@ -0,0 +1,67 @@
+package shamir
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMul(t *testing.T) {
+	// the example of fips 197 section 4.2
+	assert.Equal(t, byte(0xc1), mul(0x57, 0x83))
+	assert.Equal(t, byte(0), mul(0, 0x83))
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(a), div(mul(byte(a), 0x53), 0x53))
+	}
+}
+
+func TestSplit(t *testing.T) {
+
+	secret := []byte("eeSaoghoh8oi9leed7hai4looK3jae1N")
+
+	t.Run("any threshold of shares should rebuild the secret", func(t *testing.T) {
+		shares, errSplit := Split(secret, 5, 3)
+		assert.NoError(t, errSplit)
+		assert.Len(t, shares, 5)
+		for _, combination := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var selected [][]byte
+			for _, index := range combination {
+				selected = append(selected, shares[index])
+			}
+			combined, errCombine := Combine(selected)
+			assert.NoError(t, errCombine)
+			assert.Equal(t, secret, combined)
+		}
+	})
+
+	t.Run("less shares than the threshold should not rebuild the secret", func(t *testing.T) {
+		shares, _ := Split(secret, 5, 3)
+		combined, errCombine := Combine(shares[:2])
+		assert.NoError(t, errCombine)
+		assert.NotEqual(t, secret, combined)
+	})
+
+	t.Run("it should validate the params", func(t *testing.T) {
+		_, errSplit := Split(secret, 5, 1)
+		assert.EqualError(t, errSplit, "the threshold must be at least 2")
+		_, errSplit = Split(secret, 2, 3)
+		assert.EqualError(t, errSplit, "the number of shares must be at least the threshold 3")
+		_, errSplit = Split(secret, 256, 3)
+		assert.EqualError(t, errSplit, "the number of shares must be at most 255")
+		_, errSplit = Split(nil, 5, 3)
+		assert.EqualError(t, errSplit, "the secret must not be empty")
+	})
+
+}
+
+func TestCombine(t *testing.T) {
+	shares, _ := Split([]byte("secret"), 3, 2)
+
+	_, errCombine := Combine(shares[:1])
+	assert.EqualError(t, errCombine, "at least 2 shares are required")
+
+	_, errCombine = Combine([][]byte{shares[0], shares[0]})
+	assert.EqualError(t, errCombine, "the share 1 has been passed twice")
+
+	_, errCombine = Combine([][]byte{shares[0], shares[1][1:]})
+	assert.EqualError(t, errCombine, "the shares have different lengths")
+}
@@ -2,7 +2,9 @@ package utility
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -30,3 +32,158 @@ func GetStagedFiles(all bool) ([]string, error) {
 	}
 	return filteredStagedFiles, nil
 }
+
+// GetGitRoot returns the top level directory of the git repository containing the directory, the working directory is used if dir is empty
+func GetGitRoot(dir string) (string, error) {
+	gitCommand := exec.Command("git", "rev-parse", "--show-toplevel")
+	gitCommand.Dir = dir
+	output, errExec := gitCommand.Output()
+	if errExec != nil {
+		return "", fmt.Errorf("could not resolve the git repository: %s / %s", errExec.Error(), string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetGitConfig sets the value of the local git config of the current repository
+func SetGitConfig(name string, value string) error {
+	output, errExec := exec.Command("git", "config", "--local", name, value).CombinedOutput()
+	if errExec != nil {
+		return fmt.Errorf("could not set git config %s: %s / %s", name, errExec.Error(), string(output))
+	}
+	return nil
+}
+
+// GetRevisionFiles returns the files below the path in the git revision, the paths are relative to the git root
+// the path may be a file or a directory, an empty list is returned if it does not exist
+func GetRevisionFiles(gitRoot string, revision string, pathInRevision string) ([]string, error) {
+	gitCommand := exec.Command("git", "ls-tree", "-r", "--name-only", revision, "--", pathInRevision)
+	gitCommand.Dir = gitRoot
+	output, errExec := gitCommand.Output()
+	if errExec != nil {
+		return nil, fmt.Errorf("could not list the files of revision %s: %s", revision, gitErrorMessage(errExec))
+	}
+	var files []string
+	for _, file := range strings.Split(strings.ReplaceAll(string(output), "\r\n", "\n"), "\n") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// GetRevisionFile returns the contents of the file in the git revision, the path is relative to the git root
+func GetRevisionFile(gitRoot string, revision string, file string) ([]byte, error) {
+	gitCommand := exec.Command("git", "show", fmt.Sprintf("%s:%s", revision, file))
+	gitCommand.Dir = gitRoot
+	output, errExec := gitCommand.Output()
+	if errExec != nil {
+		return nil, fmt.Errorf("could not read %s of revision %s: %s", file, revision, gitErrorMessage(errExec))
+	}
+	return output, nil
+}
+
+// gitErrorMessage returns the error output of a failed git command
+func gitErrorMessage(errExec error) string {
+	if exitError, isExitError := errExec.(*exec.ExitError); isExitError && len(exitError.Stderr) > 0 {
+		return strings.TrimSpace(string(exitError.Stderr))
+	}
+	return errExec.Error()
+}
+
+// AddGitAttributes adds the attribute to the patterns in the .gitattributes file of the git repository
+// patterns already having the attribute are skipped, the added lines are returned
+func AddGitAttributes(gitRoot string, patterns []string, attribute string) ([]string, error) {
+
+	attributesFile := filepath.Join(gitRoot, ".gitattributes")
+	contents, errRead := os.ReadFile(attributesFile)
+	if errRead != nil && !os.IsNotExist(errRead) {
+		return nil, fmt.Errorf("could not read %s: %s", attributesFile, errRead.Error())
+	}
+
+	existingLines := make(map[string]bool)
+	for _, line := range strings.Split(strings.ReplaceAll(string(contents), "\r\n", "\n"), "\n") {
+		existingLines[strings.Join(strings.Fields(line), " ")] = true
+	}
+
+	var addedLines []string
+	for _, pattern := range patterns {
+		line := fmt.Sprintf("%s %s", pattern, attribute)
+		if existingLines[line] {
+			continue
+		}
+		existingLines[line] = true
+		addedLines = append(addedLines, line)
+	}
+
+	if len(addedLines) == 0 {
+		return nil, nil
+	}
+
+	newContents := string(contents)
+	if len(newContents) > 0 && !strings.HasSuffix(newContents, "\n") {
+		newContents += "\n"
+	}
+	newContents += strings.Join(addedLines, "\n") + "\n"
+
+	if errWrite := os.WriteFile(attributesFile, []byte(newContents), 0644); errWrite != nil {
+		return nil, fmt.Errorf("could not write %s: %s", attributesFile, errWrite.Error())
+	}
+
+	return addedLines, nil
+
+}
+
+// GetGitIdentity returns the configured git user of the working directory as "name <email>", empty if it is not configured
+func GetGitIdentity() string {
+	name, _ := exec.Command("git", "config", "user.name").Output()
+	email, _ := exec.Command("git", "config", "user.email").Output()
+	identity := strings.TrimSpace(string(name))
+	if trimmedEmail := strings.TrimSpace(string(email)); trimmedEmail != "" {
+		identity = strings.TrimSpace(fmt.Sprintf("%s <%s>", identity, trimmedEmail))
+	}
+	return identity
+}
+
+// IsGitIgnored returns true if the file is ignored by the git repository containing it
+// the file does not need to exist, git check-ignore runs from the git root of its nearest existing directory
+func IsGitIgnored(path string) (bool, error) {
+
+	absPath, errAbs := filepath.Abs(path)
+	if errAbs != nil {
+		return false, fmt.Errorf("could not check if %s is ignored by git: %s", path, errAbs.Error())
+	}
+
+	existingDir := filepath.Dir(absPath)
+	for {
+		if _, errStat := os.Stat(existingDir); errStat == nil || filepath.Dir(existingDir) == existingDir {
+			break
+		}
+		existingDir = filepath.Dir(existingDir)
+	}
+
+	// the git root is returned without symlinks, so the path has to be resolved the same way to be relative to it
+	realDir, errEval := filepath.EvalSymlinks(existingDir)
+	if errEval != nil {
+		return false, fmt.Errorf("could not check if %s is ignored by git: %s", path, errEval.Error())
+	}
+	gitRoot, errRoot := GetGitRoot(realDir)
+	if errRoot != nil {
+		return false, fmt.Errorf("could not check if %s is ignored by git: %s", path, errRoot.Error())
+	}
+	relativePath, errRel := filepath.Rel(gitRoot, filepath.Join(realDir, strings.TrimPrefix(absPath, existingDir)))
+	if errRel != nil {
+		return false, fmt.Errorf("could not check if %s is ignored by git: %s", path, errRel.Error())
+	}
+
+	gitCommand := exec.Command("git", "check-ignore", "-q", "--", filepath.ToSlash(relativePath))
+	gitCommand.Dir = gitRoot
+	output, errExec := gitCommand.CombinedOutput()
+	if errExec == nil {
+		return true, nil
+	}
+	if exitError, isExitError := errExec.(*exec.ExitError); isExitError && exitError.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("could not check if %s is ignored by git: %s / %s", path, errExec.Error(), strings.TrimSpace(string(output)))
+
+}
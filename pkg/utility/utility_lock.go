@@ -0,0 +1,117 @@
+package utility
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// lockTimeout is the time to wait for a lock held by another process
+	lockTimeout = 10 * time.Second
+	// lockRetryInterval is the time between two attempts to acquire the lock
+	lockRetryInterval = 10 * time.Millisecond
+	// StaleLockAge is the age after which a lock file is considered to be left over by a crashed process
+	StaleLockAge = 30 * time.Second
+)
+
+// fileMutexes serializes the locks of the same file inside this process (path -> *sync.Mutex)
+var fileMutexes sync.Map
+
+// LockPath returns the path of the lock file guarding the file
+func LockPath(path string) string {
+	return path + ".lock"
+}
+
+// LockFile acquires the advisory lock of the file and returns the function to release it
+// the lock file is created exclusively so concurrent processes wait for each other
+func LockFile(fs afero.Fs, path string) (func(), error) {
+
+	mutexValue, _ := fileMutexes.LoadOrStore(path, &sync.Mutex{})
+	mutex := mutexValue.(*sync.Mutex)
+	mutex.Lock()
+
+	lockFile := LockPath(path)
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+
+		f, errCreate := fs.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0664)
+		if errCreate == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() {
+				_ = fs.Remove(lockFile)
+				mutex.Unlock()
+			}, nil
+		}
+
+		if !os.IsExist(errCreate) {
+			mutex.Unlock()
+			return nil, fmt.Errorf("could not lock %s: %s", path, errCreate.Error())
+		}
+
+		if identity, errIdentity := readLockIdentity(fs, lockFile); errIdentity == nil && time.Since(time.Unix(0, identity.modTime)) > StaleLockAge {
+			if removeStaleLock(fs, lockFile, identity) {
+				continue
+			}
+		}
+
+		if time.Now().After(deadline) {
+			mutex.Unlock()
+			return nil, fmt.Errorf("the file %s is locked by another process, remove %s if no other git-secrets process is running", path, lockFile)
+		}
+
+		time.Sleep(lockRetryInterval)
+
+	}
+
+}
+
+// lockIdentity identifies a lock file by the pid written to it and its modification time
+type lockIdentity struct {
+	pid     string
+	modTime int64
+}
+
+// readLockIdentity reads the identity of the lock file
+func readLockIdentity(fs afero.Fs, lockFile string) (lockIdentity, error) {
+	info, errStat := fs.Stat(lockFile)
+	if errStat != nil {
+		return lockIdentity{}, errStat
+	}
+	contents, errRead := afero.ReadFile(fs, lockFile)
+	if errRead != nil {
+		return lockIdentity{}, errRead
+	}
+	return lockIdentity{pid: strings.TrimSpace(string(contents)), modTime: info.ModTime().UnixNano()}, nil
+}
+
+// removeStaleLock removes the lock file if it still is the stale lock, returns true if it has been removed
+// the identity is compared while holding a break lock, so a lock created by another process after the stale one has been removed is never removed
+func removeStaleLock(fs afero.Fs, lockFile string, stale lockIdentity) bool {
+
+	breakFile := lockFile + ".break"
+	f, errCreate := fs.OpenFile(breakFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0664)
+	if errCreate != nil {
+		// another process is removing the stale lock, a break lock left by a crashed process is removed once it is stale itself
+		if info, errStat := fs.Stat(breakFile); errStat == nil && time.Since(info.ModTime()) > StaleLockAge {
+			_ = fs.Remove(breakFile)
+		}
+		return false
+	}
+	_ = f.Close()
+	defer func() {
+		_ = fs.Remove(breakFile)
+	}()
+
+	current, errIdentity := readLockIdentity(fs, lockFile)
+	if errIdentity != nil || current != stale {
+		return false
+	}
+	return fs.Remove(lockFile) == nil
+
+}
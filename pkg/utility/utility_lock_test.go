@@ -0,0 +1,49 @@
+package utility
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRemoveStaleLock(t *testing.T) {
+
+	staleTime := time.Now().Add(-2 * StaleLockAge)
+
+	createStaleLock := func(fs afero.Fs) lockIdentity {
+		assert.NoError(t, afero.WriteFile(fs, "config.json.lock", []byte("1\n"), 0664))
+		assert.NoError(t, fs.Chtimes("config.json.lock", staleTime, staleTime))
+		identity, errIdentity := readLockIdentity(fs, "config.json.lock")
+		assert.NoError(t, errIdentity)
+		return identity
+	}
+
+	t.Run("it should remove the stale lock", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.True(t, removeStaleLock(fs, "config.json.lock", createStaleLock(fs)))
+		exists, _ := afero.Exists(fs, "config.json.lock")
+		assert.False(t, exists)
+		exists, _ = afero.Exists(fs, "config.json.lock.break")
+		assert.False(t, exists)
+	})
+
+	t.Run("it should not remove a lock created after the stale lock has been removed", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		stale := createStaleLock(fs)
+		assert.NoError(t, afero.WriteFile(fs, "config.json.lock", []byte("2\n"), 0664))
+		assert.False(t, removeStaleLock(fs, "config.json.lock", stale))
+		contents, _ := afero.ReadFile(fs, "config.json.lock")
+		assert.Equal(t, "2\n", string(contents))
+	})
+
+	t.Run("it should not remove the stale lock while another process removes it", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		stale := createStaleLock(fs)
+		assert.NoError(t, afero.WriteFile(fs, "config.json.lock.break", []byte{}, 0664))
+		assert.False(t, removeStaleLock(fs, "config.json.lock", stale))
+		exists, _ := afero.Exists(fs, "config.json.lock")
+		assert.True(t, exists)
+	})
+
+}
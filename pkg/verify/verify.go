@@ -0,0 +1,159 @@
+package verify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"sort"
+	"sync"
+)
+
+type Reason string
+
+const (
+	ReasonKeyMismatch   Reason = "key mismatch"
+	ReasonWrongKey      Reason = "wrong key"
+	ReasonInvalidBase64 Reason = "bad base64"
+	ReasonTruncated     Reason = "truncated payload"
+	ReasonUnresolved    Reason = "unresolved secret"
+	ReasonInvalidKey    Reason = "invalid key"
+	ReasonUnknown       Reason = "unknown"
+)
+
+// reasons holds the reasons in the order they are printed
+var reasons = []Reason{ReasonKeyMismatch, ReasonUnresolved, ReasonInvalidKey, ReasonWrongKey, ReasonInvalidBase64, ReasonTruncated, ReasonUnknown}
+
+// Failure describes a secret which could not be decrypted
+type Failure struct {
+	Context string
+	Secret  string
+	Reason  Reason
+	Err     error
+}
+
+// Result holds the total number of verified secrets and the failures sorted by context and secret name
+type Result struct {
+	Total    int
+	Failures []*Failure
+}
+
+// ReasonOf returns why a secret could not be decrypted
+func ReasonOf(err error) Reason {
+	switch {
+	case errors.Is(err, encryption.ErrKeyMismatch):
+		return ReasonKeyMismatch
+	case errors.Is(err, encryption.ErrUnresolvedSecret):
+		return ReasonUnresolved
+	case errors.Is(err, encryption.ErrInvalidKey):
+		return ReasonInvalidKey
+	case errors.Is(err, encryption.ErrWrongKey):
+		return ReasonWrongKey
+	case errors.Is(err, encryption.ErrInvalidBase64):
+		return ReasonInvalidBase64
+	case errors.Is(err, encryption.ErrTruncatedPayload):
+		return ReasonTruncated
+	default:
+		return ReasonUnknown
+	}
+}
+
+// Secrets returns the secrets defined by the contexts and their ancestors, all secrets if no context is passed
+func Secrets(repository *config_generic.Repository, contextNames []string) ([]*config_generic.Secret, error) {
+
+	var contexts []*config_generic.Context
+	if len(contextNames) == 0 {
+		contexts = repository.GetContexts()
+	}
+	for _, contextName := range contextNames {
+		context := repository.GetContext(contextName)
+		if context == nil {
+			return nil, fmt.Errorf("the context %s does not exist", contextName)
+		}
+		contexts = append(contexts, context.Chain()...)
+	}
+
+	var secrets []*config_generic.Secret
+	visited := make(map[string]bool)
+	for _, context := range contexts {
+		if visited[context.Name] {
+			continue
+		}
+		visited[context.Name] = true
+		secrets = append(secrets, repository.GetSecretsByContext(context.Name)...)
+	}
+
+	return secrets, nil
+
+}
+
+// Verify decrypts the secrets through their origin context using the number of parallel workers
+func Verify(secrets []*config_generic.Secret, workers int) *Result {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	result := &Result{Total: len(secrets)}
+	queue := make(chan *config_generic.Secret)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for secret := range queue {
+				if _, errDecode := secret.Decode(); errDecode != nil {
+					mutex.Lock()
+					result.Failures = append(result.Failures, &Failure{Context: secret.OriginContext.Name, Secret: secret.Name, Reason: ReasonOf(errDecode), Err: errDecode})
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, secret := range secrets {
+		queue <- secret
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(result.Failures, func(i, j int) bool {
+		if result.Failures[i].Context != result.Failures[j].Context {
+			return result.Failures[i].Context < result.Failures[j].Context
+		}
+		return result.Failures[i].Secret < result.Failures[j].Secret
+	})
+
+	return result
+
+}
+
+// FormatText prints the failures grouped by their reason followed by a summary
+func (r *Result) FormatText() []byte {
+
+	var out bytes.Buffer
+
+	for _, reason := range reasons {
+		var group []*Failure
+		for _, failure := range r.Failures {
+			if failure.Reason == reason {
+				group = append(group, failure)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&out, "%s (%d):\n", reason, len(group))
+		for _, failure := range group {
+			fmt.Fprintf(&out, "  %s.%s: %s\n", failure.Context, failure.Secret, failure.Err.Error())
+		}
+	}
+
+	fmt.Fprintf(&out, "%d of %d secrets decrypted\n", r.Total-len(r.Failures), r.Total)
+	return out.Bytes()
+
+}
@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"embed"
+	"fmt"
+	config_generic "github.com/benammann/git-secrets/pkg/config/generic"
+	global_config "github.com/benammann/git-secrets/pkg/config/global"
+	"github.com/benammann/git-secrets/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const GlobalSecretKey = "gitSecretsTest"
+const GlobalSecretValue = "eeSaoghoh8oi9leed7hai4looK3jae1N"
+
+const TestFile = "verify-test.json"
+
+//go:embed test_fs
+var testFiles embed.FS
+
+func parseTestDocument(t *testing.T, overwrittenSecrets map[string]string) *config_generic.Repository {
+	contents, errRead := testFiles.ReadFile("test_fs/" + TestFile)
+	assert.NoError(t, errRead)
+	globalConfig := global_config.NewGlobalConfigProvider(global_config.NewMemoryStorageProvider())
+	repository, errParse := config_generic.ParseDocument(TestFile, contents, globalConfig, overwrittenSecrets)
+	assert.NoError(t, errParse)
+	return repository
+}
+
+func failureReasons(result *Result) map[string]Reason {
+	reasons := make(map[string]Reason)
+	for _, failure := range result.Failures {
+		reasons[failure.Context+"."+failure.Secret] = failure.Reason
+	}
+	return reasons
+}
+
+func TestVerify(t *testing.T) {
+
+	t.Run("it should group the failures by reason", func(t *testing.T) {
+		repository := parseTestDocument(t, map[string]string{GlobalSecretKey: GlobalSecretValue})
+		secrets, errSecrets := Secrets(repository, nil)
+		assert.NoError(t, errSecrets)
+
+		result := Verify(secrets, 4)
+		assert.Equal(t, 6, result.Total)
+		assert.Equal(t, map[string]Reason{
+			"default.apiKey":        ReasonWrongKey,
+			"default.apiToken":      ReasonInvalidBase64,
+			"default.webhookSecret": ReasonTruncated,
+			"prod.databasePassword": ReasonUnresolved,
+		}, failureReasons(result))
+
+		text := string(result.FormatText())
+		assert.Contains(t, text, "unresolved secret (1):\n  prod.databasePassword: could not resolve secret: env variable GIT_SECRETS_VERIFY_TEST_KEY is empty\n")
+		assert.Contains(t, text, "wrong key (1):\n  default.apiKey: ")
+		assert.Contains(t, text, "2 of 6 secrets decrypted\n")
+	})
+
+	t.Run("it should resolve the keys from env variables", func(t *testing.T) {
+		t.Setenv("GIT_SECRETS_VERIFY_TEST_KEY", GlobalSecretValue)
+		repository := parseTestDocument(t, map[string]string{GlobalSecretKey: GlobalSecretValue})
+		secrets, _ := Secrets(repository, []string{"prod"})
+		result := Verify(secrets, 2)
+		assert.Equal(t, 5, result.Total)
+		assert.NotContains(t, failureReasons(result), "prod.databasePassword")
+	})
+
+	t.Run("it should report unresolved secrets without overrides", func(t *testing.T) {
+		repository := parseTestDocument(t, map[string]string{})
+		secrets, _ := Secrets(repository, []string{"staging"})
+		result := Verify(secrets, 1)
+		assert.Equal(t, map[string]Reason{
+			"default.apiKey":           ReasonUnresolved,
+			"default.apiToken":         ReasonInvalidBase64,
+			"default.databasePassword": ReasonUnresolved,
+			"default.webhookSecret":    ReasonUnresolved,
+			"staging.databasePassword": ReasonUnresolved,
+		}, failureReasons(result))
+	})
+
+	t.Run("it should fail on unknown contexts", func(t *testing.T) {
+		_, errSecrets := Secrets(parseTestDocument(t, map[string]string{}), []string{"missing"})
+		assert.EqualError(t, errSecrets, "the context missing does not exist")
+	})
+
+}
+
+func TestReasonOf(t *testing.T) {
+	assert.Equal(t, ReasonKeyMismatch, ReasonOf(fmt.Errorf("%w: the secret has the fingerprint dd42c1f78b389d88 but the context expects 0123456789abcdef", encryption.ErrKeyMismatch)))
+	assert.Equal(t, ReasonWrongKey, ReasonOf(fmt.Errorf("%w: cipher: message authentication failed", encryption.ErrWrongKey)))
+	assert.Equal(t, ReasonUnknown, ReasonOf(fmt.Errorf("something else")))
+}
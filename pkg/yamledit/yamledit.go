@@ -0,0 +1,198 @@
+package yamledit
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/benammann/git-secrets/pkg/jsonedit"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// Document edits a yaml document in place
+// only the touched values are rewritten, comments, the style of untouched values, the order of the keys and unknown keys are kept
+type Document struct {
+	root *yaml.Node
+}
+
+// NewDocument creates a new editable document from the yaml source
+func NewDocument(source []byte) (*Document, error) {
+	var root yaml.Node
+	if errParse := yaml.Unmarshal(source, &root); errParse != nil {
+		return nil, fmt.Errorf("could not parse yaml: %s", errParse.Error())
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the yaml document must contain a mapping")
+	}
+	return &Document{root: &root}, nil
+}
+
+// Bytes returns the edited document
+func (d *Document) Bytes() ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if errEncode := encoder.Encode(d.root); errEncode != nil {
+		return nil, fmt.Errorf("could not encode yaml: %s", errEncode.Error())
+	}
+	return out.Bytes(), nil
+}
+
+// ApplyChanges compares two decoded json values and applies the differences to the document
+// keys only present in before are deleted, sequences which only got new items are appended
+func (d *Document) ApplyChanges(before interface{}, after interface{}) error {
+	return jsonedit.ApplyChanges(d, before, after)
+}
+
+// Set sets the value at the given path, missing parent mappings are created
+func (d *Document) Set(path []string, value interface{}) error {
+
+	if len(path) == 0 {
+		return d.replace(d.root.Content[0], value)
+	}
+
+	var keyNode *yaml.Node
+	current := d.root.Content[0]
+	for i, key := range path {
+		if current.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", strings.Join(path[:i], "."))
+		}
+		keyNode = memberKey(current, key)
+		if keyNode == nil {
+			return insertMember(current, key, nestedValue(path[i+1:], value))
+		}
+		current = member(current, key)
+	}
+
+	if errReplace := d.replace(current, value); errReplace != nil {
+		return errReplace
+	}
+
+	// the line comment of a mapping or sequence is only written next to its key
+	if current.Kind != yaml.ScalarNode && current.LineComment != "" && keyNode.LineComment == "" {
+		keyNode.LineComment, current.LineComment = current.LineComment, ""
+	}
+
+	return nil
+
+}
+
+// Append appends the value to the sequence at the given path, the sequence is created if it is missing
+func (d *Document) Append(path []string, value interface{}) error {
+
+	sequence := lookup(d.root.Content[0], path)
+	if sequence == nil {
+		return d.Set(path, []interface{}{value})
+	}
+	if sequence.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s is not a sequence", strings.Join(path, "."))
+	}
+
+	item, errEncode := encodeNode(value)
+	if errEncode != nil {
+		return errEncode
+	}
+	sequence.Content = append(sequence.Content, item)
+	return nil
+
+}
+
+// Delete removes the key at the given path, nothing happens if the key does not exist
+func (d *Document) Delete(path []string) error {
+
+	if len(path) == 0 {
+		return fmt.Errorf("can not delete the document root")
+	}
+
+	parent := lookup(d.root.Content[0], path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == path[len(path)-1] {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			break
+		}
+	}
+
+	return nil
+
+}
+
+// replace replaces the node by the encoded value, the comments of the node are kept
+func (d *Document) replace(target *yaml.Node, value interface{}) error {
+	replacement, errEncode := encodeNode(value)
+	if errEncode != nil {
+		return errEncode
+	}
+	replacement.HeadComment = target.HeadComment
+	replacement.LineComment = target.LineComment
+	replacement.FootComment = target.FootComment
+	*target = *replacement
+	return nil
+}
+
+// lookup returns the node at the path or nil if it does not exist
+func lookup(node *yaml.Node, path []string) *yaml.Node {
+	current := node
+	for _, key := range path {
+		if current.Kind != yaml.MappingNode {
+			return nil
+		}
+		current = member(current, key)
+		if current == nil {
+			return nil
+		}
+	}
+	return current
+}
+
+// member returns the value of the key in the mapping or nil if it does not exist
+func member(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// memberKey returns the key node of the key in the mapping or nil if it does not exist
+func memberKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i]
+		}
+	}
+	return nil
+}
+
+// insertMember appends the key and the encoded value to the mapping
+func insertMember(mapping *yaml.Node, key string, value interface{}) error {
+	valueNode, errEncode := encodeNode(value)
+	if errEncode != nil {
+		return errEncode
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	// an empty flow mapping like {} is written as block mapping once it has members
+	mapping.Style = 0
+	mapping.Content = append(mapping.Content, keyNode, valueNode)
+	return nil
+}
+
+// encodeNode encodes the value as block yaml node
+func encodeNode(value interface{}) (*yaml.Node, error) {
+	var node yaml.Node
+	if errEncode := node.Encode(value); errEncode != nil {
+		return nil, fmt.Errorf("could not encode yaml: %s", errEncode.Error())
+	}
+	return &node, nil
+}
+
+// nestedValue wraps the value into mappings for the remaining path
+func nestedValue(path []string, value interface{}) interface{} {
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+	return value
+}
@@ -0,0 +1,128 @@
+package yamledit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testDocument = `# head comment
+b: keep # line comment
+a:
+  x: 1
+  since: 2030-01-01
+empty: {}
+list:
+  - one
+unknown: true
+`
+
+func newTestDocument(t *testing.T, source string) *Document {
+	document, errParse := NewDocument([]byte(source))
+	assert.NoError(t, errParse)
+	return document
+}
+
+func documentString(t *testing.T, document *Document) string {
+	output, errEncode := document.Bytes()
+	assert.NoError(t, errEncode)
+	return string(output)
+}
+
+func TestNewDocument(t *testing.T) {
+	_, errParse := NewDocument([]byte("a: [\n"))
+	assert.Error(t, errParse)
+	_, errParse = NewDocument([]byte("- one\n"))
+	assert.Error(t, errParse)
+	document := newTestDocument(t, testDocument)
+	assert.Equal(t, testDocument, documentString(t, document))
+}
+
+func TestDocument_Set(t *testing.T) {
+
+	t.Run("replace an existing value and keep its comment", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"b"}, "changed"))
+		assert.Contains(t, documentString(t, document), "# head comment\nb: changed # line comment\n")
+	})
+
+	t.Run("keep the line comment of a value replaced by a mapping", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"b"}, map[string]interface{}{"value": "keep"}))
+		assert.Contains(t, documentString(t, document), "# head comment\nb: # line comment\n  value: keep\n")
+	})
+
+	t.Run("insert a key after the last member", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"a", "y"}, "new"))
+		assert.Contains(t, documentString(t, document), "  since: 2030-01-01\n  y: new\nempty: {}\n")
+	})
+
+	t.Run("insert a key into an empty mapping", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"empty", "key"}, "value"))
+		assert.Contains(t, documentString(t, document), "empty:\n  key: value\n")
+	})
+
+	t.Run("create missing parents", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.NoError(t, document.Set([]string{"new", "nested", "key"}, "value"))
+		assert.Contains(t, documentString(t, document), "new:\n  nested:\n    key: value\n")
+	})
+
+	t.Run("fail if a parent is not a mapping", func(t *testing.T) {
+		document := newTestDocument(t, testDocument)
+		assert.Error(t, document.Set([]string{"b", "key"}, "value"))
+	})
+
+}
+
+func TestDocument_Append(t *testing.T) {
+	document := newTestDocument(t, testDocument)
+	assert.NoError(t, document.Append([]string{"list"}, "two"))
+	assert.NoError(t, document.Append([]string{"new"}, "one"))
+	assert.Error(t, document.Append([]string{"b"}, "one"))
+	assert.Contains(t, documentString(t, document), "list:\n  - one\n  - two\n")
+	assert.Contains(t, documentString(t, document), "new:\n  - one\n")
+}
+
+func TestDocument_Delete(t *testing.T) {
+	document := newTestDocument(t, testDocument)
+	assert.NoError(t, document.Delete([]string{"a", "x"}))
+	assert.NoError(t, document.Delete([]string{"missing", "key"}))
+	assert.Error(t, document.Delete([]string{}))
+	assert.Equal(t, `# head comment
+b: keep # line comment
+a:
+  since: 2030-01-01
+empty: {}
+list:
+  - one
+unknown: true
+`, documentString(t, document))
+}
+
+func TestDocument_ApplyChanges(t *testing.T) {
+	document := newTestDocument(t, testDocument)
+	before := map[string]interface{}{
+		"b":    "keep",
+		"a":    map[string]interface{}{"x": 1.0},
+		"list": []interface{}{"one"},
+	}
+	after := map[string]interface{}{
+		"b":    "keep",
+		"a":    map[string]interface{}{"x": 2.0},
+		"list": []interface{}{"one", "two"},
+	}
+	assert.NoError(t, document.ApplyChanges(before, after))
+	assert.Equal(t, `# head comment
+b: keep # line comment
+a:
+  x: 2
+  since: 2030-01-01
+empty: {}
+list:
+  - one
+  - two
+unknown: true
+`, documentString(t, document))
+}
@@ -12,6 +12,7 @@ var schemaDefinitions embed.FS
 type FileName string
 
 var V1 FileName = "v1.json"
+var V2 FileName = "v2.json"
 
 // GetSchemaContents reads the schema contents from the embed fs
 func GetSchemaContents(schemaFileName FileName) []byte {